@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"sync"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// BodyComparator compares the expected body of the test (from response or responseFiles)
+// with the actual response and returns the differences as errors, which are counted
+// as the body category; nil means the bodies are equivalent
+type BodyComparator func(expected string, result *models.Result) []error
+
+var (
+	bodyComparatorsMu sync.RWMutex
+	bodyComparators   = map[string]BodyComparator{}
+)
+
+// RegisterBodyComparator makes the comparator available to the tests by the name given in bodyComparator,
+// registering the comparator with the same name replaces the previous one
+func RegisterBodyComparator(name string, comparator BodyComparator) {
+	if comparator == nil {
+		panic("checker: nil body comparator " + name)
+	}
+	bodyComparatorsMu.Lock()
+	defer bodyComparatorsMu.Unlock()
+	bodyComparators[name] = comparator
+}
+
+// GetBodyComparator returns the comparator registered with the name
+func GetBodyComparator(name string) (BodyComparator, bool) {
+	bodyComparatorsMu.RLock()
+	defer bodyComparatorsMu.RUnlock()
+	comparator, ok := bodyComparators[name]
+	return comparator, ok
+}
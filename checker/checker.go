@@ -5,3 +5,10 @@ import "github.com/lamoda/gonkey/models"
 type CheckerInterface interface {
 	Check(models.TestInterface, *models.Result) ([]error, error)
 }
+
+// CategorizedChecker is implemented by the checkers whose errors are counted
+// by category in the summary
+type CategorizedChecker interface {
+	CheckerInterface
+	Category() models.ErrorCategory
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/lamoda/gonkey/checker"
 	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/internal/files"
 	"github.com/lamoda/gonkey/models"
 )
 
@@ -19,23 +21,56 @@ func NewChecker() checker.CheckerInterface {
 	return &ResponseBodyChecker{}
 }
 
+func (c *ResponseBodyChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBody
+}
+
 func (c *ResponseBodyChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
 	var errs []error
 	var foundResponse bool
 	// test response with the expected response body
 	if expectedBody, ok := t.GetResponse(result.ResponseStatusCode); ok {
 		foundResponse = true
-		// is the response JSON document?
-		if strings.Contains(result.ResponseContentType, "json") && expectedBody != "" {
-			checkErrs, err := compareJsonBody(t, expectedBody, result)
-			if err != nil {
-				return nil, err
-			}
-			errs = append(errs, checkErrs...)
-		} else {
-			// compare bodies as leaf nodes
-			errs = append(errs, compare.Compare(expectedBody, result.ResponseBody, compare.CompareParams{})...)
+		checkErrs, err := compareBody(t, expectedBody, result)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, checkErrs...)
+	}
+	// test response with any of the golden files
+	if filenames, ok := t.GetResponseFiles(result.ResponseStatusCode); ok {
+		foundResponse = true
+		checkErrs, err := compareFiles(t, filenames, result)
+		if err != nil {
+			return nil, err
 		}
+		errs = append(errs, checkErrs...)
+	}
+	// the body may be checked with its hash, regexp, required fields, keys, structure, NDJSON lines,
+	// JSON Schema or merge base instead
+	if _, ok := t.GetResponseBodyHash(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetResponseBodyMatches(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetRequiredFields(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetResponseKeys(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetStructureSnapshot(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetResponseNDJSON(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetResponseSchema(result.ResponseStatusCode); ok {
+		foundResponse = true
+	}
+	if _, ok := t.GetResponseMergeBase(result.ResponseStatusCode); ok {
+		foundResponse = true
 	}
 	if !foundResponse {
 		err := fmt.Errorf("server responded with status %d", result.ResponseStatusCode)
@@ -44,6 +79,52 @@ func (c *ResponseBodyChecker) Check(t models.TestInterface, result *models.Resul
 	return errs, nil
 }
 
+func compareBody(t models.TestInterface, expectedBody string, result *models.Result) ([]error, error) {
+	// the registered comparator replaces the default comparison
+	if name := t.BodyComparator(); name != "" {
+		comparator, ok := checker.GetBodyComparator(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown body comparator %s", name)
+		}
+		return comparator(expectedBody, result), nil
+	}
+	// is the response JSON document?
+	if strings.Contains(result.ResponseContentType, "json") && expectedBody != "" {
+		return compareJsonBody(t, expectedBody, result)
+	}
+	// compare bodies as leaf nodes
+	return compare.Compare(expectedBody, result.ResponseBody, compare.CompareParams{}), nil
+}
+
+// compareFiles passes if the response matches any of the files,
+// otherwise reports the differences with the closest one
+func compareFiles(t models.TestInterface, filenames []string, result *models.Result) ([]error, error) {
+	var closest string
+	var closestErrs []error
+	for _, file := range filenames {
+		data, err := files.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read response file %s: %s", file, err.Error())
+		}
+		errs, err := compareBody(t, string(data), result)
+		if err != nil {
+			return nil, err
+		}
+		if len(errs) == 0 {
+			return nil, nil
+		}
+		if closestErrs == nil || len(errs) < len(closestErrs) {
+			closest, closestErrs = file, errs
+		}
+	}
+	if closestErrs == nil {
+		return nil, nil
+	}
+
+	errs := []error{fmt.Errorf("response does not match any of %d files, the closest is %s", len(filenames), closest)}
+	return append(errs, closestErrs...), nil
+}
+
 func compareJsonBody(t models.TestInterface, expectedBody string, result *models.Result) ([]error, error) {
 	// decode expected body
 	var expected interface{}
@@ -57,8 +138,8 @@ func compareJsonBody(t models.TestInterface, expectedBody string, result *models
 	}
 
 	// decode actual body
-	var actual interface{}
-	if err := json.Unmarshal([]byte(result.ResponseBody), &actual); err != nil {
+	actual, err := decodeActualBody(result.ResponseBody, strings.Contains(expectedBody, "$matchInt"))
+	if err != nil {
 		return []error{errors.New("could not parse response")}, nil
 	}
 
@@ -66,7 +147,26 @@ func compareJsonBody(t models.TestInterface, expectedBody string, result *models
 		IgnoreValues:         !t.NeedsCheckingValues(),
 		IgnoreArraysOrdering: t.IgnoreArraysOrdering(),
 		DisallowExtraFields:  t.DisallowExtraFields(),
+		Wildcard:             t.Wildcard(),
 	}
 
 	return compare.Compare(expected, actual, params), nil
 }
+
+// decodeActualBody keeps the numbers as serialized with useNumber to tell integers from floats
+func decodeActualBody(body string, useNumber bool) (interface{}, error) {
+	var actual interface{}
+	if !useNumber {
+		err := json.Unmarshal([]byte(body), &actual)
+		return actual, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&actual); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level value")
+	}
+	return actual, nil
+}
@@ -0,0 +1,113 @@
+package response_body
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newFilesTest() *yaml_file.Test {
+	return &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseFiles: map[int][]string{200: {
+				filepath.Join("testdata", "new.json"),
+				filepath.Join("testdata", "paid.json"),
+			}},
+		},
+	}
+}
+
+func TestCheckShouldPassWhenAnyFileMatches(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"status": "paid", "paidAt": "2020-01-01", "items": [1]}`,
+	}
+
+	errs, err := NewChecker().Check(newFilesTest(), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportClosestFile(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"status": "paid", "paidAt": "2020-01-01", "items": [2]}`,
+	}
+
+	errs, err := NewChecker().Check(newFilesTest(), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Len(t, errs, 2)
+	assert.Equal(t, errors.New("response does not match any of 2 files, the closest is testdata/paid.json"), errs[0])
+}
+
+func TestCheckShouldFailOnMissingFile(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseFiles: map[int][]string{200: {filepath.Join("testdata", "missing.json")}},
+		},
+	}
+
+	_, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200})
+
+	assert.Error(t, err)
+}
+
+func TestCheckShouldUseRegisteredBodyComparator(t *testing.T) {
+	checker.RegisterBodyComparator("ignore-spaces", func(expected string, result *models.Result) []error {
+		if strings.Join(strings.Fields(expected), "") != strings.Join(strings.Fields(result.ResponseBody), "") {
+			return []error{errors.New("bodies differ")}
+		}
+		return nil
+	})
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{BodyComparatorVal: "ignore-spaces"},
+		Responses:      map[int]string{200: "<book>\n  <id>1</id>\n</book>"},
+	}
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "<book><id>1</id></book>"})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "<book><id>2</id></book>"})
+	assert.NoError(t, err)
+	assert.Equal(t, []error{errors.New("bodies differ")}, errs)
+}
+
+func TestCheckShouldFailOnUnknownBodyComparator(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{BodyComparatorVal: "unknown"},
+		Responses:      map[int]string{200: "{}"},
+	}
+
+	_, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "{}"})
+	assert.EqualError(t, err, "unknown body comparator unknown")
+}
+
+func TestCheckShouldDecompressGzippedFiles(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseFiles: map[int][]string{200: {filepath.Join("testdata", "paid.json.gz")}},
+		},
+	}
+	result := &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"status": "paid", "paidAt": "2020-01-01", "items": [1]}`,
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
@@ -0,0 +1,111 @@
+package response_body_forbidden
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+// contextLength limits the part of the body shown around the forbidden text
+const contextLength = 30
+
+type ResponseBodyForbiddenChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseBodyForbiddenChecker{}
+}
+
+func (c *ResponseBodyForbiddenChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBodyForbidden
+}
+
+// Check reports the regular expressions found in the raw response body of any status,
+// the found text is redacted not to disclose it in the reports either
+func (c *ResponseBodyForbiddenChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	patterns := t.GetResponseBodyForbidden()
+	rxs := make([]*regexp.Regexp, len(patterns))
+	compileErrs := make([]error, len(patterns))
+	var found [][]int
+	for i, pattern := range patterns {
+		rxs[i], compileErrs[i] = regexp.Compile(pattern)
+		if compileErrs[i] == nil {
+			found = append(found, rxs[i].FindAllStringIndex(result.ResponseBody, -1)...)
+		}
+	}
+	found = mergeRanges(found)
+
+	var errs []error
+	for i, pattern := range patterns {
+		if compileErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("invalid forbidden response body regexp %s: %s", pattern, compileErrs[i].Error()))
+			continue
+		}
+		if loc := rxs[i].FindStringIndex(result.ResponseBody); loc != nil {
+			errs = append(errs, fmt.Errorf("response body contains forbidden %s: %q",
+				pattern, redactedSnippet(result.ResponseBody, loc[0], loc[1], found)))
+		}
+	}
+	return errs, nil
+}
+
+// mergeRanges sorts the ranges of the found texts and joins the overlapping ones
+func mergeRanges(ranges [][]int) [][]int {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	var res [][]int
+	for _, r := range ranges {
+		if last := len(res) - 1; last >= 0 && r[0] <= res[last][1] {
+			if r[1] > res[last][1] {
+				res[last][1] = r[1]
+			}
+			continue
+		}
+		res = append(res, []int{r[0], r[1]})
+	}
+	return res
+}
+
+// redactedSnippet returns the text around the found one, every found text of the snippet
+// (the sorted ranges of all the forbidden expressions) is replaced with [REDACTED]
+func redactedSnippet(body string, start, end int, found [][]int) string {
+	from, to := start, end
+	for i := 0; i < contextLength && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(body[:from])
+		from -= size
+	}
+	for i := 0; i < contextLength && to < len(body); i++ {
+		_, size := utf8.DecodeRuneInString(body[to:])
+		to += size
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := from
+	for _, r := range found {
+		if r[1] <= from || r[0] >= to {
+			continue
+		}
+		if r[0] > pos {
+			b.WriteString(body[pos:r[0]])
+		}
+		b.WriteString("[REDACTED]")
+		pos = r[1]
+	}
+	if pos < to {
+		b.WriteString(body[pos:to])
+	}
+	if to < len(body) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
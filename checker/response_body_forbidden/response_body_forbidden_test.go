@@ -0,0 +1,81 @@
+package response_body_forbidden
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldPassWithoutForbiddenText(t *testing.T) {
+	result := &models.Result{ResponseStatusCode: 500, ResponseBody: `{"error": "internal error"}`}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyForbidden: []string{`\.internal\b`, `(?i)traceback`},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckShouldReportRedactedText(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 500,
+		ResponseBody: `{"error": "connection to db-master.internal refused", "trace": "goroutine 1 [running]:` +
+			` main.main() /app/main.go:12 +0x20 and a long tail of the stack trace"}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyForbidden: []string{`[\w-]+\.internal\b`, `goroutine \d+`, `password`},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{
+		errors.New(`response body contains forbidden [\w-]+\.internal\b: "{\"error\": \"connection to [REDACTED] refused\", \"trace\": \"[REDACTED]..."`),
+		errors.New(`response body contains forbidden goroutine \d+: "...[REDACTED] refused\", \"trace\": \"[REDACTED] [running]: main.main() /app/m..."`),
+	}, errs)
+}
+
+func TestCheckShouldRedactEveryMatchOfSnippet(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 500,
+		ResponseBody:       `{"hosts": ["db-1.internal", "db-2.internal"], "token": "secret-token"}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyForbidden: []string{`[\w-]+\.internal\b`, `secret-\w+`, `token": "secret`},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{
+		errors.New(`response body contains forbidden [\w-]+\.internal\b: "{\"hosts\": [\"[REDACTED]\", \"[REDACTED]\"], \"[REDACTED]..."`),
+		errors.New(`response body contains forbidden secret-\w+: "..., \"[REDACTED]\"], \"[REDACTED]\"}"`),
+		errors.New(`response body contains forbidden token": "secret: "...[REDACTED]\", \"[REDACTED]\"], \"[REDACTED]\"}"`),
+	}, errs)
+}
+
+func TestCheckShouldReportInvalidRegexp(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyForbidden: []string{`(secret`},
+		},
+	}
+	errs, err := NewChecker().Check(test, &models.Result{ResponseBody: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{
+		errors.New("invalid forbidden response body regexp (secret: error parsing regexp: missing closing ): `(secret`"),
+	}, errs)
+}
@@ -0,0 +1,67 @@
+package response_body_hash
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+var algorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+type ResponseBodyHashChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseBodyHashChecker{}
+}
+
+func (c *ResponseBodyHashChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBodyHash
+}
+
+func (c *ResponseBodyHashChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	expected, ok := t.GetResponseBodyHash(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	// sort algorithms to report errors in the same order
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		newHash, ok := algorithms[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown response body hash algorithm %s", name)
+		}
+		h := newHash()
+		h.Write([]byte(result.ResponseBody))
+		actual := hex.EncodeToString(h.Sum(nil))
+		if !strings.EqualFold(actual, expected[name]) {
+			errs = append(errs, fmt.Errorf(
+				"response body %s does not match: expected %s, actual %s",
+				name,
+				expected[name],
+				actual,
+			))
+		}
+	}
+	return errs, nil
+}
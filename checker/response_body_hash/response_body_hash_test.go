@@ -0,0 +1,67 @@
+package response_body_hash
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldMatchBodyHash(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyHash: map[int]map[string]string{200: {
+				"md5":    "5D41402ABC4B2A76B9719D911017C592",
+				"sha256": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			}},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "hello"})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportMismatchedHash(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyHash: map[int]map[string]string{200: {"sha1": "0000"}},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "hello"})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("response body sha1 does not match: expected 0000, actual aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"),
+	}, errs)
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyHash: map[int]map[string]string{200: {"sha1": "0000"}},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 404, ResponseBody: "hello"})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldFailOnUnknownAlgorithm(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyHash: map[int]map[string]string{200: {"crc32": "0000"}},
+		},
+	}
+
+	_, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: "hello"})
+
+	assert.EqualError(t, err, "unknown response body hash algorithm crc32")
+}
@@ -0,0 +1,49 @@
+package response_body_matches
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+// snippetLength limits the part of the body shown when it doesn't match
+const snippetLength = 100
+
+type ResponseBodyMatchesChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseBodyMatchesChecker{}
+}
+
+func (c *ResponseBodyMatchesChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBodyMatches
+}
+
+// Check matches the raw response body with the regular expression
+func (c *ResponseBodyMatchesChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	pattern, ok := t.GetResponseBodyMatches(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return []error{fmt.Errorf("invalid response body regexp %s: %s", pattern, err.Error())}, nil
+	}
+	if !rx.MatchString(result.ResponseBody) {
+		return []error{fmt.Errorf("response body does not match regexp %s: %q", pattern, snippet(result.ResponseBody))}, nil
+	}
+	return nil, nil
+}
+
+func snippet(body string) string {
+	runes := []rune(body)
+	if len(runes) <= snippetLength {
+		return body
+	}
+	return string(runes[:snippetLength]) + "..."
+}
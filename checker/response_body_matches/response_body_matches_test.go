@@ -0,0 +1,74 @@
+package response_body_matches
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldMatchWholeBody(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "<html>\n<TITLE>Orders</TITLE>\n<input name=\"csrf\" value=\"a1b2\">\n</html>",
+	}
+
+	for _, pattern := range []string{`csrf" value="\w+"`, `(?i)<title>orders</title>`, `(?m)^<html>$`, `(?s)<html>.*</html>`} {
+		test := &yaml_file.Test{
+			TestDefinition: yaml_file.TestDefinition{
+				ResponseBodyMatches: map[int]string{200: pattern},
+			},
+		}
+		errs, err := NewChecker().Check(test, result)
+
+		assert.NoError(t, err, "Check must not result with an error")
+		assert.Empty(t, errs, pattern)
+	}
+}
+
+func TestCheckShouldReportRegexpAndSnippet(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       strings.Repeat("a", 150),
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyMatches: map[int]string{200: `^b`},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New(`response body does not match regexp ^b: "` + strings.Repeat("a", 100) + `..."`),
+	}, errs)
+}
+
+func TestCheckShouldReportInvalidRegexp(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyMatches: map[int]string{200: `(`},
+		},
+	}
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Len(t, errs, 1)
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseBodyMatches: map[int]string{200: `^b`},
+		},
+	}
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 404})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs)
+}
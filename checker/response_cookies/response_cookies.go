@@ -0,0 +1,84 @@
+package response_cookies
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseCookiesChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseCookiesChecker{}
+}
+
+func (c *ResponseCookiesChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryCookies
+}
+
+// Check compares the cookies set by the response with the expected ones
+func (c *ResponseCookiesChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	expected, ok := t.GetResponseCookies(result.ResponseStatusCode)
+	if !ok || len(expected) == 0 {
+		return nil, nil
+	}
+
+	actual := make(map[string]*http.Cookie)
+	for _, cookie := range (&http.Response{Header: result.ResponseHeaders}).Cookies() {
+		actual[cookie.Name] = cookie
+	}
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		cookie, ok := actual[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("response does not set expected cookie %s", name))
+			continue
+		}
+		if expected[name].Deleted && !isDeleted(cookie, time.Now()) {
+			errs = append(errs, fmt.Errorf("response cookie %s is not deleted: expires %s, max-age %s",
+				name, expiry(cookie), maxAge(cookie)))
+		}
+	}
+	return errs, nil
+}
+
+// isDeleted reports whether the cookie makes the client remove the stored one
+func isDeleted(cookie *http.Cookie, now time.Time) bool {
+	// Max-Age=0 and negative values are parsed as -1
+	if cookie.MaxAge < 0 {
+		return true
+	}
+	return cookie.MaxAge == 0 && !cookie.Expires.IsZero() && cookie.Expires.Before(now)
+}
+
+func expiry(cookie *http.Cookie) string {
+	if cookie.RawExpires == "" {
+		return "none"
+	}
+	return cookie.RawExpires
+}
+
+func maxAge(cookie *http.Cookie) string {
+	switch {
+	case cookie.MaxAge > 0:
+		return strconv.Itoa(cookie.MaxAge)
+	case cookie.MaxAge < 0:
+		return "0"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,77 @@
+package response_cookies
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+)
+
+const deletedDefinition = `
+responseCookies:
+  200:
+    session:
+      deleted: true
+`
+
+func newResult(setCookie ...string) *models.Result {
+	return &models.Result{
+		ResponseStatusCode: 200,
+		ResponseHeaders:    http.Header{"Set-Cookie": setCookie},
+	}
+}
+
+func TestCheckShouldPassForDeletedCookies(t *testing.T) {
+	tests := []string{
+		"session=; Max-Age=0",
+		"session=; Max-Age=-1",
+		"session=; Expires=Thu, 01 Jan 1970 00:00:00 GMT",
+	}
+	for _, setCookie := range tests {
+		t.Run(setCookie, func(t *testing.T) {
+			errs, err := NewChecker().Check(testutil.NewTest(t, deletedDefinition), newResult("theme=dark", setCookie))
+
+			assert.NoError(t, err, "Check must not result with an error")
+			assert.Empty(t, errs, "Check must succeed")
+		})
+	}
+}
+
+func TestCheckShouldReportActualExpiry(t *testing.T) {
+	tests := []struct {
+		setCookie string
+		expected  string
+	}{
+		{
+			setCookie: "session=abc; Expires=Fri, 01 Jan 2100 00:00:00 GMT",
+			expected:  "response cookie session is not deleted: expires Fri, 01 Jan 2100 00:00:00 GMT, max-age none",
+		},
+		{
+			setCookie: "session=abc; Max-Age=3600",
+			expected:  "response cookie session is not deleted: expires none, max-age 3600",
+		},
+		{
+			setCookie: "session=abc",
+			expected:  "response cookie session is not deleted: expires none, max-age none",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.setCookie, func(t *testing.T) {
+			errs, err := NewChecker().Check(testutil.NewTest(t, deletedDefinition), newResult(tc.setCookie))
+
+			assert.NoError(t, err, "Check must not result with an error")
+			assert.Equal(t, []error{errors.New(tc.expected)}, errs)
+		})
+	}
+}
+
+func TestCheckShouldReportMissingCookie(t *testing.T) {
+	errs, err := NewChecker().Check(testutil.NewTest(t, deletedDefinition), newResult("theme=dark"))
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{errors.New("response does not set expected cookie session")}, errs)
+}
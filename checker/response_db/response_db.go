@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/lamoda/gonkey/checker"
 	"github.com/lamoda/gonkey/models"
@@ -16,7 +17,8 @@ import (
 type ResponseDbChecker struct {
 	checker.CheckerInterface
 
-	db *sql.DB
+	db     *sql.DB
+	driver string
 }
 
 func NewChecker(dbConnect *sql.DB) checker.CheckerInterface {
@@ -25,6 +27,19 @@ func NewChecker(dbConnect *sql.DB) checker.CheckerInterface {
 	}
 }
 
+// NewCheckerForDriver creates checker for the database other than PostgreSQL,
+// the rows are converted to JSON by gonkey instead of the database
+func NewCheckerForDriver(dbConnect *sql.DB, driver string) checker.CheckerInterface {
+	return &ResponseDbChecker{
+		db:     dbConnect,
+		driver: driver,
+	}
+}
+
+func (c *ResponseDbChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryDb
+}
+
 func (c *ResponseDbChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
 	var errors []error
 
@@ -50,7 +65,7 @@ func (c *ResponseDbChecker) Check(t models.TestInterface, result *models.Result)
 	}
 
 	// get DB response
-	actualDbResponse, err := newQuery(t.DbQueryString(), c.db)
+	actualDbResponse, err := c.query(t.DbQueryString())
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +151,13 @@ func compareDbResponseLength(expected, actual []string, query interface{}) error
 	return err
 }
 
+func (c *ResponseDbChecker) query(dbQuery string) ([]string, error) {
+	if c.driver == "" || c.driver == "postgres" {
+		return newQuery(dbQuery, c.db)
+	}
+	return newGenericQuery(dbQuery, c.db)
+}
+
 func newQuery(dbQuery string, db *sql.DB) ([]string, error) {
 
 	var dbResponse []string
@@ -165,3 +187,62 @@ func newQuery(dbQuery string, db *sql.DB) ([]string, error) {
 
 	return dbResponse, nil
 }
+
+// newGenericQuery runs the query as is and encodes every row as JSON object
+func newGenericQuery(dbQuery string, db *sql.DB) ([]string, error) {
+
+	var dbResponse []string
+
+	if idx := strings.IndexByte(dbQuery, ';'); idx >= 0 {
+		dbQuery = dbQuery[:idx]
+	}
+
+	rows, err := db.Query(dbQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = toJSONValue(values[i])
+		}
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		dbResponse = append(dbResponse, string(encoded))
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return dbResponse, nil
+}
+
+// toJSONValue converts scanned value to be encoded as JSON,
+// text is returned by some drivers as bytes
+func toJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+	return value
+}
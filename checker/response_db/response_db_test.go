@@ -0,0 +1,28 @@
+package response_db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestGenericQueryShouldEncodeRowsAsJSON(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`^SELECT id, name, score FROM users$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}).
+			AddRow(int64(1), []byte("John"), 1.5).
+			AddRow(int64(2), nil, nil))
+
+	rows, err := newGenericQuery("SELECT id, name, score FROM users;", db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"id":1,"name":"John","score":1.5}`,
+		`{"id":2,"name":null,"score":null}`,
+	}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
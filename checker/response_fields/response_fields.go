@@ -0,0 +1,43 @@
+package response_fields
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseFieldsChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseFieldsChecker{}
+}
+
+func (c *ResponseFieldsChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryFields
+}
+
+// Check reports the required fields missing in JSON response regardless of their values
+func (c *ResponseFieldsChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	fields, ok := t.GetRequiredFields(result.ResponseStatusCode)
+	if !ok || len(fields) == 0 {
+		return nil, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+		return []error{fmt.Errorf("required fields can not be checked, response body is not JSON: %s", err.Error())}, nil
+	}
+
+	var errs []error
+	for _, field := range fields {
+		if _, err := compare.ResolvePath(body, field); err != nil {
+			errs = append(errs, fmt.Errorf("required response field %s is missing: %s", field, err.Error()))
+		}
+	}
+	return errs, nil
+}
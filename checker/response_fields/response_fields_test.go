@@ -0,0 +1,65 @@
+package response_fields
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldPassWhenFieldsExist(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": 1, "createdAt": null, "items": [{"id": 2}]}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			RequiredFields: map[int][]string{200: {"$.id", "$.createdAt", "$.items[0].id"}},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportMissingFields(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": 1, "items": []}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			RequiredFields: map[int][]string{200: {"$.id", "$.createdAt", "$.items[0].id"}},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("required response field $.createdAt is missing: no field createdAt"),
+		errors.New("required response field $.items[0].id is missing: no element [0]"),
+	}, errs)
+}
+
+func TestCheckShouldReportNonJSONBody(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `OK`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			RequiredFields: map[int][]string{200: {"$.id"}},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,52 @@
+package response_header
+
+import (
+	"strings"
+)
+
+// parseLinks parses RFC 5988 Link header values into a map of rel to URL,
+// a link with several space separated rel values is stored under each of them
+func parseLinks(values []string) map[string]string {
+	links := make(map[string]string)
+	for _, value := range values {
+		for _, link := range splitLinks(value) {
+			start := strings.Index(link, "<")
+			end := strings.Index(link, ">")
+			if start < 0 || end < start {
+				continue
+			}
+			url := link[start+1 : end]
+			for _, param := range strings.Split(link[end+1:], ";") {
+				kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(kv) != 2 || !strings.EqualFold(strings.TrimSpace(kv[0]), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(kv[1]), `"`)) {
+					links[strings.ToLower(rel)] = url
+				}
+			}
+		}
+	}
+	return links
+}
+
+// splitLinks splits header value by commas which are not inside of <> brackets or quotes
+func splitLinks(value string) []string {
+	var links []string
+	var inURL, inQuotes bool
+	start := 0
+	for i, c := range value {
+		switch {
+		case c == '<' && !inQuotes:
+			inURL = true
+		case c == '>' && !inQuotes:
+			inURL = false
+		case c == '"' && !inURL:
+			inQuotes = !inQuotes
+		case c == ',' && !inURL && !inQuotes:
+			links = append(links, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(links, value[start:])
+}
@@ -3,6 +3,7 @@ package response_header
 import (
 	"fmt"
 	"net/textproto"
+	"strings"
 
 	"github.com/lamoda/gonkey/checker"
 	"github.com/lamoda/gonkey/compare"
@@ -17,11 +18,21 @@ func NewChecker() checker.CheckerInterface {
 	return &ResponseHeaderChecker{}
 }
 
+func (c *ResponseHeaderChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryHeader
+}
+
 func (c *ResponseHeaderChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	errs := checkHeaders(t, result)
+	errs = append(errs, checkLinks(t, result)...)
+	return errs, nil
+}
+
+func checkHeaders(t models.TestInterface, result *models.Result) []error {
 	// test response headers with the expected headers
 	expectedHeaders, ok := t.GetResponseHeaders(result.ResponseStatusCode)
 	if !ok || len(expectedHeaders) == 0 {
-		return nil, nil
+		return nil
 	}
 
 	var errs []error
@@ -32,17 +43,38 @@ func (c *ResponseHeaderChecker) Check(t models.TestInterface, result *models.Res
 			errs = append(errs, fmt.Errorf("response does not include expected header %s", k))
 			continue
 		}
-		found := false
-		for _, actualValue := range actualValues {
-			e := compare.Compare(v, actualValue, compare.CompareParams{})
-			if len(e) == 0 {
-				found = true
-			}
+		if err := checkHeader(k, v, actualValues); err != nil {
+			errs = append(errs, err)
 		}
-		if !found {
-			errs = append(errs, fmt.Errorf("response header %s value does not match expected %s", k, v))
+	}
+
+	return errs
+}
+
+func checkLinks(t models.TestInterface, result *models.Result) []error {
+	// test Link header with the expected links, empty URL means any value
+	expectedLinks, ok := t.GetResponseLinks(result.ResponseStatusCode)
+	if !ok || len(expectedLinks) == 0 {
+		return nil
+	}
+
+	actualLinks := parseLinks(result.ResponseHeaders["Link"])
+
+	var errs []error
+	for rel, expectedURL := range expectedLinks {
+		rel = strings.ToLower(rel)
+		actualURL, ok := actualLinks[rel]
+		if !ok {
+			errs = append(errs, fmt.Errorf("response Link header does not include rel=%s", rel))
+			continue
+		}
+		if expectedURL == "" {
+			continue
+		}
+		if e := compare.Compare(expectedURL, actualURL, compare.CompareParams{}); len(e) != 0 {
+			errs = append(errs, fmt.Errorf("response link rel=%s %s does not match expected %s", rel, actualURL, expectedURL))
 		}
 	}
 
-	return errs, nil
+	return errs
 }
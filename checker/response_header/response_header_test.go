@@ -75,7 +75,73 @@ func TestCheckWhenNotMatchedShouldReturnError(t *testing.T) {
 		errs,
 		[]error{
 			errors.New("response does not include expected header Content-Type"),
-			errors.New("response header Accept value does not match expected text/html"),
+			errors.New("response header Accept value does not match expected text/html, actual: application/json"),
 		},
 	)
 }
+
+func TestCheckShouldMatchLinks(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseLinks: map[int]map[string]string{
+				200: {
+					"next":  "https://api.example.com/items?page=3",
+					"prev":  "$matchRegexp(page=1$)",
+					"first": "",
+				},
+			},
+		},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseHeaders: map[string][]string{
+			"Link": {
+				`<https://api.example.com/items?page=3>; rel="next", <https://api.example.com/items?page=1>; rel="prev first"`,
+			},
+		},
+	}
+
+	checker := NewChecker()
+	errs, err := checker.Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckWhenLinksNotMatchedShouldReturnError(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseLinks: map[int]map[string]string{
+				200: {
+					"next": "https://api.example.com/items?page=3",
+					"last": "",
+				},
+			},
+		},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseHeaders: map[string][]string{
+			"Link": {`<https://api.example.com/items?page=2,3>; rel=next`},
+		},
+	}
+
+	checker := NewChecker()
+	errs, err := checker.Check(test, result)
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(
+		t,
+		[]error{
+			errors.New("response Link header does not include rel=last"),
+			errors.New("response link rel=next https://api.example.com/items?page=2,3 does not match expected https://api.example.com/items?page=3"),
+		},
+		errs,
+	)
+}
@@ -0,0 +1,72 @@
+package response_header
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lamoda/gonkey/compare"
+)
+
+const (
+	anyValue = "*"
+	// escapedAnyValue expects the value * itself, e.g. Access-Control-Allow-Origin: \*
+	escapedAnyValue = `\*`
+)
+
+var (
+	matchesExprRx = regexp.MustCompile(`^\$matches\((.+)\)$`)
+	allExprRx     = regexp.MustCompile(`^\$all\((.+)\)$`)
+)
+
+// checkHeader matches the expected value with the values of the header, one of them is enough
+// unless the expected value is wrapped with $all(...). The expected value is either
+// * for any value, \* for the value * itself, $matches(<regexp>)
+// or the value compared as the response body, e.g. $matchRegexp(<regexp>)
+func checkHeader(name, expected string, actualValues []string) error {
+	all := false
+	if match := allExprRx.FindStringSubmatch(expected); match != nil {
+		all = true
+		expected = match[1]
+	}
+	if expected == anyValue {
+		return nil
+	}
+	if expected == escapedAnyValue {
+		expected = anyValue
+	}
+
+	matches, err := valueMatcher(expected)
+	if err != nil {
+		return fmt.Errorf("response header %s has invalid expected value %s: %s", name, expected, err.Error())
+	}
+
+	if all {
+		for _, actualValue := range actualValues {
+			if !matches(actualValue) {
+				return fmt.Errorf("response header %s value %s does not match expected %s", name, actualValue, expected)
+			}
+		}
+		return nil
+	}
+	for _, actualValue := range actualValues {
+		if matches(actualValue) {
+			return nil
+		}
+	}
+	return fmt.Errorf("response header %s value does not match expected %s, actual: %s",
+		name, expected, strings.Join(actualValues, ", "))
+}
+
+func valueMatcher(expected string) (func(string) bool, error) {
+	if match := matchesExprRx.FindStringSubmatch(expected); match != nil {
+		rx, err := regexp.Compile(match[1])
+		if err != nil {
+			return nil, err
+		}
+		return rx.MatchString, nil
+	}
+	return func(actual string) bool {
+		return len(compare.Compare(expected, actual, compare.CompareParams{})) == 0
+	}, nil
+}
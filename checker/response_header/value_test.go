@@ -0,0 +1,86 @@
+package response_header
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   []string
+		err      string
+	}{
+		{
+			name:     "any value",
+			expected: "*",
+			actual:   []string{"Wed, 21 Oct 2015 07:28:00 GMT"},
+		},
+		{
+			name:     "escaped any value",
+			expected: `\*`,
+			actual:   []string{"*"},
+		},
+		{
+			name:     "escaped any value not matched",
+			expected: `\*`,
+			actual:   []string{"https://example.com"},
+			err:      "response header Etag value does not match expected *, actual: https://example.com",
+		},
+		{
+			name:     "regexp",
+			expected: `$matches(^W/"[a-f0-9]+"$)`,
+			actual:   []string{`W/"0815af"`},
+		},
+		{
+			name:     "regexp not matched",
+			expected: `$matches(^W/"[a-f0-9]+"$)`,
+			actual:   []string{`"0815af"`},
+			err:      `response header Etag value does not match expected $matches(^W/"[a-f0-9]+"$), actual: "0815af"`,
+		},
+		{
+			name:     "invalid regexp",
+			expected: `$matches(^W/"[a-f0-9+"$)`,
+			actual:   []string{`W/"0815af"`},
+			err:      "response header Etag has invalid expected value $matches(^W/\"[a-f0-9+\"$): error parsing regexp: missing closing ]: `[a-f0-9+\"$`",
+		},
+		{
+			name:     "one of values",
+			expected: "$matches(^no-)",
+			actual:   []string{"private", "no-cache"},
+		},
+		{
+			name:     "all values",
+			expected: "$all($matches(^no-))",
+			actual:   []string{"no-store", "no-cache"},
+		},
+		{
+			name:     "not all values",
+			expected: "$all($matches(^no-))",
+			actual:   []string{"no-cache", "private"},
+			err:      "response header Etag value private does not match expected $matches(^no-)",
+		},
+		{
+			name:     "all values present",
+			expected: "$all(*)",
+			actual:   []string{"no-cache", "private"},
+		},
+		{
+			name:     "exact value",
+			expected: "no-cache",
+			actual:   []string{"no-cache"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkHeader("Etag", tt.expected, tt.actual)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
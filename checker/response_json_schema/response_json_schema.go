@@ -0,0 +1,94 @@
+package response_json_schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/internal/files"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseJSONSchemaChecker struct {
+	checker.CheckerInterface
+}
+
+// NewChecker validates the response body against the JSON Schema of its status,
+// unlike response_schema it doesn't need a swagger specification of the service
+func NewChecker() checker.CheckerInterface {
+	return &ResponseJSONSchemaChecker{}
+}
+
+func (c *ResponseJSONSchemaChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBody
+}
+
+func (c *ResponseJSONSchemaChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	source, ok := t.GetResponseSchema(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+	schema, err := loadSchema(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &actual); err != nil {
+		return []error{fmt.Errorf("could not parse response as JSON: %s", err.Error())}, nil
+	}
+
+	err = validate.AgainstSchema(schema, actual, strfmt.Default)
+	if err == nil {
+		return nil, nil
+	}
+	compositeError, ok := err.(*errors.CompositeError)
+	if !ok {
+		return []error{err}, nil
+	}
+	errs := make([]error, 0, len(compositeError.Errors))
+	for _, e := range compositeError.Errors {
+		errs = append(errs, makeError(e))
+	}
+	return errs, nil
+}
+
+// loadSchema reads the schema from the file unless it is an inline JSON object
+func loadSchema(source string) (*spec.Schema, error) {
+	data := []byte(source)
+	if !strings.HasPrefix(strings.TrimSpace(source), "{") {
+		var err error
+		if data, err = files.ReadFile(source); err != nil {
+			return nil, fmt.Errorf("unable to read response schema file %s: %s", source, err.Error())
+		}
+	}
+	var schema spec.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid response schema %s: %s", source, err.Error())
+	}
+	return &schema, nil
+}
+
+// makeError reports the path of the invalid value the same way the body checker does,
+// the validator names the fields without the indexes of array elements, e.g. items.id
+func makeError(err error) error {
+	validation, ok := err.(*errors.Validation)
+	if !ok {
+		return err
+	}
+	name := strings.TrimPrefix(validation.Name, ".")
+	path := "$"
+	if name != "" {
+		path += "." + name
+	}
+	msg := strings.TrimPrefix(validation.Error(), validation.Name+" ")
+	msg = strings.TrimPrefix(msg, "in body ")
+	return fmt.Errorf("at path %s %s", color.CyanString(path), msg)
+}
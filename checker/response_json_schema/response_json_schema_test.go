@@ -0,0 +1,118 @@
+package response_json_schema
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldPassValidBody(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": 1, "status": "paid"}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: filepath.Join("testdata", "order.json")},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReadGzippedSchema(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": 1, "status": "paid"}`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: filepath.Join("testdata", "order.json.gz")},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportPaths(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": "1", "items": [{"sku": 7}]}`,
+	}
+	schema := `{
+		"type": "object",
+		"required": ["id", "status"],
+		"properties": {
+			"id": {"type": "integer"},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}}
+		}
+	}`
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: schema},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.ElementsMatch(t, []error{
+		errors.New("at path " + color.CyanString("$.id") + ` must be of type integer: "string"`),
+		errors.New("at path " + color.CyanString("$.items.sku") + ` must be of type string: "number"`),
+		errors.New("at path " + color.CyanString("$.status") + " is required"),
+	}, errs)
+}
+
+func TestCheckShouldReportInvalidJSON(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `<html></html>`,
+	}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: `{"type": "object"}`},
+		},
+	}
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Len(t, errs, 1)
+}
+
+func TestCheckShouldFailOnMissingSchemaFile(t *testing.T) {
+	result := &models.Result{ResponseStatusCode: 200, ResponseBody: `{}`}
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: filepath.Join("testdata", "missing.json")},
+		},
+	}
+	_, err := NewChecker().Check(test, result)
+
+	assert.Error(t, err)
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseSchemas: map[int]string{200: `{"type": "array"}`},
+		},
+	}
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 404})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,85 @@
+package response_keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseKeysChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseKeysChecker{}
+}
+
+func (c *ResponseKeysChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryKeys
+}
+
+// Check reports missing and extra keys of the JSON objects found by the paths
+func (c *ResponseKeysChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	keysByPath, ok := t.GetResponseKeys(result.ResponseStatusCode)
+	if !ok || len(keysByPath) == 0 {
+		return nil, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+		return []error{fmt.Errorf("response keys can not be checked, response body is not JSON: %s", err.Error())}, nil
+	}
+
+	paths := make([]string, 0, len(keysByPath))
+	for path := range keysByPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		errs = append(errs, checkKeys(body, path, keysByPath[path])...)
+	}
+	return errs, nil
+}
+
+func checkKeys(body interface{}, path string, expected []string) []error {
+	value, err := compare.ResolvePath(body, path)
+	if err != nil {
+		return []error{fmt.Errorf("response keys at %s can not be checked: %s", path, err.Error())}
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("response keys at %s can not be checked: value is not an object", path)}
+	}
+
+	expectedKeys := make(map[string]bool, len(expected))
+	var missing []string
+	for _, key := range expected {
+		expectedKeys[key] = true
+		if _, ok := object[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	var extra []string
+	for key := range object {
+		if !expectedKeys[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+
+	var errs []error
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("response keys missing at %s: %s", path, strings.Join(missing, ", ")))
+	}
+	if len(extra) > 0 {
+		errs = append(errs, fmt.Errorf("response keys not expected at %s: %s", path, strings.Join(extra, ", ")))
+	}
+	return errs
+}
@@ -0,0 +1,90 @@
+package response_keys
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+)
+
+const topLevelDefinition = `
+responseKeys:
+  200: [id, name, status]
+`
+
+const pathsDefinition = `
+responseKeys:
+  200:
+    $: [data]
+    $.data.items[0]: [id, price]
+`
+
+func TestCheckShouldPassWhenKeysMatch(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"status": "new", "id": 1, "name": null}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, topLevelDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportMissingAndExtraKeys(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"id": 1, "title": "x", "createdAt": "2020-01-01"}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, topLevelDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("response keys missing at $: name, status"),
+		errors.New("response keys not expected at $: createdAt, title"),
+	}, errs)
+}
+
+func TestCheckShouldCheckKeysByPath(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"data": {"items": [{"id": 1, "price": 10, "discount": 1}]}}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, pathsDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("response keys not expected at $.data.items[0]: discount"),
+	}, errs)
+}
+
+func TestCheckShouldReportNonObjectValue(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{"data": []}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, pathsDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("response keys at $.data.items[0] can not be checked: no field items"),
+	}, errs)
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 404,
+		ResponseBody:       `not found`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, topLevelDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,86 @@
+package response_merge_patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseMergePatchChecker struct {
+	checker.CheckerInterface
+}
+
+// NewChecker compares the response body with the base of its status merged with the request body
+// as JSON Merge Patch (RFC 7396), e.g. the resource before PATCH
+func NewChecker() checker.CheckerInterface {
+	return &ResponseMergePatchChecker{}
+}
+
+func (c *ResponseMergePatchChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryBody
+}
+
+func (c *ResponseMergePatchChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	base, ok := t.GetResponseMergeBase(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+	var target interface{}
+	if err := json.Unmarshal([]byte(base), &target); err != nil {
+		return nil, fmt.Errorf("invalid JSON in merge base for status %d: %s", result.ResponseStatusCode, err.Error())
+	}
+	var patch interface{}
+	if err := json.Unmarshal([]byte(result.RequestBody), &patch); err != nil {
+		return nil, fmt.Errorf("request body is not a JSON merge patch: %s", err.Error())
+	}
+	expected := MergePatch(target, patch)
+
+	var actual interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &actual); err != nil {
+		return []error{errors.New("could not parse response")}, nil
+	}
+
+	params := compare.CompareParams{
+		IgnoreValues:         !t.NeedsCheckingValues(),
+		IgnoreArraysOrdering: t.IgnoreArraysOrdering(),
+		DisallowExtraFields:  t.DisallowExtraFields(),
+		Wildcard:             t.Wildcard(),
+	}
+	compareErrs := compare.Compare(expected, actual, params)
+	if len(compareErrs) == 0 {
+		return nil, nil
+	}
+
+	expectedJSON, _ := json.Marshal(expected)
+	errs := []error{fmt.Errorf("response does not match the merge base patched with the request: %s", expectedJSON)}
+	return append(errs, compareErrs...), nil
+}
+
+// MergePatch applies the patch to the target as described by RFC 7396: the objects are merged recursively,
+// null removes the field and any other value replaces the target. The target is left unchanged
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	merged := make(map[string]interface{}, len(targetObject)+len(patchObject))
+	for k, v := range targetObject {
+		merged[k] = v
+	}
+	for k, v := range patchObject {
+		if v == nil {
+			delete(merged, k)
+		} else {
+			merged[k] = MergePatch(merged[k], v)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,67 @@
+package response_merge_patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+const mergeBase = `{"id": 1, "name": "Dune", "author": {"name": "Herbert", "born": 1920}, "tags": ["novel"]}`
+
+func check(t *testing.T, request, response string) []error {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			ResponseMergeBase: map[int]string{200: mergeBase},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, &models.Result{
+		RequestBody:        request,
+		ResponseStatusCode: 200,
+		ResponseBody:       response,
+	})
+	require.NoError(t, err)
+	return errs
+}
+
+func TestCheckShouldCompareWithPatchedBase(t *testing.T) {
+	errs := check(t,
+		`{"name": "Dune Messiah", "author": {"born": null}, "tags": ["sequel"]}`,
+		`{"id": 1, "name": "Dune Messiah", "author": {"name": "Herbert"}, "tags": ["sequel"]}`,
+	)
+	assert.Empty(t, errs)
+}
+
+func TestCheckShouldReportDifferencesWithPatchedBase(t *testing.T) {
+	errs := check(t,
+		`{"name": "Dune Messiah"}`,
+		`{"id": 1, "name": "Dune", "author": {"name": "Herbert", "born": 1920}, "tags": ["novel"]}`,
+	)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), `response does not match the merge base patched with the request: {"author":{"born":1920,"name":"Herbert"},"id":1,"name":"Dune Messiah","tags":["novel"]}`)
+	assert.Contains(t, errs[1].Error(), "Dune Messiah")
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	errs, err := NewChecker().Check(&yaml_file.Test{}, &models.Result{ResponseStatusCode: 200})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{"a": "b", "c": map[string]interface{}{"d": "e", "f": "g"}}
+	patch := map[string]interface{}{"a": "z", "c": map[string]interface{}{"f": nil}}
+
+	assert.Equal(t,
+		map[string]interface{}{"a": "z", "c": map[string]interface{}{"d": "e"}},
+		MergePatch(target, patch),
+	)
+	// the target is left unchanged
+	assert.Equal(t, "g", target["c"].(map[string]interface{})["f"])
+	// the patch which is not an object replaces the target
+	assert.Equal(t, []interface{}{"x"}, MergePatch(target, []interface{}{"x"}))
+}
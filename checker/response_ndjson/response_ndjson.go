@@ -0,0 +1,115 @@
+package response_ndjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseNDJSONChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseNDJSONChecker{}
+}
+
+func (c *ResponseNDJSONChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryNDJSON
+}
+
+// Check parses every non-empty line of the response as JSON and compares the lines with the expected ones
+func (c *ResponseNDJSONChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	expected, ok := t.GetResponseNDJSON(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	expectedLines := make([]interface{}, len(expected.Lines))
+	for i, line := range expected.Lines {
+		if err := json.Unmarshal([]byte(line), &expectedLines[i]); err != nil {
+			return nil, fmt.Errorf("invalid JSON in expected NDJSON line %d of test %s: %s", i+1, t.GetName(), err.Error())
+		}
+	}
+
+	var actualLines []interface{}
+	for i, line := range strings.Split(result.ResponseBody, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var actual interface{}
+		if err := json.Unmarshal([]byte(line), &actual); err != nil {
+			return []error{fmt.Errorf("NDJSON line %d is not JSON: %s", i+1, err.Error())}, nil
+		}
+		actualLines = append(actualLines, actual)
+	}
+
+	var errs []error
+	if len(actualLines) != expected.Count {
+		errs = append(errs, fmt.Errorf("NDJSON lines count does not match: expected %d, actual %d", expected.Count, len(actualLines)))
+	}
+
+	params := compare.CompareParams{
+		IgnoreValues:         !t.NeedsCheckingValues(),
+		IgnoreArraysOrdering: t.IgnoreArraysOrdering(),
+		DisallowExtraFields:  t.DisallowExtraFields(),
+		Wildcard:             t.Wildcard(),
+	}
+	if expected.Unordered {
+		return append(errs, compareUnordered(expected.Lines, expectedLines, actualLines, params)...), nil
+	}
+	return append(errs, compareOrdered(expectedLines, actualLines, params)...), nil
+}
+
+// compareOrdered reports the first line which doesn't match
+func compareOrdered(expected, actual []interface{}, params compare.CompareParams) []error {
+	for i := range expected {
+		if i >= len(actual) {
+			return nil
+		}
+		if diff := compare.Compare(expected[i], actual[i], params); len(diff) > 0 {
+			return append([]error{fmt.Errorf("NDJSON line %d does not match", i+1)}, diff...)
+		}
+	}
+	return nil
+}
+
+// compareUnordered reports the expected lines not matched by any of the actual lines,
+// the same actual line can't match several expected ones
+func compareUnordered(sources []string, expected, actual []interface{}, params compare.CompareParams) []error {
+	// matchOf is the expected line matched by the actual one
+	matchOf := make(map[int]int)
+	var assign func(e int, visited map[int]bool) bool
+	assign = func(e int, visited map[int]bool) bool {
+		for a := range actual {
+			if visited[a] || len(compare.Compare(expected[e], actual[a], params)) > 0 {
+				continue
+			}
+			visited[a] = true
+			if other, ok := matchOf[a]; !ok || assign(other, visited) {
+				matchOf[a] = e
+				return true
+			}
+		}
+		return false
+	}
+	for e := range expected {
+		assign(e, make(map[int]bool))
+	}
+
+	matched := make(map[int]bool, len(matchOf))
+	for _, e := range matchOf {
+		matched[e] = true
+	}
+	var errs []error
+	for e := range expected {
+		if !matched[e] {
+			errs = append(errs, fmt.Errorf("NDJSON line not found: %s", strings.TrimSpace(sources[e])))
+		}
+	}
+	return errs
+}
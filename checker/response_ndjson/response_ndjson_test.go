@@ -0,0 +1,108 @@
+package response_ndjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+)
+
+const orderedDefinition = `
+responseNDJSON:
+  200:
+    - '{"id": 1, "name": "$matchRegexp(^Du)"}'
+    - '{"id": 2}'
+`
+
+const unorderedDefinition = `
+responseNDJSON:
+  200:
+    unordered: true
+    count: 3
+    lines:
+      - '{"id": 2}'
+      - '{"id": 4}'
+`
+
+func TestCheckShouldPassWhenLinesMatch(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "{\"id\": 1, \"name\": \"Dune\"}\n{\"id\": 2, \"name\": \"Solaris\"}\n",
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, orderedDefinition), result)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckShouldReportFirstDifferingLine(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "{\"id\": 2, \"name\": \"Solaris\"}\n{\"id\": 1, \"name\": \"Dune\"}\n{\"id\": 3}",
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, orderedDefinition), result)
+
+	assert.NoError(t, err)
+	messages := testutil.ErrorMessages(errs)
+	require.True(t, len(messages) > 2, messages)
+	assert.Equal(t, "NDJSON lines count does not match: expected 2, actual 3", messages[0])
+	assert.Equal(t, "NDJSON line 1 does not match", messages[1])
+}
+
+func TestCheckShouldMatchUnorderedLines(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "{\"id\": 3}\n{\"id\": 2}\n{\"id\": 1}",
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, unorderedDefinition), result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`NDJSON line not found: {"id": 4}`}, testutil.ErrorMessages(errs))
+}
+
+func TestCheckShouldReportInvalidLine(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "{\"id\": 1}\nerror: timeout\n",
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, orderedDefinition), result)
+
+	assert.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "NDJSON line 2 is not JSON")
+}
+
+func TestCheckShouldAcceptBothFormsForDifferentStatuses(t *testing.T) {
+	definition := `
+responseNDJSON:
+  200:
+    - '{"id": 1}'
+  206:
+    unordered: true
+    count: 2
+    lines:
+      - '{"id": 2}'
+`
+	test := testutil.NewTest(t, definition)
+
+	errs, err := NewChecker().Check(test, &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "{\"id\": 1}\n",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = NewChecker().Check(test, &models.Result{
+		ResponseStatusCode: 206,
+		ResponseBody:       "{\"id\": 3}\n{\"id\": 2}\n",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
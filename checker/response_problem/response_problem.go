@@ -0,0 +1,78 @@
+package response_problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+const problemContentType = "application/problem+json"
+
+type ResponseProblemChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseProblemChecker{}
+}
+
+func (c *ResponseProblemChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryProblem
+}
+
+// Check validates standard fields of RFC 7807 problem details,
+// extension fields are left to the body checker
+func (c *ResponseProblemChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	expected, ok := t.GetResponseProblem(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(result.ResponseContentType); mediaType != problemContentType {
+		return []error{fmt.Errorf(
+			"response Content-Type does not match: expected %q, actual %q",
+			problemContentType,
+			result.ResponseContentType,
+		)}, nil
+	}
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &actual); err != nil {
+		return []error{fmt.Errorf("response body is not a valid problem details object: %s", err.Error())}, nil
+	}
+
+	var errs []error
+	errs = appendFieldError(errs, actual, "type", expected.Type)
+	errs = appendFieldError(errs, actual, "title", expected.Title)
+	if expected.Status != 0 {
+		errs = appendFieldError(errs, actual, "status", float64(expected.Status))
+	}
+	errs = appendFieldError(errs, actual, "detail", expected.Detail)
+
+	return errs, nil
+}
+
+// appendFieldError checks the field only if its expected value is specified,
+// $matchRegexp can be used for string fields
+func appendFieldError(errs []error, actual map[string]interface{}, name string, expected interface{}) []error {
+	if expected == "" {
+		return errs
+	}
+	value, ok := actual[name]
+	if !ok {
+		return append(errs, fmt.Errorf("problem details field %q is missing", name))
+	}
+	if len(compare.Compare(expected, value, compare.CompareParams{})) != 0 {
+		return append(errs, fmt.Errorf(
+			"problem details field %q does not match: expected %v, actual %v",
+			name,
+			expected,
+			value,
+		))
+	}
+	return errs
+}
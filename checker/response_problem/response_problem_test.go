@@ -0,0 +1,82 @@
+package response_problem
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+)
+
+const problemDefinition = `
+responseProblem:
+  404:
+    type: https://example.com/problems/not-found
+    title: Not Found
+    status: 404
+    detail: $matchRegexp(^order \d+ not found$)
+`
+
+func TestCheckShouldSkipWhenProblemNotSpecified(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  404,
+		ResponseContentType: "text/plain",
+		ResponseBody:        "not found",
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, problemDefinition), &models.Result{ResponseStatusCode: 200})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = NewChecker().Check(testutil.NewTest(t, "name: test"), result)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckShouldMatchProblem(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  404,
+		ResponseContentType: "application/problem+json; charset=utf-8",
+		ResponseBody: `{"type": "https://example.com/problems/not-found", "title": "Not Found",` +
+			` "status": 404, "detail": "order 15 not found", "orderId": 15}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, problemDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportMismatchedFields(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  404,
+		ResponseContentType: "application/problem+json",
+		ResponseBody:        `{"type": "about:blank", "status": 400, "detail": "order 15 not found"}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, problemDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New(`problem details field "type" does not match: expected https://example.com/problems/not-found, actual about:blank`),
+		errors.New(`problem details field "title" is missing`),
+		errors.New(`problem details field "status" does not match: expected 404, actual 400`),
+	}, errs)
+}
+
+func TestCheckShouldRequireProblemContentType(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  404,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, problemDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New(`response Content-Type does not match: expected "application/problem+json", actual "application/json"`),
+	}, errs)
+}
@@ -0,0 +1,106 @@
+package response_redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+
+	"github.com/fatih/color"
+)
+
+// Client is the set of Redis commands used by the checker,
+// it is easily implemented on top of any Redis client library
+type Client interface {
+	// Get returns value of the key and false if there is no such key
+	Get(key string) (string, bool, error)
+	// TTL returns remaining time to live of the key, negative value means the key has no expiration
+	TTL(key string) (time.Duration, error)
+}
+
+type ResponseRedisChecker struct {
+	checker.CheckerInterface
+
+	client Client
+}
+
+func NewChecker(client Client) checker.CheckerInterface {
+	return &ResponseRedisChecker{
+		client: client,
+	}
+}
+
+func (c *ResponseRedisChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryRedis
+}
+
+func (c *ResponseRedisChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	var errors []error
+
+	for _, check := range t.RedisChecks() {
+		if check.Key == "" {
+			return nil, fmt.Errorf("redis key not found for test \"%s\"", t.GetName())
+		}
+
+		value, exists, err := c.client.Get(check.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			result.RedisResponse = append(result.RedisResponse, fmt.Sprintf("%s: <missing>", check.Key))
+			if check.Exists {
+				errors = append(errors, fmt.Errorf("redis key %s does not exist", color.CyanString(check.Key)))
+			}
+			continue
+		}
+
+		ttl, err := c.client.TTL(check.Key)
+		if err != nil {
+			return nil, err
+		}
+		result.RedisResponse = append(result.RedisResponse, fmt.Sprintf("%s: %s (ttl %s)", check.Key, value, ttl))
+
+		if !check.Exists {
+			errors = append(errors, fmt.Errorf("redis key %s is expected to be absent", color.CyanString(check.Key)))
+			continue
+		}
+
+		if check.Value != nil {
+			for _, e := range compare.Compare(*check.Value, value, compare.CompareParams{}) {
+				errors = append(errors, fmt.Errorf("redis key %s value does not match: %s", color.CyanString(check.Key), e))
+			}
+		}
+
+		if err := checkTTL(check, ttl); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return errors, nil
+}
+
+func checkTTL(check models.RedisCheck, ttl time.Duration) error {
+	if check.TTLMin == 0 && check.TTLMax == 0 {
+		return nil
+	}
+
+	min := time.Duration(check.TTLMin) * time.Second
+	max := time.Duration(check.TTLMax) * time.Second
+	expected := fmt.Sprintf("from %s to %s", min, max)
+	if check.TTLMax == 0 {
+		expected = fmt.Sprintf("at least %s", min)
+	}
+
+	if ttl < 0 || ttl < min || (check.TTLMax != 0 && ttl > max) {
+		return fmt.Errorf(
+			"redis key %s ttl is out of range (-expected: %s +actual: %s)",
+			color.CyanString(check.Key),
+			color.CyanString(expected),
+			color.CyanString("%s", ttl),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package response_redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+type redisKey struct {
+	value string
+	ttl   time.Duration
+}
+
+type fakeClient map[string]redisKey
+
+func (c fakeClient) Get(key string) (string, bool, error) {
+	k, ok := c[key]
+	return k.value, ok, nil
+}
+
+func (c fakeClient) TTL(key string) (time.Duration, error) {
+	return c[key].ttl, nil
+}
+
+func loadTest(t *testing.T, definition string) *yaml_file.Test {
+	var test yaml_file.Test
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &test.TestDefinition))
+	return &test
+}
+
+func TestCheckShouldSucceed(t *testing.T) {
+	test := loadTest(t, `
+responseRedis:
+  - key: session:1
+    value: $matchRegexp(^user-\d+$)
+    ttl:
+      min: 10
+      max: 60
+  - key: session:2
+    exists: false
+`)
+	client := fakeClient{
+		"session:1": {value: "user-42", ttl: 30 * time.Second},
+	}
+
+	result := &models.Result{}
+	errs, err := NewChecker(client).Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+	assert.Equal(t, []string{"session:1: user-42 (ttl 30s)", "session:2: <missing>"}, result.RedisResponse)
+}
+
+func TestCheckShouldReturnErrors(t *testing.T) {
+	test := loadTest(t, `
+responseRedis:
+  - key: missing
+  - key: evicted
+    exists: false
+  - key: wrong_value
+    value: expected
+  - key: wrong_ttl
+    ttl:
+      max: 10
+`)
+	client := fakeClient{
+		"evicted":     {value: "1", ttl: time.Second},
+		"wrong_value": {value: "actual", ttl: -1},
+		"wrong_ttl":   {value: "1", ttl: time.Minute},
+	}
+
+	errs, err := NewChecker(client).Check(test, &models.Result{})
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Len(t, errs, 4)
+}
@@ -34,6 +34,10 @@ func NewChecker(specLocation string) checker.CheckerInterface {
 	}
 }
 
+func (c *ResponseSchemaChecker) Category() models.ErrorCategory {
+	return models.ErrorCategorySchema
+}
+
 func (c *ResponseSchemaChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
 	// decode actual body
 	var actual interface{}
@@ -0,0 +1,55 @@
+package response_status
+
+import (
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseStatusChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseStatusChecker{}
+}
+
+func (c *ResponseStatusChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryStatus
+}
+
+func (c *ResponseStatusChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	var errs []error
+
+	// test the reason phrase only if it is specified
+	if expectedText := t.GetStatusText(); expectedText != "" && result.ResponseStatusText != expectedText {
+		errs = append(errs, fmt.Errorf(
+			"response status text does not match: expected %q, actual %q",
+			expectedText,
+			result.ResponseStatusText,
+		))
+	}
+
+	// test the protocol only if it is specified
+	if expectedProto := t.GetProtocol(); expectedProto != "" && result.ResponseProto != expectedProto {
+		errs = append(errs, fmt.Errorf(
+			"response protocol does not match: expected %q, actual %q",
+			expectedProto,
+			result.ResponseProto,
+		))
+	}
+
+	// test the whole status line only if it is specified
+	if expectedLine := t.GetStatusLine(); expectedLine != "" {
+		if actualLine := result.ResponseProto + " " + result.ResponseStatus; actualLine != expectedLine {
+			errs = append(errs, fmt.Errorf(
+				"response status line does not match: expected %q, actual %q",
+				expectedLine,
+				actualLine,
+			))
+		}
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,122 @@
+package response_status
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckShouldSkipWhenStatusTextNotSpecified(t *testing.T) {
+	test := &yaml_file.Test{}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseStatusText: "OK",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldMatchStatusText(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{StatusText: "Unprocessable Entity"},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 422,
+		ResponseStatusText: "Unprocessable Entity",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckWhenStatusTextNotMatchedShouldReturnError(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{StatusText: "Unprocessable Entity"},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 422,
+		ResponseStatusText: "Validation Failed",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(
+		t,
+		[]error{
+			errors.New(`response status text does not match: expected "Unprocessable Entity", actual "Validation Failed"`),
+		},
+		errs,
+	)
+}
+
+func TestCheckWhenProtocolNotMatchedShouldReturnError(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{Protocol: "HTTP/2.0"},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseStatusText: "OK",
+		ResponseProto:      "HTTP/1.1",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(
+		t,
+		[]error{
+			errors.New(`response protocol does not match: expected "HTTP/2.0", actual "HTTP/1.1"`),
+		},
+		errs,
+	)
+}
+
+func TestCheckShouldMatchStatusLine(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{StatusLine: "HTTP/1.1 200 OK"},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseStatus:     "200 OK",
+		ResponseProto:      "HTTP/1.1",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckWhenStatusLineNotMatchedShouldReturnActualLine(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{StatusLine: "HTTP/1.1 200 OK"},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseStatus:     "200 Success",
+		ResponseProto:      "HTTP/1.0",
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New(`response status line does not match: expected "HTTP/1.1 200 OK", actual "HTTP/1.0 200 Success"`),
+	}, errs)
+}
@@ -0,0 +1,198 @@
+package response_structure
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseStructureChecker struct {
+	checker.CheckerInterface
+
+	update bool
+}
+
+// NewChecker creates the checker of the structure snapshots,
+// with update the snapshots are rewritten by the actual responses instead of being checked
+func NewChecker(update bool) checker.CheckerInterface {
+	return &ResponseStructureChecker{
+		update: update,
+	}
+}
+
+func (c *ResponseStructureChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryStructure
+}
+
+// Check compares the types of the values found in the JSON response by their paths with the snapshot
+// of the response status, so the update mode doesn't overwrite the snapshot with an unexpected response
+func (c *ResponseStructureChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	snapshotPath, ok := t.GetStructureSnapshot(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+		return []error{fmt.Errorf("response structure can not be checked, response body is not JSON: %s", err.Error())}, nil
+	}
+	actual := Infer(body)
+
+	if c.update {
+		return nil, writeSnapshot(snapshotPath, actual)
+	}
+
+	data, err := ioutil.ReadFile(snapshotPath)
+	if os.IsNotExist(err) {
+		return []error{fmt.Errorf("structure snapshot %s does not exist, run the tests in update mode to create it", snapshotPath)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var expected map[string]string
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, fmt.Errorf("unable to parse structure snapshot %s: %s", snapshotPath, err.Error())
+	}
+	return Compare(expected, actual), nil
+}
+
+func writeSnapshot(path string, structure map[string]string) error {
+	data, err := json.MarshalIndent(structure, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Infer returns the types of the values by their paths, the elements of arrays share the path
+// with [*] and the types met at the same path are joined with |, e.g. "null|string"
+func Infer(value interface{}) map[string]string {
+	types := make(map[string]map[string]bool)
+	infer(types, "$", value)
+
+	structure := make(map[string]string, len(types))
+	for path, met := range types {
+		names := make([]string, 0, len(met))
+		for name := range met {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		structure[path] = strings.Join(names, "|")
+	}
+	return structure
+}
+
+func infer(types map[string]map[string]bool, path string, value interface{}) {
+	if types[path] == nil {
+		types[path] = make(map[string]bool)
+	}
+	types[path][typeName(value)] = true
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			infer(types, path+"."+key, item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			infer(types, path+"[*]", item)
+		}
+	}
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Compare reports the paths which types changed, which are missing or not expected,
+// the paths inside the changed values and inside the empty arrays are not reported
+func Compare(expected, actual map[string]string) []error {
+	paths := make([]string, 0, len(expected)+len(actual))
+	for path := range expected {
+		paths = append(paths, path)
+	}
+	for path := range actual {
+		if _, ok := expected[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	// parents go before their children
+	sort.Strings(paths)
+
+	var errs []error
+	var reported []string
+	for _, path := range paths {
+		if insideAny(path, reported) {
+			continue
+		}
+		expectedType, inExpected := expected[path]
+		actualType, inActual := actual[path]
+		switch {
+		case !inActual:
+			if insideEmptyArray(path, actual) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("structure at %s is missing: expected %s", path, expectedType))
+		case !inExpected:
+			if insideEmptyArray(path, expected) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("structure at %s is not expected: actual %s", path, actualType))
+		case expectedType != actualType:
+			errs = append(errs, fmt.Errorf("structure at %s changed: expected %s, actual %s", path, expectedType, actualType))
+		default:
+			continue
+		}
+		reported = append(reported, path)
+	}
+	return errs
+}
+
+// insideAny is true if the path is a child of any of the parents
+func insideAny(path string, parents []string) bool {
+	for _, parent := range parents {
+		if strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[*]") {
+			return true
+		}
+	}
+	return false
+}
+
+// insideEmptyArray is true if the path is inside the elements of an array with no elements in the structure
+func insideEmptyArray(path string, structure map[string]string) bool {
+	for i := strings.Index(path, "[*]"); i != -1; {
+		array := path[:i]
+		if _, ok := structure[array+"[*]"]; !ok && strings.Contains(structure[array], "array") {
+			return true
+		}
+		next := strings.Index(path[i+3:], "[*]")
+		if next == -1 {
+			break
+		}
+		i += 3 + next
+	}
+	return false
+}
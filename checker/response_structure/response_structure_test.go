@@ -0,0 +1,110 @@
+package response_structure
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestInferShouldJoinTypesOfArrayElements(t *testing.T) {
+	structure := Infer(map[string]interface{}{
+		"total": 2.0,
+		"items": []interface{}{
+			map[string]interface{}{"id": 1.0, "deletedAt": nil},
+			map[string]interface{}{"id": 2.0, "deletedAt": "2020-01-01"},
+		},
+	})
+
+	assert.Equal(t, map[string]string{
+		"$":                    "object",
+		"$.total":              "number",
+		"$.items":              "array",
+		"$.items[*]":           "object",
+		"$.items[*].id":        "number",
+		"$.items[*].deletedAt": "null|string",
+	}, structure)
+}
+
+func TestCompareShouldReportChangedMissingAndExtraPaths(t *testing.T) {
+	expected := map[string]string{
+		"$":             "object",
+		"$.id":          "number",
+		"$.author":      "object",
+		"$.author.name": "string",
+		"$.tags":        "array",
+		"$.tags[*]":     "string",
+		"$.title":       "string",
+	}
+	actual := map[string]string{
+		"$":        "object",
+		"$.id":     "string",
+		"$.author": "null",
+		"$.tags":   "array",
+		"$.isbn":   "string",
+	}
+
+	assert.Equal(t, []string{
+		"structure at $.author changed: expected object, actual null",
+		"structure at $.id changed: expected number, actual string",
+		"structure at $.isbn is not expected: actual string",
+		"structure at $.title is missing: expected string",
+	}, testutil.ErrorMessages(Compare(expected, actual)))
+}
+
+func TestCheckShouldCreateAndCompareSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	test := &yaml_file.Test{FileName: filepath.Join(dir, "tests.yaml")}
+	test.StructureSnapshots = map[int]string{200: "snapshots/books.json"}
+	result := &models.Result{ResponseStatusCode: 200, ResponseBody: `{"id": 1, "title": "Dune"}`}
+
+	errs, err := NewChecker(false).Check(test, result)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"structure snapshot " + filepath.Join(dir, "snapshots", "books.json") +
+			" does not exist, run the tests in update mode to create it",
+	}, testutil.ErrorMessages(errs))
+
+	errs, err = NewChecker(true).Check(test, result)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// the values are not pinned
+	errs, err = NewChecker(false).Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: `{"id": 2, "title": "Solaris"}`})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = NewChecker(false).Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: `{"id": "2", "title": "Solaris"}`})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"structure at $.id changed: expected number, actual string"}, testutil.ErrorMessages(errs))
+}
+
+func TestUpdateShouldNotOverwriteSnapshotWithResponseOfOtherStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	test := &yaml_file.Test{FileName: filepath.Join(dir, "tests.yaml")}
+	test.StructureSnapshots = map[int]string{200: "snapshots/books.json"}
+	errs, err := NewChecker(true).Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: `{"id": 1}`})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = NewChecker(true).Check(test, &models.Result{ResponseStatusCode: 500, ResponseBody: `{"error": "oops"}`})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "snapshots", "books.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$.id": "number"`)
+}
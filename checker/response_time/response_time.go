@@ -0,0 +1,28 @@
+package response_time
+
+import (
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseTimeChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseTimeChecker{}
+}
+
+func (c *ResponseTimeChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryResponseTime
+}
+
+func (c *ResponseTimeChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	max := t.MaxResponseTime()
+	if max <= 0 || result.ResponseTime <= max {
+		return nil, nil
+	}
+	return []error{fmt.Errorf("response time %s exceeds %s", result.ResponseTime, max)}, nil
+}
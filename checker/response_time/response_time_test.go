@@ -0,0 +1,45 @@
+package response_time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestCheckShouldPassWithinLimit(t *testing.T) {
+	result := &models.Result{ResponseTime: 150 * time.Millisecond}
+
+	test := &yaml_file.Test{}
+	test.ResponseTimeVal.Max = models.Duration(200 * time.Millisecond)
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportActualTime(t *testing.T) {
+	result := &models.Result{ResponseTime: 250 * time.Millisecond}
+
+	test := &yaml_file.Test{}
+	test.ResponseTimeVal.Max = models.Duration(200 * time.Millisecond)
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{errors.New("response time 250ms exceeds 200ms")}, errs)
+}
+
+func TestCheckShouldSkipWithoutLimit(t *testing.T) {
+	result := &models.Result{ResponseTime: time.Minute}
+
+	test := &yaml_file.Test{}
+	test.ResponseTimeVal.Max = models.Duration(0)
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs)
+}
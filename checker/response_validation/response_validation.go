@@ -0,0 +1,139 @@
+package response_validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseValidationChecker struct {
+	checker.CheckerInterface
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseValidationChecker{}
+}
+
+func (c *ResponseValidationChecker) Category() models.ErrorCategory {
+	return models.ErrorCategoryValidation
+}
+
+// Check matches the validation errors of the response with the expected ones in any order
+// and reports the missing and the extra errors
+func (c *ResponseValidationChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	expected, ok := t.GetResponseValidationErrors(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+		return []error{fmt.Errorf("validation errors can not be checked, response body is not JSON: %s", err.Error())}, nil
+	}
+	value, err := compare.ResolvePath(body, expected.Path)
+	if err != nil {
+		return []error{fmt.Errorf("validation errors at %s can not be checked: %s", expected.Path, err.Error())}, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return []error{fmt.Errorf("validation errors at %s can not be checked: value is not an array", expected.Path)}, nil
+	}
+	actual := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		if actual[i], ok = item.(map[string]interface{}); !ok {
+			return []error{fmt.Errorf("validation error %s[%d] is not an object", expected.Path, i)}, nil
+		}
+	}
+
+	matchedActual, matchedExpected := matchErrors(expected.Errors, actual)
+
+	var errs []error
+	for i, e := range expected.Errors {
+		if !matchedExpected[i] {
+			errs = append(errs, fmt.Errorf("validation error missing: %s", formatError(e)))
+		}
+	}
+	for i, a := range actual {
+		if !matchedActual[i] {
+			errs = append(errs, fmt.Errorf("validation error not expected: %s", formatActualError(a)))
+		}
+	}
+	return errs, nil
+}
+
+// matchErrors finds the maximum matching of the expected errors with the actual ones,
+// the same actual error can't match several expected ones
+func matchErrors(expected []map[string]string, actual []map[string]interface{}) (map[int]bool, map[int]bool) {
+	// matchOf is the expected error matched by the actual one
+	matchOf := make(map[int]int)
+	var assign func(e int, visited map[int]bool) bool
+	assign = func(e int, visited map[int]bool) bool {
+		for a := range actual {
+			if visited[a] || !errorMatches(expected[e], actual[a]) {
+				continue
+			}
+			visited[a] = true
+			if other, ok := matchOf[a]; !ok || assign(other, visited) {
+				matchOf[a] = e
+				return true
+			}
+		}
+		return false
+	}
+	for e := range expected {
+		assign(e, make(map[int]bool))
+	}
+
+	matchedActual := make(map[int]bool, len(matchOf))
+	matchedExpected := make(map[int]bool, len(matchOf))
+	for a, e := range matchOf {
+		matchedActual[a] = true
+		matchedExpected[e] = true
+	}
+	return matchedActual, matchedExpected
+}
+
+func errorMatches(expected map[string]string, actual map[string]interface{}) bool {
+	for field, value := range expected {
+		actualValue, ok := actual[field]
+		if !ok {
+			return false
+		}
+		if len(compare.Compare(value, stringValue(actualValue), compare.CompareParams{})) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// stringValue returns strings as is and other values as JSON
+func stringValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+func formatError(e map[string]string) string {
+	fields := make([]string, 0, len(e))
+	for field, value := range e {
+		fields = append(fields, field+"="+strconv.Quote(value))
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+func formatActualError(e map[string]interface{}) string {
+	fields := make(map[string]string, len(e))
+	for field, value := range e {
+		fields[field] = stringValue(value)
+	}
+	return formatError(fields)
+}
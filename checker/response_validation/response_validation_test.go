@@ -0,0 +1,145 @@
+package response_validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/internal/testutil"
+	"github.com/lamoda/gonkey/models"
+)
+
+const plainDefinition = `
+responseValidationErrors:
+  422:
+    - field: email
+      message: $matchRegexp(^invalid)
+    - field: age
+      message: must be positive
+`
+
+const pathDefinition = `
+responseValidationErrors:
+  400:
+    path: $.error.details
+    errors:
+      - field: name
+`
+
+func TestCheckShouldMatchErrorsInAnyOrder(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 422,
+		ResponseBody: `{"errors": [
+			{"field": "age", "message": "must be positive", "code": 3},
+			{"field": "email", "message": "invalid email"}
+		]}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, plainDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldReportMissingAndExtraErrors(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 422,
+		ResponseBody: `{"errors": [
+			{"field": "email", "message": "invalid email"},
+			{"field": "email", "message": "too long"}
+		]}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, plainDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New(`validation error missing: field="age" message="must be positive"`),
+		errors.New(`validation error not expected: field="email" message="too long"`),
+	}, errs)
+}
+
+func TestCheckShouldMatchEveryActualErrorOnce(t *testing.T) {
+	// the first expected error could take any of the actual ones
+	definition := `
+responseValidationErrors:
+  422:
+    - field: $matchRegexp(.+)
+    - field: email
+`
+	result := &models.Result{
+		ResponseStatusCode: 422,
+		ResponseBody:       `{"errors": [{"field": "email"}, {"field": "name"}]}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, definition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldMatchErrorsAtPathSetForStatus(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 400,
+		ResponseBody:       `{"error": {"details": [{"field": "name", "message": "required"}]}}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, pathDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldFailOnNonArrayPath(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 400,
+		ResponseBody:       `{"error": {"details": {"field": "name"}}}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, pathDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Equal(t, []error{
+		errors.New("validation errors at $.error.details can not be checked: value is not an array"),
+	}, errs)
+}
+
+func TestCheckShouldAcceptBothFormsForDifferentStatuses(t *testing.T) {
+	definition := `
+responseValidationErrors:
+  422:
+    - field: email
+  400:
+    path: $.error.details
+    errors:
+      - field: name
+`
+	test := testutil.NewTest(t, definition)
+
+	errs, err := NewChecker().Check(test, &models.Result{
+		ResponseStatusCode: 422,
+		ResponseBody:       `{"errors": [{"field": "email"}]}`,
+	})
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+
+	errs, err = NewChecker().Check(test, &models.Result{
+		ResponseStatusCode: 400,
+		ResponseBody:       `{"error": {"details": [{"field": "name"}]}}`,
+	})
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs, "Check must succeed")
+}
+
+func TestCheckShouldSkipOtherStatuses(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       `{}`,
+	}
+
+	errs, err := NewChecker().Check(testutil.NewTest(t, plainDefinition), result)
+
+	assert.NoError(t, err, "Check must not result with an error")
+	assert.Empty(t, errs)
+}
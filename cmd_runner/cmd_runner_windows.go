@@ -6,18 +6,23 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
-func CmdRun(scriptPath string, timeout int) error {
+func CmdRun(scriptPath string, timeout time.Duration) error {
 	//by default timeout should be 3s
 	if timeout <= 0 {
-		timeout = 3
+		timeout = 3 * time.Second
 	}
 	cmd := exec.Command(strings.TrimRight(scriptPath, "\n"))
 	cmd.Env = os.Environ()
 
+	// Start a new process group so the children of the script can be found and killed later
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -33,12 +38,12 @@ func CmdRun(scriptPath string, timeout int) error {
 	}()
 
 	select {
-	case <-time.After(time.Duration(timeout) * time.Second):
-		// Kill process
-		if err := cmd.Process.Kill(); err != nil {
+	case <-time.After(timeout):
+		// Kill the process with all its children
+		if err := killTree(cmd.Process.Pid); err != nil {
 			return err
 		}
-		fmt.Printf("Process killed as timeout(%d) reached\n", timeout)
+		fmt.Printf("Process killed as timeout(%s) reached\n", timeout)
 	case err := <-done:
 		if err != nil {
 			return fmt.Errorf("process finished with error = %v", err)
@@ -55,3 +60,17 @@ func CmdRun(scriptPath string, timeout int) error {
 
 	return nil
 }
+
+// killTree kills the process and the processes started by it,
+// the process alone is killed if taskkill is unavailable
+func killTree(pid int) error {
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid))
+	if err := kill.Run(); err != nil {
+		process, findErr := os.FindProcess(pid)
+		if findErr != nil {
+			return err
+		}
+		return process.Kill()
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package compare
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -14,6 +16,8 @@ type CompareParams struct {
 	IgnoreValues         bool
 	IgnoreArraysOrdering bool
 	DisallowExtraFields  bool
+	// Wildcard is the expected value matching any scalar, doubled it matches any subtree, disabled if empty
+	Wildcard string
 }
 
 type leafsMatchType int
@@ -24,21 +28,76 @@ const (
 )
 
 var regexExprRx = regexp.MustCompile(`^\$matchRegexp\((.+)\)$`)
+var equalsExprRx = regexp.MustCompile(`^\$matchEquals\((\$.*)\)$`)
+
+const (
+	matchEmpty    = "$matchEmpty"
+	matchNotEmpty = "$matchNotEmpty"
+	matchInt      = "$matchInt"
+)
 
 // Compare compares values as plain text
 // It can be compared several ways:
 // - Pure values: should be equal
 // - Regex: try to compile 'expected' as regex and match 'actual' with it
 //     It activates on following syntax: $matchRegexp(%EXPECTED_VALUE%)
+// - Emptiness: $matchEmpty or $matchNotEmpty checks that 'actual' array, map or string
+//     is empty or not, null value does not match any of them
+// - Reference: $matchEquals($.path) checks that 'actual' equals to the value of another field
+//     of the 'actual' document, `length` of the referenced array, map or string can be used
+// - Integer: $matchInt checks that 'actual' number has no fractional part, the serialized form
+//     like 1.0 is only detected if the 'actual' document is decoded with json.Number
+// - Wildcard: params.Wildcard, e.g. "*", matches any scalar value, doubled it matches any subtree,
+//     the wildcard preceded by a backslash is compared as is
 func Compare(expected, actual interface{}, params CompareParams) []error {
-	return compareBranch("$", expected, actual, &params)
+	return compareBranch("$", expected, actual, &params, actual)
 }
 
-func compareBranch(path string, expected, actual interface{}, params *CompareParams) []error {
+func compareBranch(path string, expected, actual interface{}, params *CompareParams, root interface{}) []error {
 	expectedType := getType(expected)
 	actualType := getType(actual)
 	var errors []error
 
+	// check emptiness
+	if expected == matchEmpty || expected == matchNotEmpty {
+		return compareEmptiness(path, expected.(string), actual)
+	}
+
+	// check integer
+	if expected == matchInt {
+		return compareInt(path, actual)
+	}
+
+	// check wildcards
+	if expectedStr, ok := expected.(string); ok && params.Wildcard != "" {
+		switch expectedStr {
+		case params.Wildcard + params.Wildcard:
+			return nil
+		case params.Wildcard:
+			if !isScalarType(actualType) {
+				return []error{makeError(path, "wildcard matches scalar values only", "scalar", actualType)}
+			}
+			return nil
+		case `\` + params.Wildcard, `\` + params.Wildcard + params.Wildcard:
+			expected = expectedStr[1:]
+		}
+	}
+
+	// numbers decoded as json.Number are compared as the other numbers
+	if number, ok := actual.(json.Number); ok {
+		if f, err := number.Float64(); err == nil {
+			actual = f
+			actualType = getType(actual)
+		}
+	}
+
+	// check equality to another field
+	if expectedStr, ok := expected.(string); ok {
+		if matches := equalsExprRx.FindStringSubmatch(expectedStr); matches != nil {
+			return compareReference(path, matches[1], actual, root)
+		}
+	}
+
 	// compare types
 	if expectedType != actualType {
 		errors = append(errors, makeError(path, "types do not match", expectedType, actualType))
@@ -68,7 +127,7 @@ func compareBranch(path string, expected, actual interface{}, params *ComparePar
 		// iterate over children
 		for i := 0; i < expectedRef.Len(); i++ {
 			subPath := fmt.Sprintf("%s[%d]", path, i)
-			res := compareBranch(subPath, expectedRef.Index(i).Interface(), actualRef.Index(i).Interface(), params)
+			res := compareBranch(subPath, expectedRef.Index(i).Interface(), actualRef.Index(i).Interface(), params, root)
 			errors = append(errors, res...)
 		}
 	}
@@ -97,6 +156,7 @@ func compareBranch(path string, expected, actual interface{}, params *ComparePar
 				expectedRef.MapIndex(key).Interface(),
 				actualRef.MapIndex(key).Interface(),
 				params,
+				root,
 			)
 			errors = append(errors, res...)
 		}
@@ -178,6 +238,113 @@ func compareRegex(path string, expected, actual interface{}) (errors []error) {
 	return nil
 }
 
+func compareEmptiness(path, expected string, actual interface{}) []error {
+	if actual == nil {
+		return []error{makeError(path, "value is null", expected, nil)}
+	}
+
+	var length int
+	actualType := getType(actual)
+	switch actualType {
+	case "array", "map", "string":
+		length = reflect.ValueOf(actual).Len()
+	default:
+		return []error{makeError(path, "type mismatch", "array, map or string", actualType)}
+	}
+
+	if expected == matchEmpty && length != 0 {
+		return []error{makeError(path, "value is not empty", expected, fmt.Sprintf("length %d: %v", length, actual))}
+	}
+	if expected == matchNotEmpty && length == 0 {
+		return []error{makeError(path, "value is empty", expected, actual)}
+	}
+
+	return nil
+}
+
+func compareInt(path string, actual interface{}) []error {
+	switch value := actual.(type) {
+	case json.Number:
+		if _, err := strconv.ParseInt(value.String(), 10, 64); err != nil {
+			return []error{makeError(path, "value is not an integer", matchInt, value.String())}
+		}
+		return nil
+	case float64:
+		if value != float64(int64(value)) {
+			return []error{makeError(path, "value is not an integer", matchInt, value)}
+		}
+		return nil
+	}
+	return []error{makeError(path, "type mismatch", "number", getType(actual))}
+}
+
+func compareReference(path, ref string, actual, root interface{}) []error {
+	expected, err := ResolvePath(root, ref)
+	if err != nil {
+		return []error{makeError(path, "can not resolve "+ref, err.Error(), actual)}
+	}
+
+	if expectedNumber, ok := toFloat(expected); ok {
+		if actualNumber, ok := toFloat(actual); ok && expectedNumber == actualNumber {
+			return nil
+		}
+	} else if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	return []error{makeError(path, "value does not equal "+ref, expected, actual)}
+}
+
+var pathSegmentRx = regexp.MustCompile(`^(?:\.([^.\[]+)|\[(\d+)\])`)
+
+// ResolvePath finds the value by JSONPath like $.items[0].id, the last segment
+// can be `length` of an array, a map or a string unless the map has such key
+func ResolvePath(root interface{}, path string) (interface{}, error) {
+	value := root
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		matches := pathSegmentRx.FindStringSubmatch(rest)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid path at %s", rest)
+		}
+		rest = rest[len(matches[0]):]
+
+		ref := reflect.ValueOf(value)
+		switch {
+		case matches[2] != "":
+			index, _ := strconv.Atoi(matches[2])
+			if getType(value) != "array" || index >= ref.Len() {
+				return nil, fmt.Errorf("no element [%d]", index)
+			}
+			value = ref.Index(index).Interface()
+		case getType(value) == "map" && ref.MapIndex(reflect.ValueOf(matches[1])).IsValid():
+			value = ref.MapIndex(reflect.ValueOf(matches[1])).Interface()
+		case matches[1] == "length" && rest == "" && (getType(value) == "array" || getType(value) == "map" || getType(value) == "string"):
+			value = ref.Len()
+		default:
+			return nil, fmt.Errorf("no field %s", matches[1])
+		}
+	}
+	return value, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	ref := reflect.ValueOf(value)
+	switch ref.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(ref.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(ref.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return ref.Float(), true
+	}
+	return 0, false
+}
+
 func retrieveRegexStr(expr string) string {
 
 	if matches := regexExprRx.FindStringSubmatch(expr); matches != nil {
@@ -3,10 +3,12 @@ package compare
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func makeErrorString(path, msg string, expected, actual interface{}) string {
@@ -10277,3 +10279,136 @@ var complexJson2 = `
     ]
 }
 `
+
+func TestCompareEmptiness(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"errors": [], "data": {"id": 1}, "name": "", "missing": null}`), &actual)
+
+	expected := map[string]interface{}{
+		"errors": "$matchEmpty",
+		"data":   "$matchNotEmpty",
+		"name":   "$matchEmpty",
+	}
+	assert.Empty(t, Compare(expected, actual, CompareParams{}))
+}
+
+func TestCompareEmptinessErrors(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"errors": ["failed"], "data": {}, "missing": null, "count": 1}`), &actual)
+
+	expected := map[string]interface{}{
+		"errors":  "$matchEmpty",
+		"data":    "$matchNotEmpty",
+		"missing": "$matchEmpty",
+		"count":   "$matchNotEmpty",
+	}
+	errors := Compare(expected, actual, CompareParams{})
+	assert.ElementsMatch(t, []string{
+		makeErrorString("$.errors", "value is not empty", "$matchEmpty", "length 1: [failed]"),
+		makeErrorString("$.data", "value is empty", "$matchNotEmpty", "map[]"),
+		makeErrorString("$.missing", "value is null", "$matchEmpty", nil),
+		makeErrorString("$.count", "type mismatch", "array, map or string", "float64"),
+	}, errorsToStrings(errors))
+}
+
+func errorsToStrings(errors []error) []string {
+	var res []string
+	for _, err := range errors {
+		res = append(res, err.Error())
+	}
+	return res
+}
+
+func TestCompareReferences(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"id": 7, "total": 2, "items": [{"id": 7}, {"id": 8}], "data": {"id": 7}}`), &actual)
+
+	expected := map[string]interface{}{
+		"total": "$matchEquals($.items.length)",
+		"data":  map[string]interface{}{"id": "$matchEquals($.id)"},
+		"items": []interface{}{
+			map[string]interface{}{"id": "$matchEquals($.data.id)"},
+			map[string]interface{}{"id": "$matchEquals($.items[1].id)"},
+		},
+	}
+	assert.Empty(t, Compare(expected, actual, CompareParams{}))
+}
+
+func TestCompareReferencesErrors(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"total": 3, "items": [{"id": 7}, {"id": 8}], "data": {"id": 7}}`), &actual)
+
+	expected := map[string]interface{}{
+		"total": "$matchEquals($.items.length)",
+		"data":  map[string]interface{}{"id": "$matchEquals($.items[1].id)"},
+		"items": "$matchEquals($.missing)",
+	}
+	errors := Compare(expected, actual, CompareParams{})
+	assert.ElementsMatch(t, []string{
+		makeErrorString("$.total", "value does not equal $.items.length", 2, 3),
+		makeErrorString("$.data.id", "value does not equal $.items[1].id", 8, 7),
+		makeErrorString("$.items", "can not resolve $.missing", "no field missing", "[map[id:7] map[id:8]]"),
+	}, errorsToStrings(errors))
+}
+
+func TestCompareInt(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"id": 1, "price": 1.5, "count": 1.0, "total": 1e2, "name": "1", "approx": 2.0}`))
+	decoder.UseNumber()
+	var actual interface{}
+	require.NoError(t, decoder.Decode(&actual))
+
+	expected := map[string]interface{}{
+		"id":     "$matchInt",
+		"price":  1.5,
+		"count":  "$matchInt",
+		"total":  "$matchInt",
+		"name":   "$matchInt",
+		"approx": 2.0,
+	}
+	errors := Compare(expected, actual, CompareParams{})
+	assert.ElementsMatch(t, []string{
+		makeErrorString("$.count", "value is not an integer", "$matchInt", "1.0"),
+		makeErrorString("$.total", "value is not an integer", "$matchInt", "1e2"),
+		makeErrorString("$.name", "type mismatch", "number", "string"),
+	}, errorsToStrings(errors))
+}
+
+func TestCompareIntWithoutJSONNumber(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"id": 1, "price": 1.5}`), &actual)
+
+	errors := Compare(map[string]interface{}{"id": "$matchInt", "price": "$matchInt"}, actual, CompareParams{})
+	assert.Equal(t, []string{
+		makeErrorString("$.price", "value is not an integer", "$matchInt", 1.5),
+	}, errorsToStrings(errors))
+}
+
+func TestCompareWildcards(t *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{"id": 7, "name": null, "items": [{"id": 1}], "meta": {"page": 1}, "tags": ["a"], "mask": "*"}`), &actual)
+
+	expected := map[string]interface{}{
+		"id":    "*",
+		"name":  "*",
+		"items": []interface{}{map[string]interface{}{"id": "*"}},
+		"meta":  "**",
+		"tags":  "*",
+		"mask":  `\*`,
+	}
+	errors := Compare(expected, actual, CompareParams{Wildcard: "*"})
+	assert.Equal(t, []string{
+		makeErrorString("$.tags", "wildcard matches scalar values only", "scalar", "array"),
+	}, errorsToStrings(errors))
+}
+
+func TestCompareWildcardsDisabled(t *testing.T) {
+	errors := Compare(map[string]interface{}{"id": "*"}, map[string]interface{}{"id": 7.0}, CompareParams{})
+	assert.Len(t, errors, 1)
+}
+
+func TestCompareEscapedWildcard(t *testing.T) {
+	errors := Compare(`\**`, "abc", CompareParams{Wildcard: "*"})
+	assert.Equal(t, []string{
+		makeErrorString("$", "values do not match", "**", "abc"),
+	}, errorsToStrings(errors))
+}
@@ -0,0 +1,61 @@
+package compare
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var removePathSegmentRx = regexp.MustCompile(`^(?:\.([^.\[]+)|\[(\d+|\*)\])`)
+
+// RemovePath deletes the fields found by the path from the decoded JSON document, the elements
+// of arrays are set to null; [*] stands for all the elements, the paths not found are skipped
+func RemovePath(root interface{}, path string) error {
+	var segments [][]string
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		matches := removePathSegmentRx.FindStringSubmatch(rest)
+		if matches == nil {
+			return fmt.Errorf("invalid path %s at %s", path, rest)
+		}
+		segments = append(segments, matches)
+		rest = rest[len(matches[0]):]
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("path %s can not remove the whole document", path)
+	}
+	removeSegments(root, segments)
+	return nil
+}
+
+func removeSegments(value interface{}, segments [][]string) {
+	segment, last := segments[0], len(segments) == 1
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if segment[1] == "" {
+			return
+		}
+		if last {
+			delete(v, segment[1])
+			return
+		}
+		if child, ok := v[segment[1]]; ok {
+			removeSegments(child, segments[1:])
+		}
+	case []interface{}:
+		if segment[2] == "" {
+			return
+		}
+		for i := range v {
+			if segment[2] != "*" && segment[2] != strconv.Itoa(i) {
+				continue
+			}
+			if last {
+				v[i] = nil
+				continue
+			}
+			removeSegments(v[i], segments[1:])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemovePath(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"requestId": "a1",
+		"items": [{"id": 1, "updatedAt": "x"}, {"id": 2, "updatedAt": "y"}],
+		"tags": ["a", "b"]
+	}`), &doc))
+
+	require.NoError(t, RemovePath(doc, "$.requestId"))
+	require.NoError(t, RemovePath(doc, "$.items[*].updatedAt"))
+	require.NoError(t, RemovePath(doc, "$.tags[1]"))
+	require.NoError(t, RemovePath(doc, "$.missing.field"))
+
+	assert.Equal(t, map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1.0},
+			map[string]interface{}{"id": 2.0},
+		},
+		"tags": []interface{}{"a", nil},
+	}, doc)
+
+	assert.Error(t, RemovePath(doc, "$"))
+	assert.Error(t, RemovePath(doc, "$.items[-1]"))
+}
@@ -0,0 +1,150 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// DefaultDriver is the dialect used when no driver is specified in the config
+const DefaultDriver = "postgres"
+
+// Dialect generates SQL queries for the particular database
+type Dialect interface {
+	// QuoteIdentifier quotes name of a table or a column
+	QuoteIdentifier(name string) string
+	// QuoteLiteral escapes string to be safely passed as a value in SQL query
+	QuoteLiteral(value string) string
+	// FormatBool converts boolean to its SQL representation
+	FormatBool(value bool) string
+	// DefaultValue returns SQL used for the fields missing in some of the rows
+	DefaultValue() string
+	// TruncateQueries returns queries removing all the rows from the table
+	TruncateQueries(table string) []string
+	// InsertQuery returns query inserting the rows, values are already converted to SQL.
+	// The query must return inserted rows as JSON objects (single column)
+	// in the same order as the rows were passed.
+	InsertQuery(table string, fields []string, rows [][]string) string
+	// AfterLoadQueries returns queries issued after all the tables are loaded,
+	// e.g. to fix the sequences
+	AfterLoadQueries() []string
+}
+
+// CopyDialect is implemented by dialects able to load large tables in bulk
+type CopyDialect interface {
+	Dialect
+	// CopyQuery returns statement to be prepared in transaction, then executed
+	// for every row with its values and once without arguments to flush the data
+	CopyQuery(table string, fields []string) string
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Dialect{
+		DefaultDriver: postgresDialect{},
+		"sqlite3":     sqliteDialect{},
+	}
+)
+
+// RegisterDialect makes the dialect available by the driver name given in fixtures config,
+// registering the dialect with the same name replaces the previous one
+func RegisterDialect(driver string, dialect Dialect) {
+	if dialect == nil {
+		panic("fixtures: nil dialect for driver " + driver)
+	}
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[driver] = dialect
+}
+
+func getDialect(driver string) (Dialect, error) {
+	if driver == "" {
+		driver = DefaultDriver
+	}
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown fixtures driver %s", driver)
+	}
+	return dialect, nil
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return "\"" + name + "\""
+}
+
+func (postgresDialect) QuoteLiteral(s string) string {
+	var p string
+	if strings.Contains(s, `\`) {
+		p = "E"
+	}
+	s = strings.Replace(s, `'`, `''`, -1)
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return p + `'` + s + `'`
+}
+
+func (postgresDialect) FormatBool(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
+
+func (postgresDialect) DefaultValue() string {
+	return "default"
+}
+
+func (d postgresDialect) TruncateQueries(table string) []string {
+	return []string{fmt.Sprintf("TRUNCATE TABLE %s CASCADE", d.QuoteIdentifier(table))}
+}
+
+func (d postgresDialect) InsertQuery(table string, fields []string, rows [][]string) string {
+	quotedFields := make([]string, len(fields))
+	for i, field := range fields {
+		quotedFields[i] = d.QuoteIdentifier(field)
+	}
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = "(" + strings.Join(row, ", ") + ")"
+	}
+
+	tableAlias := table + tempTableSuffix // guarantees that table and column won't collide
+	query := "INSERT INTO %s AS %s (%s) VALUES %s RETURNING row_to_json(%[2]s)"
+	return fmt.Sprintf(query, d.QuoteIdentifier(table), tableAlias, strings.Join(quotedFields, ", "), strings.Join(values, ", "))
+}
+
+// AfterLoadQueries alters the sequences so they contain max id + 1
+func (postgresDialect) AfterLoadQueries() []string {
+	return []string{`
+DO $$
+DECLARE
+    r record;
+BEGIN
+    FOR r IN (
+        SELECT 'SELECT SETVAL(' || quote_literal(quote_ident(seq_ns.nspname) || '.' || quote_ident(seq.relname))
+            || ', COALESCE(MAX(' || quote_ident(col.attname) || '), 1) ) FROM '
+            || quote_ident(tbl_ns.nspname) || '.' || quote_ident(tbl.relname) AS q
+        FROM pg_class seq
+            JOIN pg_namespace seq_ns ON (seq.relnamespace = seq_ns.oid)
+            JOIN pg_depend dep ON (dep.objid = seq.oid)
+            JOIN pg_class tbl ON (dep.refobjid = tbl.oid)
+            JOIN pg_namespace tbl_ns ON (tbl.relnamespace = tbl_ns.oid)
+            JOIN pg_attribute col ON (col.attrelid = tbl.oid AND dep.refobjsubid = col.attnum)
+        WHERE
+            seq.relkind = 'S'
+        ORDER BY seq.relname
+    ) LOOP
+        EXECUTE r.q;
+    END LOOP;
+END$$
+`}
+}
+
+func (postgresDialect) CopyQuery(table string, fields []string) string {
+	return pq.CopyIn(table, fields...)
+}
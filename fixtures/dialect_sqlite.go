@@ -0,0 +1,58 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sqliteDialect generates queries for SQLite 3.35+ (RETURNING clause is required),
+// the driver itself is not imported, so any of them can be used
+type sqliteDialect struct{}
+
+func (sqliteDialect) QuoteIdentifier(name string) string {
+	return "\"" + strings.Replace(name, "\"", "\"\"", -1) + "\""
+}
+
+// QuoteLiteral escapes only quotes since backslash is not special in SQLite
+func (sqliteDialect) QuoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// FormatBool returns integer since SQLite has no boolean type
+func (sqliteDialect) FormatBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// DefaultValue returns NULL since SQLite doesn't support DEFAULT in VALUES list
+func (sqliteDialect) DefaultValue() string {
+	return "NULL"
+}
+
+func (d sqliteDialect) TruncateQueries(table string) []string {
+	return []string{fmt.Sprintf("DELETE FROM %s", d.QuoteIdentifier(table))}
+}
+
+// InsertQuery returns the inserted fields and rowid (as well as INTEGER PRIMARY KEY alias)
+// since the table columns are unknown
+func (d sqliteDialect) InsertQuery(table string, fields []string, rows [][]string) string {
+	quotedFields := make([]string, len(fields))
+	jsonFields := []string{"'rowid', rowid"}
+	for i, field := range fields {
+		quotedFields[i] = d.QuoteIdentifier(field)
+		jsonFields = append(jsonFields, d.QuoteLiteral(field)+", "+quotedFields[i])
+	}
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = "(" + strings.Join(row, ", ") + ")"
+	}
+
+	query := "INSERT INTO %s (%s) VALUES %s RETURNING json_object(%s)"
+	return fmt.Sprintf(query, d.QuoteIdentifier(table), strings.Join(quotedFields, ", "), strings.Join(values, ", "), strings.Join(jsonFields, ", "))
+}
+
+func (sqliteDialect) AfterLoadQueries() []string {
+	return nil
+}
@@ -0,0 +1,88 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type testDialect struct {
+	postgresDialect
+}
+
+func (testDialect) TruncateQueries(table string) []string {
+	return []string{"DELETE FROM " + table}
+}
+
+func (testDialect) InsertQuery(table string, fields []string, rows [][]string) string {
+	var values []string
+	for _, row := range rows {
+		values = append(values, "("+strings.Join(row, ",")+")")
+	}
+	return "INSERT " + table + " (" + strings.Join(fields, ",") + ") " + strings.Join(values, ",")
+}
+
+func (testDialect) AfterLoadQueries() []string {
+	return nil
+}
+
+func TestLoadTablesShouldUseRegisteredDialect(t *testing.T) {
+	RegisterDialect("test", testDialect{})
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+	l := NewLoader(&Config{DB: db, Driver: "test"})
+	require.NoError(t, l.loadYml([]byte("tables:\n  table1:\n    - f1: value1\n      f2: true\n"), &ctx))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM table1$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT table1 \(f1,f2\) \('value1',true\)$`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"f1":"value1","f2":true}`))
+	mock.ExpectCommit()
+
+	assert.NoError(t, l.loadTables(&ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadShouldFailOnUnknownDriver(t *testing.T) {
+	l := NewLoader(&Config{Driver: "unknown"})
+	assert.EqualError(t, l.Load([]string{"fixture"}), "unknown fixtures driver unknown")
+}
+
+func TestLoadTablesShouldUseSQLiteDialect(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+	yml := "tables:\n  table1:\n    - $name: ref1\n      f1: it's\n      f2: true\n    - f1: c:\\dir\n"
+	l := NewLoader(&Config{DB: db, Driver: "sqlite3"})
+	require.NoError(t, l.loadYml([]byte(yml), &ctx))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^DELETE FROM "table1"$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT INTO "table1" \("f1", "f2"\) VALUES \('it''s', 1\), \('c:\\dir', NULL\) ` +
+		`RETURNING json_object\('rowid', rowid, 'f1', "f1", 'f2', "f2"\)$`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).
+			AddRow(`{"rowid":1,"f1":"it's","f2":1}`).
+			AddRow(`{"rowid":2,"f1":"c:\\dir","f2":null}`))
+	mock.ExpectCommit()
+
+	assert.NoError(t, l.loadTables(&ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, float64(1), ctx.refsInserted["ref1"]["rowid"])
+}
@@ -5,19 +5,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
-	_ "github.com/lib/pq"
 	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/internal/files"
+	"github.com/lamoda/gonkey/models"
 )
 
 const tempTableSuffix = "_table_gonkey"
 
+// tables with at least this number of rows are loaded with COPY instead of INSERT
+const defaultCopyThreshold = 1000
+
+// large tables are inserted with several queries of this number of rows
+const defaultInsertChunkSize = 1000
+
 type row map[string]interface{}
 
 type table []row
@@ -29,6 +37,8 @@ type fixture struct {
 	Inherits  []string
 	Tables    yaml.MapSlice
 	Templates yaml.MapSlice
+	// Collections are the tables of MongoDB fixtures
+	Collections yaml.MapSlice
 }
 
 type loadedTable struct {
@@ -41,41 +51,180 @@ type loadContext struct {
 	tables         []loadedTable
 	refsDefinition rowsDict
 	refsInserted   rowsDict
+	tablesLoaded   int
+	tablesTotal    int
+}
+
+// Progress describes the state of fixtures loading
+type Progress struct {
+	Table string
+	// TableRows of TableTotal rows of the table are loaded
+	TableRows  int
+	TableTotal int
+	// Tables of TablesTotal tables are loaded completely
+	Tables      int
+	TablesTotal int
+}
+
+// LoaderInterface loads the fixtures of the tests into the database, see Loader and MongoLoader
+type LoaderInterface interface {
+	Load(names []string) error
+	LoadWithInline(names []string, inline []string) error
+	Clean(names []string) ([]models.TableCleanup, error)
+	CleanWithInline(names []string, inline []string) ([]models.TableCleanup, error)
 }
 
 type Config struct {
 	DB       *sql.DB
 	Location string
 	Debug    bool
+	// DebugOutput receives the debug output instead of stdout, e.g. to attach it to the report
+	DebugOutput io.Writer
+	// Driver is the name of the dialect registered with RegisterDialect, postgres by default
+	Driver string
+	// OnProgress is called every time a part of a table is loaded
+	OnProgress func(Progress)
+	// CheckCleanup makes Clean fail if the number of deleted rows of a table
+	// differs from the number of its rows in the fixtures, e.g. the tests leaked data
+	CheckCleanup bool
 }
 
 type Loader struct {
-	db       *sql.DB
-	location string
-	debug    bool
+	db            *sql.DB
+	location      string
+	debug         bool
+	debugOutput   io.Writer
+	copyThreshold int
+	chunkSize     int
+	dialect       Dialect
+	dialectErr    error
+	onProgress    func(Progress)
+	checkCleanup  bool
+	// collections are accepted only by the loader of MongoLoader
+	collections bool
 }
 
 func NewLoader(config *Config) *Loader {
+	dialect, err := getDialect(config.Driver)
 	return &Loader{
-		db:       config.DB,
-		location: strings.TrimRight(config.Location, "/"),
-		debug:    config.Debug,
+		db:            config.DB,
+		location:      strings.TrimRight(config.Location, "/"),
+		debug:         config.Debug,
+		debugOutput:   debugOutput(config.DebugOutput),
+		copyThreshold: defaultCopyThreshold,
+		chunkSize:     defaultInsertChunkSize,
+		dialect:       dialect,
+		dialectErr:    err,
+		onProgress:    config.OnProgress,
+		checkCleanup:  config.CheckCleanup,
+	}
+}
+
+func debugOutput(w io.Writer) io.Writer {
+	if w == nil {
+		return os.Stdout
 	}
+	return w
 }
 
 func (f *Loader) Load(names []string) error {
-	ctx := loadContext{
+	return f.LoadWithInline(names, nil)
+}
+
+// LoadWithInline loads the fixtures files and then the fixtures defined in the test,
+// which are YAML documents of the same format as the files
+func (f *Loader) LoadWithInline(names []string, inline []string) error {
+	if f.dialectErr != nil {
+		return f.dialectErr
+	}
+	ctx, err := f.gather(names, inline)
+	if err != nil {
+		return err
+	}
+	return f.loadTables(ctx)
+}
+
+// gather reads the data of the fixtures without loading it
+func (f *Loader) gather(names []string, inline []string) (*loadContext, error) {
+	ctx := &loadContext{
 		refsDefinition: make(rowsDict),
 		refsInserted:   make(rowsDict),
 	}
-	// gather data from files
 	for _, name := range names {
-		err := f.loadFile(name, &ctx)
+		err := f.loadFile(name, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load fixture %s: %s", name, err.Error())
+		}
+	}
+	for i, data := range inline {
+		if err := f.loadYml([]byte(data), ctx); err != nil {
+			return nil, fmt.Errorf("unable to load inline fixture #%d: %s", i+1, err.Error())
+		}
+	}
+	return ctx, nil
+}
+
+// Clean truncates the tables of the fixtures without loading the data
+// and reports the number of rows deleted from every table
+func (f *Loader) Clean(names []string) ([]models.TableCleanup, error) {
+	return f.CleanWithInline(names, nil)
+}
+
+// CleanWithInline truncates the tables of the fixtures files and of the fixtures defined in the tests
+func (f *Loader) CleanWithInline(names []string, inline []string) ([]models.TableCleanup, error) {
+	if f.dialectErr != nil {
+		return nil, f.dialectErr
+	}
+	ctx, err := f.gather(names, inline)
+	if err != nil {
+		return nil, err
+	}
+	var cleanups []models.TableCleanup
+	indexes := make(map[string]int)
+	for _, lt := range ctx.tables {
+		if i, ok := indexes[lt.Name]; ok {
+			cleanups[i].Inserted += len(lt.Rows)
+			continue
+		}
+		indexes[lt.Name] = len(cleanups)
+		cleanups = append(cleanups, models.TableCleanup{Table: lt.Name, Inserted: len(lt.Rows)})
+	}
+	for i := range cleanups {
+		deleted, err := f.countRows(cleanups[i].Table)
 		if err != nil {
-			return fmt.Errorf("unable to load fixture %s: %s", name, err.Error())
+			return nil, err
+		}
+		if err := f.truncateTable(cleanups[i].Table); err != nil {
+			return nil, err
+		}
+		cleanups[i].Deleted = deleted
+		if f.debug {
+			fmt.Fprintf(f.debugOutput, "Deleted %d rows from %s, %d inserted by the fixtures\n",
+				deleted, cleanups[i].Table, cleanups[i].Inserted)
+		}
+	}
+	if f.checkCleanup {
+		for _, c := range cleanups {
+			if c.Deleted != c.Inserted {
+				return cleanups, fmt.Errorf("%d rows deleted from %s, %d inserted by the fixtures",
+					c.Deleted, c.Table, c.Inserted)
+			}
 		}
 	}
-	return f.loadTables(&ctx)
+	return cleanups, nil
+}
+
+// countRows returns the number of rows in the table
+func (f *Loader) countRows(name string) (int, error) {
+	query := "SELECT COUNT(*) FROM " + f.dialect.QuoteIdentifier(name)
+	if f.debug {
+		fmt.Fprintln(f.debugOutput, "Issuing SQL:", query)
+	}
+	var count int
+	if err := f.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("unable to count rows of %s: %s", name, err.Error())
+	}
+	return count, nil
 }
 
 func (f *Loader) loadFile(name string, ctx *loadContext) error {
@@ -83,6 +232,8 @@ func (f *Loader) loadFile(name string, ctx *loadContext) error {
 		f.location + "/" + name,
 		f.location + "/" + name + ".yml",
 		f.location + "/" + name + ".yaml",
+		f.location + "/" + name + ".yml.gz",
+		f.location + "/" + name + ".yaml.gz",
 	}
 	var err error
 	var file string
@@ -100,9 +251,10 @@ func (f *Loader) loadFile(name string, ctx *loadContext) error {
 		return nil
 	}
 	if f.debug {
-		fmt.Println("Loading", file)
+		fmt.Fprintln(f.debugOutput, "Loading", file)
 	}
-	data, err := ioutil.ReadFile(file)
+	// compressed fixtures are decompressed, e.g. users.yml.gz
+	data, err := files.ReadFile(file)
 	if err != nil {
 		return err
 	}
@@ -117,6 +269,10 @@ func (f *Loader) loadYml(data []byte, ctx *loadContext) error {
 		return err
 	}
 
+	if len(loadedFixture.Collections) > 0 && !f.collections {
+		return errors.New("`collections` are supported only by MongoDB fixtures, use `tables`")
+	}
+
 	// load inherits
 	for _, inheritFile := range loadedFixture.Inherits {
 		if err := f.loadFile(inheritFile, ctx); err != nil {
@@ -156,7 +312,7 @@ func (f *Loader) loadYml(data []byte, ctx *loadContext) error {
 		ctx.refsDefinition[name] = row
 		if f.debug {
 			rowJson, _ := json.Marshal(row)
-			fmt.Printf("Populating ref %s as %s from template\n", name, string(rowJson))
+			fmt.Fprintf(f.debugOutput, "Populating ref %s as %s from template\n", name, string(rowJson))
 		}
 	}
 
@@ -168,7 +324,7 @@ func (f *Loader) loadYml(data []byte, ctx *loadContext) error {
 	//        }
 	//    }
 	// }
-	for _, sourceTable := range loadedFixture.Tables {
+	for _, sourceTable := range append(loadedFixture.Tables, loadedFixture.Collections...) {
 		sourceRows, ok := sourceTable.Value.([]interface{})
 		if !ok {
 			return errors.New("expected array at root level")
@@ -211,6 +367,11 @@ func (f *Loader) loadTables(ctx *loadContext) error {
 		truncatedTables[lt.Name] = true
 	}
 	// then load data
+	for _, lt := range ctx.tables {
+		if len(lt.Rows) > 0 {
+			ctx.tablesTotal++
+		}
+	}
 	for _, lt := range ctx.tables {
 		if len(lt.Rows) == 0 {
 			continue
@@ -218,10 +379,13 @@ func (f *Loader) loadTables(ctx *loadContext) error {
 		if err := f.loadTable(ctx, lt.Name, lt.Rows); err != nil {
 			return err
 		}
+		ctx.tablesLoaded++
 	}
-	// alter the sequences so they contain max id + 1
-	if err := f.fixSequences(); err != nil {
-		return err
+	// dialect specific post processing, e.g. fixing the sequences
+	for _, query := range f.dialect.AfterLoadQueries() {
+		if err := f.exec(query); err != nil {
+			return err
+		}
 	}
 
 	tx.Commit()
@@ -230,15 +394,20 @@ func (f *Loader) loadTables(ctx *loadContext) error {
 
 // truncateTable truncates table
 func (f *Loader) truncateTable(name string) error {
-	query := fmt.Sprintf("TRUNCATE TABLE \"%s\" CASCADE", name)
+	for _, query := range f.dialect.TruncateQueries(name) {
+		if err := f.exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Loader) exec(query string) error {
 	if f.debug {
-		fmt.Println("Issuing SQL:", query)
+		fmt.Fprintln(f.debugOutput, "Issuing SQL:", query)
 	}
 	_, err := f.db.Exec(query)
-	if err != nil {
-		return err
-	}
-	return nil
+	return err
 }
 
 func (f *Loader) loadTable(ctx *loadContext, t string, rows table) error {
@@ -256,13 +425,58 @@ func (f *Loader) loadTable(ctx *loadContext, t string, rows table) error {
 			rows[i] = baseRow
 		}
 	}
+	// large tables without references are loaded much faster with COPY
+	if fields, ok := f.copyFields(rows); ok {
+		if err := f.copyTable(t, fields, rows); err != nil {
+			return err
+		}
+		f.reportProgress(ctx, t, len(rows), len(rows))
+		return nil
+	}
+	// insert large tables by chunks to report the progress
+	for start := 0; start < len(rows); start += f.chunkSize {
+		end := start + f.chunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := f.insertRows(ctx, t, rows[start:end]); err != nil {
+			return err
+		}
+		f.reportProgress(ctx, t, end, len(rows))
+	}
+	return nil
+}
+
+// reportProgress prints the progress in debug mode and passes it to the callback
+func (f *Loader) reportProgress(ctx *loadContext, t string, loaded, total int) {
+	progress := Progress{
+		Table:       t,
+		TableRows:   loaded,
+		TableTotal:  total,
+		Tables:      ctx.tablesLoaded,
+		TablesTotal: ctx.tablesTotal,
+	}
+	if loaded == total {
+		progress.Tables++
+	}
+	if f.debug {
+		fmt.Fprintf(f.debugOutput, "Loaded %d/%d rows into %s, %d/%d tables\n",
+			loaded, total, t, progress.Tables, progress.TablesTotal)
+	}
+	if f.onProgress != nil {
+		f.onProgress(progress)
+	}
+}
+
+// insertRows inserts the rows with single query and populates the references
+func (f *Loader) insertRows(ctx *loadContext, t string, rows table) error {
 	// build SQL
 	query, err := f.buildInsertQuery(ctx, t, rows)
 	if err != nil {
 		return err
 	}
 	if f.debug {
-		fmt.Println("Issuing SQL:", query)
+		fmt.Fprintln(f.debugOutput, "Issuing SQL:", query)
 	}
 	// issuing query
 	insertedRows, err := f.db.Query(query)
@@ -296,47 +510,90 @@ func (f *Loader) loadTable(ctx *loadContext, t string, rows table) error {
 			ctx.refsDefinition[name] = row
 			if f.debug {
 				rowJson, _ := json.Marshal(row)
-				fmt.Printf("Populating ref %s as %s from row definition\n", name, string(rowJson))
+				fmt.Fprintf(f.debugOutput, "Populating ref %s as %s from row definition\n", name, string(rowJson))
 			}
 			ctx.refsInserted[name] = values
 			if f.debug {
 				valuesJson, _ := json.Marshal(values)
-				fmt.Printf("Populating ref %s as %s from inserted values\n", name, string(valuesJson))
+				fmt.Fprintf(f.debugOutput, "Populating ref %s as %s from inserted values\n", name, string(valuesJson))
 			}
 		}
 	}
 	return err
 }
 
-func (f *Loader) fixSequences() error {
-	query := `
-DO $$
-DECLARE
-    r record;
-BEGIN
-    FOR r IN (
-        SELECT 'SELECT SETVAL(' || quote_literal(quote_ident(seq_ns.nspname) || '.' || quote_ident(seq.relname))
-            || ', COALESCE(MAX(' || quote_ident(col.attname) || '), 1) ) FROM '
-            || quote_ident(tbl_ns.nspname) || '.' || quote_ident(tbl.relname) AS q
-        FROM pg_class seq
-            JOIN pg_namespace seq_ns ON (seq.relnamespace = seq_ns.oid)
-            JOIN pg_depend dep ON (dep.objid = seq.oid)
-            JOIN pg_class tbl ON (dep.refobjid = tbl.oid)
-            JOIN pg_namespace tbl_ns ON (tbl.relnamespace = tbl_ns.oid)
-            JOIN pg_attribute col ON (col.attrelid = tbl.oid AND dep.refobjsubid = col.attnum)
-        WHERE
-            seq.relkind = 'S'
-        ORDER BY seq.relname
-    ) LOOP
-        EXECUTE r.q;
-    END LOOP;
-END$$
-`
+// copyFields returns the list of fields if the rows can be loaded with COPY,
+// that is the table is large enough, rows are not referenced, don't use expressions
+// and all of them have the same set of fields (COPY can't insert default values)
+func (f *Loader) copyFields(rows table) ([]string, bool) {
+	if _, ok := f.dialect.(CopyDialect); !ok {
+		return nil, false
+	}
+	if f.copyThreshold <= 0 || len(rows) < f.copyThreshold {
+		return nil, false
+	}
+	var fields []string
+	for i, row := range rows {
+		if _, ok := row["$name"]; ok {
+			return nil, false
+		}
+		var rowFields []string
+		for name, value := range row {
+			if len(name) > 0 && name[0] == '$' {
+				continue
+			}
+			if stringValue, ok := value.(string); ok && len(stringValue) > 0 && stringValue[0] == '$' {
+				return nil, false
+			}
+			rowFields = append(rowFields, name)
+		}
+		sort.Strings(rowFields)
+		if i == 0 {
+			fields = rowFields
+			continue
+		}
+		if strings.Join(rowFields, ",") != strings.Join(fields, ",") {
+			return nil, false
+		}
+	}
+	return fields, len(fields) > 0
+}
+
+// copyTable loads rows using COPY FROM STDIN
+func (f *Loader) copyTable(t string, fields []string, rows table) error {
 	if f.debug {
-		fmt.Println("Issuing SQL:", query)
+		fmt.Fprintf(f.debugOutput, "Issuing COPY into %s of %d rows\n", t, len(rows))
 	}
-	_, err := f.db.Exec(query)
-	return err
+	tx, err := f.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(f.dialect.(CopyDialect).CopyQuery(t, fields))
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(fields))
+	for i, row := range rows {
+		for k, name := range fields {
+			values[k], err = toCopyValue(row[name])
+			if err != nil {
+				return fmt.Errorf("unable to process %s value (row %d of %s): %s", name, i, t, err.Error())
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return err
+		}
+	}
+	// flush buffered data
+	if _, err := stmt.Exec(); err != nil {
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // buildInsertQuery builds SQL query for data insertion
@@ -358,13 +615,13 @@ func (f *Loader) buildInsertQuery(ctx *loadContext, t string, rows table) (strin
 	}
 	sort.Strings(fields)
 	// second pass, collecting values
-	dbValues := make([]string, len(rows))
+	dbValues := make([][]string, len(rows))
 	for i, row := range rows {
 		dbValuesRow := make([]string, len(fields))
 		for k, name := range fields {
 			value, present := row[name]
 			if !present {
-				dbValuesRow[k] = "default" // default is a PostgreSQL keyword
+				dbValuesRow[k] = f.dialect.DefaultValue()
 				continue
 			}
 			// resolve references
@@ -378,22 +635,15 @@ func (f *Loader) buildInsertQuery(ctx *loadContext, t string, rows table) (strin
 					continue
 				}
 			}
-			dbValue, err := toDbValue(value)
+			dbValue, err := f.toDbValue(value)
 			if err != nil {
 				return "", fmt.Errorf("unable to process %s value (row %d of %s): %s", name, i, t, err.Error())
 			}
 			dbValuesRow[k] = dbValue
 		}
-		dbValues[i] = "(" + strings.Join(dbValuesRow, ", ") + ")"
-	}
-	// quote fields
-	for i, field := range fields {
-		fields[i] = "\"" + field + "\""
+		dbValues[i] = dbValuesRow
 	}
-
-	tableAlias := t + tempTableSuffix // guarantees that table and column won't collide
-	query := "INSERT INTO \"%s\" AS %s (%s) VALUES %s RETURNING row_to_json(%[2]s)"
-	return fmt.Sprintf(query, t, tableAlias, strings.Join(fields, ", "), strings.Join(dbValues, ", ")), nil
+	return f.dialect.InsertQuery(t, fields, dbValues), nil
 }
 
 // resolveExpression converts expressions starting with dollar sign into a value
@@ -413,7 +663,7 @@ func (f *Loader) resolveExpression(expr string, ctx *loadContext) (string, error
 		if err != nil {
 			return "", nil
 		}
-		return toDbValue(value)
+		return f.toDbValue(value)
 	}
 }
 
@@ -466,12 +716,12 @@ func inArray(needle string, haystack *[]string) bool {
 
 // toDbValue prepares value to be passed in SQL query
 // with respect to its type and converts it to string
-func toDbValue(value interface{}) (string, error) {
+func (f *Loader) toDbValue(value interface{}) (string, error) {
 	if value == nil {
 		return "NULL", nil
 	}
 	if value, ok := value.(string); ok {
-		return quoteLiteral(value), nil
+		return f.dialect.QuoteLiteral(value), nil
 	}
 	if value, ok := value.(int); ok {
 		return strconv.Itoa(value), nil
@@ -480,7 +730,7 @@ func toDbValue(value interface{}) (string, error) {
 		return strconv.FormatFloat(value, 'g', -1, 64), nil
 	}
 	if value, ok := value.(bool); ok {
-		return strconv.FormatBool(value), nil
+		return f.dialect.FormatBool(value), nil
 	}
 	// the value is either slice or map, so insert it as JSON string
 	// fixme: marshaller doesn't know how to encode map[interface{}]interface{}
@@ -488,17 +738,22 @@ func toDbValue(value interface{}) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return quoteLiteral(string(encoded)), nil
+	return f.dialect.QuoteLiteral(string(encoded)), nil
 }
 
-// quoteLiteral properly escapes string to be safely
-// passed as a value in SQL query
-func quoteLiteral(s string) string {
-	var p string
-	if strings.Contains(s, `\`) {
-		p = "E"
+// toCopyValue prepares value to be passed to COPY, the driver
+// takes care of its encoding to COPY text format
+func toCopyValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case nil, string, bool, float64:
+		return value, nil
+	case int:
+		return int64(value), nil
+	}
+	// the value is either slice or map, so insert it as JSON string
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
 	}
-	s = strings.Replace(s, `'`, `''`, -1)
-	s = strings.Replace(s, `\`, `\\`, -1)
-	return p + `'` + s + `'`
+	return string(encoded), nil
 }
@@ -1,9 +1,19 @@
 package fixtures
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/lamoda/gonkey/models"
 )
 
 func TestBuildInsertQuery(t *testing.T) {
@@ -249,3 +259,282 @@ tables:
 		t.Fail()
 	}
 }
+
+func TestLoadTablesShouldCopyLargeTables(t *testing.T) {
+	yml := `
+tables:
+  table1:
+    - f1: value1
+      f2: 1
+    - f1: value2
+      f2: null
+    - f1: value3
+      f2:
+        - 1
+        - '2'
+`
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	l := NewLoader(&Config{DB: db, Debug: true})
+	l.copyThreshold = 3
+
+	err = l.loadYml([]byte(yml), &ctx)
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+	}
+
+	mock.ExpectBegin()
+
+	mock.ExpectExec("^TRUNCATE TABLE \"table1\" CASCADE$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectBegin()
+
+	copyStmt := mock.ExpectPrepare(`^COPY "table1" \("f1", "f2"\) FROM STDIN$`)
+	copyStmt.ExpectExec().
+		WithArgs("value1", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().
+		WithArgs("value2", nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().
+		WithArgs("value3", `[1,"2"]`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	copyStmt.ExpectExec().
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	mock.ExpectCommit()
+
+	mock.ExpectExec("^DO").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err = l.loadTables(&ctx)
+	if err != nil {
+		t.Error(err)
+		t.Fail()
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		t.Fail()
+	}
+}
+
+func TestCopyFieldsShouldFallbackToInsert(t *testing.T) {
+	l := NewLoader(&Config{})
+	l.copyThreshold = 2
+
+	cases := map[string]table{
+		"small table":     {{"f1": "value1"}},
+		"named row":       {{"$name": "ref", "f1": "value1"}, {"f1": "value2"}},
+		"expression":      {{"f1": "$eval(NOW())"}, {"f1": "value2"}},
+		"different field": {{"f1": "value1"}, {"f2": "value2"}},
+	}
+	for name, rows := range cases {
+		if _, ok := l.copyFields(rows); ok {
+			t.Errorf("%s: must be loaded with INSERT", name)
+		}
+	}
+
+	fields, ok := l.copyFields(table{{"$extend": "tpl", "f2": 1, "f1": "a"}, {"f1": "b", "f2": 2}})
+	if !ok || len(fields) != 2 || fields[0] != "f1" || fields[1] != "f2" {
+		t.Errorf("must be loaded with COPY, got fields %v", fields)
+	}
+}
+
+func TestLoadTablesShouldInsertByChunksAndReportProgress(t *testing.T) {
+	yml := `
+tables:
+  table1:
+    - f1: value1
+    - f1: value2
+    - $name: ref3
+      f1: value3
+  table2:
+    - f1: $ref3.f1
+`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	var progress []Progress
+	l := NewLoader(&Config{DB: db, OnProgress: func(p Progress) { progress = append(progress, p) }})
+	l.chunkSize = 2
+	require.NoError(t, l.loadYml([]byte(yml), &ctx))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^TRUNCATE TABLE "table1" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`^TRUNCATE TABLE "table2" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT INTO "table1" AS table1_table_gonkey \("f1"\) VALUES \('value1'\), \('value2'\) `).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"f1":"value1"}`).AddRow(`{"f1":"value2"}`))
+	mock.ExpectQuery(`^INSERT INTO "table1" AS table1_table_gonkey \("f1"\) VALUES \('value3'\) `).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"f1":"value3"}`))
+	mock.ExpectQuery(`^INSERT INTO "table2" AS table2_table_gonkey \("f1"\) VALUES \('value3'\) `).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"f1":"value3"}`))
+	mock.ExpectExec("^DO").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, l.loadTables(&ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []Progress{
+		{Table: "table1", TableRows: 2, TableTotal: 3, Tables: 0, TablesTotal: 2},
+		{Table: "table1", TableRows: 3, TableTotal: 3, Tables: 1, TablesTotal: 2},
+		{Table: "table2", TableRows: 1, TableTotal: 1, Tables: 2, TablesTotal: 2},
+	}, progress)
+}
+
+func TestCleanShouldTruncateTablesOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	yml := "tables:\n  table1:\n    - f1: value1\n  table2:\n    - f1: value2\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "suite.yml"), []byte(yml), 0644))
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "table1"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`^TRUNCATE TABLE "table1" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "table2"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(`^TRUNCATE TABLE "table2" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	l := NewLoader(&Config{DB: db, Location: dir})
+	cleanups, err := l.Clean([]string{"suite"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.TableCleanup{
+		{Table: "table1", Deleted: 1, Inserted: 1},
+		{Table: "table2", Deleted: 3, Inserted: 1},
+	}, cleanups)
+}
+
+func TestCleanShouldFailOnLeakedRowsIfChecked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	yml := "tables:\n  table1:\n    - f1: value1\n    - f1: value2\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "suite.yml"), []byte(yml), 0644))
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "table1"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectExec(`^TRUNCATE TABLE "table1" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	l := NewLoader(&Config{DB: db, Location: dir, CheckCleanup: true})
+	_, err = l.Clean([]string{"suite"})
+	assert.EqualError(t, err, "5 rows deleted from table1, 2 inserted by the fixtures")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadFileShouldDecompressGzipFixture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write([]byte("tables:\n  table1:\n    - f1: value1\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "large.yml.gz"), buf.Bytes(), 0644))
+
+	ctx := loadContext{
+		refsDefinition: make(rowsDict),
+		refsInserted:   make(rowsDict),
+	}
+	l := NewLoader(&Config{Location: dir})
+	require.NoError(t, l.loadFile("large", &ctx))
+
+	require.Len(t, ctx.tables, 1)
+	assert.Equal(t, "table1", ctx.tables[0].Name)
+	assert.Equal(t, "value1", ctx.tables[0].Rows[0]["f1"])
+}
+
+func TestLoadTablesShouldWriteDebugOutput(t *testing.T) {
+	yml := `
+tables:
+  table1:
+    - f1: value1
+`
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	var output bytes.Buffer
+	l := NewLoader(&Config{DB: db, Debug: true, DebugOutput: &output})
+	require.NoError(t, l.loadYml([]byte(yml), &ctx))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^TRUNCATE TABLE \"table1\" CASCADE$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT INTO "table1"`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"f1":"value1"}`))
+	mock.ExpectExec("^DO").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, l.loadTables(&ctx))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	log := output.String()
+	truncate := strings.Index(log, `Issuing SQL: TRUNCATE TABLE "table1" CASCADE`)
+	insert := strings.Index(log, `Issuing SQL: INSERT INTO "table1"`)
+	assert.NotEqual(t, -1, truncate)
+	assert.Greater(t, insert, truncate)
+}
+
+func TestLoadYmlShouldRejectCollectionsOfSQLFixtures(t *testing.T) {
+	yml := `
+collections:
+  users:
+    - name: Alice
+`
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	err := NewLoader(&Config{}).loadYml([]byte(yml), &ctx)
+
+	assert.EqualError(t, err, "`collections` are supported only by MongoDB fixtures, use `tables`")
+	assert.Empty(t, ctx.tables)
+}
@@ -0,0 +1,252 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+type MongoConfig struct {
+	Database *mongo.Database
+	Location string
+	Debug    bool
+	// DebugOutput receives the debug output instead of stdout
+	DebugOutput io.Writer
+	// CheckCleanup makes Clean fail if the number of deleted documents of a collection
+	// differs from the number of its documents in the fixtures
+	CheckCleanup bool
+}
+
+// MongoLoader loads the fixtures of the same format as Loader, the tables being the collections.
+// $extend, templates, inherits and $name references are supported, $eval is not
+type MongoLoader struct {
+	db           *mongo.Database
+	files        *Loader
+	debug        bool
+	debugOutput  io.Writer
+	checkCleanup bool
+}
+
+func NewMongoLoader(config *MongoConfig) *MongoLoader {
+	files := NewLoader(&Config{Location: config.Location, Debug: config.Debug, DebugOutput: config.DebugOutput})
+	files.collections = true
+	return &MongoLoader{
+		db:           config.Database,
+		files:        files,
+		debug:        config.Debug,
+		debugOutput:  debugOutput(config.DebugOutput),
+		checkCleanup: config.CheckCleanup,
+	}
+}
+
+func (f *MongoLoader) Load(names []string) error {
+	return f.LoadWithInline(names, nil)
+}
+
+// LoadWithInline clears the collections of the fixtures and inserts their documents
+func (f *MongoLoader) LoadWithInline(names []string, inline []string) error {
+	ctx, err := f.files.gather(names, inline)
+	if err != nil {
+		return err
+	}
+	cleared := make(map[string]bool)
+	for _, lt := range ctx.tables {
+		if cleared[lt.Name] {
+			continue
+		}
+		if _, err := f.clearCollection(lt.Name); err != nil {
+			return err
+		}
+		cleared[lt.Name] = true
+	}
+	for _, lt := range ctx.tables {
+		if len(lt.Rows) == 0 {
+			continue
+		}
+		if err := f.insertDocuments(ctx, lt.Name, lt.Rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *MongoLoader) Clean(names []string) ([]models.TableCleanup, error) {
+	return f.CleanWithInline(names, nil)
+}
+
+// CleanWithInline clears the collections of the fixtures files and of the fixtures defined in the tests
+func (f *MongoLoader) CleanWithInline(names []string, inline []string) ([]models.TableCleanup, error) {
+	ctx, err := f.files.gather(names, inline)
+	if err != nil {
+		return nil, err
+	}
+	var cleanups []models.TableCleanup
+	indexes := make(map[string]int)
+	for _, lt := range ctx.tables {
+		if i, ok := indexes[lt.Name]; ok {
+			cleanups[i].Inserted += len(lt.Rows)
+			continue
+		}
+		indexes[lt.Name] = len(cleanups)
+		cleanups = append(cleanups, models.TableCleanup{Table: lt.Name, Inserted: len(lt.Rows)})
+	}
+	for i := range cleanups {
+		deleted, err := f.clearCollection(cleanups[i].Table)
+		if err != nil {
+			return nil, err
+		}
+		cleanups[i].Deleted = deleted
+		if f.debug {
+			fmt.Fprintf(f.debugOutput, "Deleted %d documents from %s, %d inserted by the fixtures\n",
+				deleted, cleanups[i].Table, cleanups[i].Inserted)
+		}
+	}
+	if f.checkCleanup {
+		for _, c := range cleanups {
+			if c.Deleted != c.Inserted {
+				return cleanups, fmt.Errorf("%d documents deleted from %s, %d inserted by the fixtures",
+					c.Deleted, c.Table, c.Inserted)
+			}
+		}
+	}
+	return cleanups, nil
+}
+
+// clearCollection deletes all the documents of the collection keeping its indexes
+func (f *MongoLoader) clearCollection(name string) (int, error) {
+	if f.debug {
+		fmt.Fprintln(f.debugOutput, "Clearing collection", name)
+	}
+	res, err := f.db.Collection(name).DeleteMany(context.Background(), bson.D{})
+	if err != nil {
+		return 0, fmt.Errorf("unable to clear collection %s: %s", name, err.Error())
+	}
+	return int(res.DeletedCount), nil
+}
+
+// insertDocuments inserts the documents in order, the references are populated while they are built
+func (f *MongoLoader) insertDocuments(ctx *loadContext, collection string, rows table) error {
+	docs, err := f.buildDocuments(ctx, rows)
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %s", collection, err.Error())
+	}
+	if f.debug {
+		fmt.Fprintf(f.debugOutput, "Inserting %d documents into %s\n", len(docs), collection)
+	}
+	if _, err := f.db.Collection(collection).InsertMany(context.Background(), docs); err != nil {
+		return fmt.Errorf("unable to insert into %s: %s", collection, err.Error())
+	}
+	return nil
+}
+
+// buildDocuments applies $extend and resolves the references of the rows,
+// the named documents get _id generated by the loader unless the fixture sets it,
+// so the later documents of the same collection can reference them
+func (f *MongoLoader) buildDocuments(ctx *loadContext, rows table) ([]interface{}, error) {
+	docs := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if base, ok := row["$extend"].(string); ok {
+			baseRow, err := f.files.resolveReference(ctx.refsDefinition, base)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range row {
+				baseRow[k] = v
+			}
+			rows[i] = baseRow
+			row = baseRow
+		}
+
+		fields := make([]string, 0, len(row))
+		for name := range row {
+			if len(name) > 0 && name[0] == '$' {
+				continue
+			}
+			fields = append(fields, name)
+		}
+		sort.Strings(fields)
+
+		name, named := row["$name"].(string)
+		doc := make(bson.D, 0, len(fields)+1)
+		if _, ok := row["_id"]; named && !ok {
+			doc = append(doc, bson.E{Key: "_id", Value: primitive.NewObjectID()})
+		}
+		for _, field := range fields {
+			value, err := f.toMongoValue(ctx, row[field])
+			if err != nil {
+				return nil, fmt.Errorf("unable to process %s value (document %d): %s", field, i, err.Error())
+			}
+			doc = append(doc, bson.E{Key: field, Value: value})
+		}
+		docs[i] = doc
+
+		if !named {
+			continue
+		}
+		if _, ok := ctx.refsDefinition[name]; ok {
+			return nil, fmt.Errorf("duplicating ref name %s", name)
+		}
+		values := documentValues(doc)
+		ctx.refsDefinition[name] = row
+		ctx.refsInserted[name] = values
+		if f.debug {
+			valuesJson, _ := json.Marshal(values)
+			fmt.Fprintf(f.debugOutput, "Populating ref %s as %s from document\n", name, string(valuesJson))
+		}
+	}
+	return docs, nil
+}
+
+// toMongoValue converts the YAML value keeping the order of the nested maps,
+// $recordName.fieldName is the value of the previously inserted named document
+func (f *MongoLoader) toMongoValue(ctx *loadContext, value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		if strings.HasPrefix(value, "$eval(") {
+			return nil, fmt.Errorf("$eval() is not supported by MongoDB fixtures: %s", value)
+		}
+		if len(value) > 0 && value[0] == '$' {
+			return f.files.resolveFieldReference(ctx.refsInserted, value)
+		}
+		return value, nil
+	case yaml.MapSlice:
+		doc := make(bson.D, len(value))
+		for i, item := range value {
+			v, err := f.toMongoValue(ctx, item.Value)
+			if err != nil {
+				return nil, err
+			}
+			doc[i] = bson.E{Key: fmt.Sprint(item.Key), Value: v}
+		}
+		return doc, nil
+	case []interface{}:
+		array := make(bson.A, len(value))
+		for i, item := range value {
+			v, err := f.toMongoValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = v
+		}
+		return array, nil
+	}
+	return value, nil
+}
+
+func documentValues(doc bson.D) map[string]interface{} {
+	values := make(map[string]interface{}, len(doc))
+	for _, e := range doc {
+		values[e.Key] = e.Value
+	}
+	return values
+}
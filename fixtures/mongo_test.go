@@ -0,0 +1,118 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoBuildDocuments(t *testing.T) {
+	yml := `
+templates:
+  base_user:
+    role: customer
+    settings:
+      lang: en
+      tags: [a, b]
+collections:
+  users:
+    - $extend: base_user
+      $name: alice
+      name: Alice
+    - name: Bob
+      manager: $alice._id
+`
+	ctx := loadContext{
+		refsDefinition: make(rowsDict),
+		refsInserted:   make(rowsDict),
+	}
+	l := NewMongoLoader(&MongoConfig{})
+	require.NoError(t, l.files.loadYml([]byte(yml), &ctx))
+
+	docs, err := l.buildDocuments(&ctx, ctx.tables[0].Rows)
+
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	alice := docs[0].(bson.D)
+	require.Equal(t, "_id", alice[0].Key, "the referenced document must get _id")
+	id, ok := alice[0].Value.(primitive.ObjectID)
+	require.True(t, ok)
+	assert.Equal(t, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "name", Value: "Alice"},
+		{Key: "role", Value: "customer"},
+		{Key: "settings", Value: bson.D{
+			{Key: "lang", Value: "en"},
+			{Key: "tags", Value: bson.A{"a", "b"}},
+		}},
+	}, alice)
+	assert.Equal(t, bson.D{
+		{Key: "manager", Value: id},
+		{Key: "name", Value: "Bob"},
+	}, docs[1])
+}
+
+func TestMongoBuildDocumentsShouldResolveReferencesToEarlierCollections(t *testing.T) {
+	yml := `
+collections:
+  users:
+    - $name: alice
+      _id: 1
+      name: Alice
+  orders:
+    - $name: first
+      user: $alice._id
+    - user: $alice.name
+      parent: $first._id
+`
+	ctx := loadContext{
+		refsDefinition: make(rowsDict),
+		refsInserted:   make(rowsDict),
+	}
+	l := NewMongoLoader(&MongoConfig{})
+	require.NoError(t, l.files.loadYml([]byte(yml), &ctx))
+
+	// the collections are built in the order they are inserted by LoadWithInline
+	users, err := l.buildDocuments(&ctx, ctx.tables[0].Rows)
+	require.NoError(t, err)
+	orders, err := l.buildDocuments(&ctx, ctx.tables[1].Rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: "Alice"}}}, users)
+	first := orders[0].(bson.D)
+	assert.Equal(t, bson.E{Key: "user", Value: 1}, first[1])
+	assert.Equal(t, bson.D{
+		{Key: "parent", Value: first[0].Value},
+		{Key: "user", Value: "Alice"},
+	}, orders[1])
+}
+
+func TestMongoBuildDocumentsShouldRejectReferenceToLaterDocument(t *testing.T) {
+	ctx := loadContext{
+		refsDefinition: make(rowsDict),
+		refsInserted:   make(rowsDict),
+	}
+	l := NewMongoLoader(&MongoConfig{})
+
+	_, err := l.buildDocuments(&ctx, table{
+		{"manager": "$bob._id"},
+		{"$name": "bob"},
+	})
+
+	assert.Error(t, err)
+}
+
+func TestMongoBuildDocumentsShouldRejectEval(t *testing.T) {
+	ctx := loadContext{
+		refsDefinition: make(rowsDict),
+		refsInserted:   make(rowsDict),
+	}
+	l := NewMongoLoader(&MongoConfig{})
+
+	_, err := l.buildDocuments(&ctx, table{{"createdAt": "$eval(NOW())"}})
+
+	assert.Error(t, err)
+}
@@ -14,9 +14,11 @@ require (
 	github.com/kylelemons/godebug v1.1.0
 	github.com/lib/pq v1.3.0
 	github.com/mattn/go-colorable v0.1.4 // indirect
-	github.com/mattn/go-isatty v0.0.10 // indirect
+	github.com/mattn/go-isatty v0.0.10
 	github.com/stretchr/testify v1.5.1
 	github.com/tidwall/gjson v1.6.0
+	go.mongodb.org/mongo-driver v1.3.0
+	golang.org/x/sys v0.0.0-20191008105621-543471e840be
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0
 	gopkg.in/yaml.v2 v2.2.8
 )
@@ -0,0 +1,34 @@
+package files
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ReadFile reads the file the tests refer to, the files with .gz extension are decompressed,
+// e.g. a large fixture or response body stored as users.yml.gz
+func ReadFile(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(filename, ".gz") {
+		return data, nil
+	}
+	if data, err = gunzip(data); err != nil {
+		return nil, fmt.Errorf("unable to decompress %s: %s", filename, err.Error())
+	}
+	return data, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
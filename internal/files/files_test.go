@@ -0,0 +1,24 @@
+package files
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	data, err := ReadFile(filepath.Join("testdata", "plain.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain\n", string(data))
+
+	data, err = ReadFile(filepath.Join("testdata", "compressed.txt.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, "compressed\n", string(data))
+}
+
+func TestReadFileShouldFailOnBrokenArchive(t *testing.T) {
+	_, err := ReadFile(filepath.Join("testdata", "broken.txt.gz"))
+	assert.EqualError(t, err, "unable to decompress testdata/broken.txt.gz: unexpected EOF")
+}
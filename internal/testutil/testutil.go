@@ -0,0 +1,27 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+// NewTest returns the test of the YAML definition for the checker tests,
+// e.g. the expected response of a single status
+func NewTest(t *testing.T, definition string) *yaml_file.Test {
+	test := &yaml_file.Test{}
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &test.TestDefinition))
+	return test
+}
+
+// ErrorMessages returns the texts of the errors to compare them with the expected ones
+func ErrorMessages(errs []error) []string {
+	var messages []string
+	for _, e := range errs {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
 	"errors"
 	"flag"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -11,12 +13,31 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/checker/response_body_forbidden"
+	"github.com/lamoda/gonkey/checker/response_body_hash"
+	"github.com/lamoda/gonkey/checker/response_body_matches"
+	"github.com/lamoda/gonkey/checker/response_cookies"
 	"github.com/lamoda/gonkey/checker/response_db"
+	"github.com/lamoda/gonkey/checker/response_fields"
+	"github.com/lamoda/gonkey/checker/response_json_schema"
+	"github.com/lamoda/gonkey/checker/response_keys"
+	"github.com/lamoda/gonkey/checker/response_merge_patch"
+	"github.com/lamoda/gonkey/checker/response_ndjson"
+	"github.com/lamoda/gonkey/checker/response_problem"
 	"github.com/lamoda/gonkey/checker/response_schema"
+	"github.com/lamoda/gonkey/checker/response_status"
+	"github.com/lamoda/gonkey/checker/response_structure"
+	"github.com/lamoda/gonkey/checker/response_time"
+	"github.com/lamoda/gonkey/checker/response_validation"
 	"github.com/lamoda/gonkey/fixtures"
 	"github.com/lamoda/gonkey/output/allure_report"
 	"github.com/lamoda/gonkey/output/console_colored"
+	"github.com/lamoda/gonkey/output/failures_dir"
+	"github.com/lamoda/gonkey/output/junit_xml"
+	"github.com/lamoda/gonkey/output/tap"
 	"github.com/lamoda/gonkey/runner"
+	"github.com/lamoda/gonkey/testloader"
+	"github.com/lamoda/gonkey/testloader/recording"
 	"github.com/lamoda/gonkey/testloader/yaml_file"
 	"github.com/lamoda/gonkey/variables"
 )
@@ -26,22 +47,76 @@ func main() {
 		Host             string
 		SpecPath         string
 		TestsLocation    string
+		Replay           string
+		Bootstrap        string
 		DbDsn            string
+		DbPool           runner.DBPoolConfig
 		FixturesLocation string
+		SuiteFixtures    string
+		CheckCleanup     bool
 		EnvFile          string
+		FailedTestsFile  string
+		RerunFailed      bool
+		StepFrom         string
+		StepOnly         string
+		FailOnSkip       bool
+		MaxFailures      string
+		Shuffle          bool
+		ShuffleSeed      int64
+		UpdateSnapshots  bool
+		WarnDuplicates   bool
+		UserAgent        string
+		NoIdentification bool
+		HTTP2            bool
+		ParityHost       string
+		ParityIgnore     string
 		Allure           bool
+		JUnitReport      string
+		FailuresDir      string
+		TAPReport        string
 		Verbose          bool
+		PrettyJSON       bool
+		Progress         string
+		DotsPerLine      int
 		Debug            bool
 	}
 
 	flag.StringVar(&config.Host, "host", "", "Target system hostname")
 	flag.StringVar(&config.SpecPath, "spec", "", "Path or URL to swagger specification")
 	flag.StringVar(&config.TestsLocation, "tests", "", "Path to tests file or directory")
+	flag.StringVar(&config.Replay, "replay", "", "Path to recorded session file or directory replayed instead of the tests")
+	flag.StringVar(&config.Bootstrap, "bootstrap", "", "Path to tests file or directory executed once before the tests")
 	flag.StringVar(&config.DbDsn, "db_dsn", "", "DSN for the fixtures database (WARNING! Db tables will be truncated)")
+	flag.IntVar(&config.DbPool.MaxOpenConns, "db-max-open-conns", 0, "Maximum number of open connections to the database")
+	flag.IntVar(&config.DbPool.MaxIdleConns, "db-max-idle-conns", 0, "Maximum number of idle connections to the database")
+	flag.DurationVar(&config.DbPool.ConnMaxLifetime, "db-conn-max-lifetime", 0, "Maximum amount of time a database connection may be reused")
 	flag.StringVar(&config.FixturesLocation, "fixtures", "", "Path to fixtures directory")
+	flag.StringVar(&config.SuiteFixtures, "suite-fixtures", "", "Comma separated fixtures loaded once before all the tests")
+	flag.BoolVar(&config.CheckCleanup, "check-fixtures-cleanup", false, "Fail if fixtures cleanup deletes other number of rows than the fixtures have")
 	flag.StringVar(&config.EnvFile, "env-file", "", "Path to env-file")
+	flag.StringVar(&config.FailedTestsFile, "failed-tests", "", "Path to file to save failed tests list to")
+	flag.BoolVar(&config.RerunFailed, "rerun-failed", false, "Run only tests listed in the failed tests file")
+	flag.StringVar(&config.StepFrom, "step-from", "", "Skip the tests preceding the one with this name")
+	flag.StringVar(&config.StepOnly, "step-only", "", "Run only the test with this name")
+	flag.BoolVar(&config.FailOnSkip, "fail-on-skip", false, "Fail if any test was skipped")
+	flag.StringVar(&config.MaxFailures, "max-failures", "", "Number or percentage (e.g. 5%) of failed tests which still pass the run")
+	flag.BoolVar(&config.Shuffle, "shuffle", false, "Run the tests in random order")
+	flag.Int64Var(&config.ShuffleSeed, "shuffle-seed", 0, "Seed of the random order of the tests, random if zero")
+	flag.StringVar(&config.UserAgent, "user-agent", "", "User-Agent of the requests, gonkey/<version> by default")
+	flag.BoolVar(&config.NoIdentification, "disable-request-identification", false, "Send no User-Agent, X-Test-Name and X-Test-Run-Id headers by default")
+	flag.BoolVar(&config.HTTP2, "http2", false, "Negotiate HTTP/2 with HTTPS hosts")
+	flag.StringVar(&config.ParityHost, "parity-host", "", "Hostname which must respond to the same requests as the target system")
+	flag.StringVar(&config.ParityIgnore, "parity-ignore", "", "Comma separated JSON paths not compared with the parity host responses")
+	flag.BoolVar(&config.WarnDuplicates, "warn-on-duplicate-names", false, "Warn about the tests sharing a name instead of failing")
+	flag.BoolVar(&config.UpdateSnapshots, "update-snapshots", false, "Rewrite the structure snapshots by the actual responses")
 	flag.BoolVar(&config.Allure, "allure", true, "Make Allure report")
+	flag.StringVar(&config.JUnitReport, "junit-report", "", "Path to JUnit XML report to write")
+	flag.StringVar(&config.FailuresDir, "failures-dir", "", "Path to directory to write the failed tests to")
+	flag.StringVar(&config.TAPReport, "tap-report", "", "Path to TAP report to write")
 	flag.BoolVar(&config.Verbose, "v", false, "Verbose output")
+	flag.BoolVar(&config.PrettyJSON, "pretty", false, "Print JSON bodies indented")
+	flag.StringVar(&config.Progress, "progress", "dots", "Progress style of the passed tests: dots, counter or spinner")
+	flag.IntVar(&config.DotsPerLine, "dots-per-line", 0, "Number of the dots of a line, 80 or the terminal width by default")
 	flag.BoolVar(&config.Debug, "debug", false, "Debug output")
 
 	flag.Parse()
@@ -54,11 +129,19 @@ func main() {
 		}
 		config.Host = strings.TrimRight(config.Host, "/")
 	}
+	if config.ParityHost != "" && !strings.HasPrefix(config.ParityHost, "http://") && !strings.HasPrefix(config.ParityHost, "https://") {
+		config.ParityHost = "http://" + config.ParityHost
+	}
+	config.ParityHost = strings.TrimRight(config.ParityHost, "/")
 
-	if config.TestsLocation == "" {
+	if config.TestsLocation == "" && config.Replay == "" {
 		log.Fatal(errors.New("no tests location provided"))
 	}
 
+	if config.RerunFailed && config.FailedTestsFile == "" {
+		log.Fatal(errors.New("you should specify failed-tests to rerun failed tests"))
+	}
+
 	var db *sql.DB
 	if config.DbDsn != "" {
 		var err error
@@ -66,14 +149,26 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
+		config.DbPool.Apply(db)
+	}
+
+	// the debug output of the fixtures is printed and reported with the test
+	var fixturesLog *bytes.Buffer
+	var fixturesDebugOutput io.Writer
+	if config.Debug {
+		fixturesLog = &bytes.Buffer{}
+		fixturesDebugOutput = io.MultiWriter(os.Stdout, fixturesLog)
 	}
 
-	var fixturesLoader *fixtures.Loader
+	var fixturesLoader fixtures.LoaderInterface
 	if db != nil && config.FixturesLocation != "" {
 		fixturesLoader = fixtures.NewLoader(&fixtures.Config{
 			DB:       db,
 			Location: config.FixturesLocation,
 			Debug:    config.Debug,
+
+			DebugOutput:  fixturesDebugOutput,
+			CheckCleanup: config.CheckCleanup,
 		})
 	} else if config.FixturesLocation != "" {
 		log.Fatal(errors.New("you should specify db_dsn to load fixtures"))
@@ -84,16 +179,75 @@ func main() {
 		log.Println(errors.New("error loading .env file"), err)
 	}
 
+	var suiteFixtures []string
+	if config.SuiteFixtures != "" {
+		if fixturesLoader == nil {
+			log.Fatal(errors.New("you should specify fixtures to load suite fixtures"))
+		}
+		suiteFixtures = strings.Split(config.SuiteFixtures, ",")
+	}
+
+	var parityIgnore []string
+	if config.ParityIgnore != "" {
+		parityIgnore = strings.Split(config.ParityIgnore, ",")
+	}
+
+	var bootstrap testloader.LoaderInterface
+	if config.Bootstrap != "" {
+		bootstrap = yaml_file.NewLoader(config.Bootstrap)
+	}
+
+	var rerunFailedFrom string
+	if config.RerunFailed {
+		rerunFailedFrom = config.FailedTestsFile
+	}
+
+	var testsLoader testloader.LoaderInterface
+	if config.Replay != "" {
+		testsLoader = recording.NewLoader(config.Replay)
+	} else {
+		yamlLoader := yaml_file.NewLoader(config.TestsLocation)
+		yamlLoader.SetWarnOnDuplicateNames(config.WarnDuplicates)
+		testsLoader = yamlLoader
+	}
+
 	r := runner.New(
 		&runner.Config{
-			Host:           config.Host,
-			FixturesLoader: fixturesLoader,
-			Variables:      variables.New(),
+			Host:            config.Host,
+			FixturesLoader:  fixturesLoader,
+			FixturesLog:     fixturesLog,
+			Variables:       variables.New(),
+			FailedTestsFile: config.FailedTestsFile,
+			RerunFailedFrom: rerunFailedFrom,
+			StepFrom:        config.StepFrom,
+			StepOnly:        config.StepOnly,
+			FailOnSkip:      config.FailOnSkip,
+			MaxFailures:     config.MaxFailures,
+			SuiteFixtures:   suiteFixtures,
+			Bootstrap:       bootstrap,
+			Shuffle:         config.Shuffle,
+			ShuffleSeed:     config.ShuffleSeed,
+
+			UserAgent:                    config.UserAgent,
+			DisableRequestIdentification: config.NoIdentification,
+			EnableHTTP2:                  config.HTTP2,
+
+			ParityHost:   config.ParityHost,
+			ParityIgnore: parityIgnore,
 		},
-		yaml_file.NewLoader(config.TestsLocation),
+		testsLoader,
 	)
 
-	consoleOutput := console_colored.NewOutput(config.Verbose)
+	progressStyle, err := console_colored.ParseProgressStyle(config.Progress)
+	if err != nil {
+		log.Fatal(err)
+	}
+	consoleOutput := console_colored.NewOutput(
+		config.Verbose,
+		console_colored.WithProgressStyle(progressStyle),
+		console_colored.WithDotsPerLine(config.DotsPerLine),
+	)
+	consoleOutput.SetPrettyJSON(config.PrettyJSON)
 	r.AddOutput(consoleOutput)
 
 	var allureOutput *allure_report.AllureReportOutput
@@ -102,7 +256,46 @@ func main() {
 		r.AddOutput(allureOutput)
 	}
 
+	var junitOutput *junit_xml.JUnitXMLOutput
+	if config.JUnitReport != "" {
+		junitOutput = junit_xml.NewOutput(config.JUnitReport)
+		r.AddOutput(junitOutput)
+	}
+
+	if config.FailuresDir != "" {
+		failuresOutput, err := failures_dir.NewOutput(config.FailuresDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.AddOutput(failuresOutput)
+	}
+
+	var tapOutput *tap.TAPOutput
+	if config.TAPReport != "" {
+		tapFile, err := os.Create(config.TAPReport)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer tapFile.Close()
+		tapOutput = tap.NewOutput(tapFile, config.Verbose)
+		r.AddOutput(tapOutput)
+	}
+
 	r.AddCheckers(response_body.NewChecker())
+	r.AddCheckers(response_body_hash.NewChecker())
+	r.AddCheckers(response_body_matches.NewChecker())
+	r.AddCheckers(response_body_forbidden.NewChecker())
+	r.AddCheckers(response_fields.NewChecker())
+	r.AddCheckers(response_keys.NewChecker())
+	r.AddCheckers(response_ndjson.NewChecker())
+	r.AddCheckers(response_json_schema.NewChecker())
+	r.AddCheckers(response_merge_patch.NewChecker())
+	r.AddCheckers(response_validation.NewChecker())
+	r.AddCheckers(response_structure.NewChecker(config.UpdateSnapshots))
+	r.AddCheckers(response_cookies.NewChecker())
+	r.AddCheckers(response_status.NewChecker())
+	r.AddCheckers(response_problem.NewChecker())
+	r.AddCheckers(response_time.NewChecker())
 	if config.SpecPath != "" {
 		r.AddCheckers(response_schema.NewChecker(config.SpecPath))
 	}
@@ -121,6 +314,15 @@ func main() {
 	if allureOutput != nil {
 		allureOutput.Finalize()
 	}
+	if junitOutput != nil {
+		junitOutput.ShowSummary(summary)
+		if err := junitOutput.Finalize(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if tapOutput != nil {
+		tapOutput.ShowSummary(summary)
+	}
 
 	if !summary.Success {
 		os.Exit(1)
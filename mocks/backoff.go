@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// jitter of the retry delays, none by default
+const (
+	JitterNone  = "none"
+	JitterFull  = "full"
+	JitterEqual = "equal"
+)
+
+// backoff doubles the delay before every retry, the n-th delay is retryDelay * 2^(n-1),
+// full jitter picks a random delay in [0, delay), equal jitter in [delay/2, delay)
+type backoff struct {
+	delay  time.Duration
+	jitter string
+
+	sync.Mutex
+	rnd *rand.Rand
+}
+
+// newBackoff seeds the random delays with the seed to reproduce them, a random seed is used if zero
+func newBackoff(delay time.Duration, jitter string, seed int64) (*backoff, error) {
+	switch jitter {
+	case "", JitterNone, JitterFull, JitterEqual:
+	default:
+		return nil, fmt.Errorf("unknown jitter %s, expected %s, %s or %s", jitter, JitterNone, JitterFull, JitterEqual)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &backoff{
+		delay:  delay,
+		jitter: jitter,
+		rnd:    rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// retryDelay returns the delay before the retry, the first retry is 1
+func (b *backoff) retryDelay(retry int) time.Duration {
+	delay := b.delay << uint(retry-1)
+	if delay <= 0 {
+		return 0
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	switch b.jitter {
+	case JitterFull:
+		return time.Duration(b.rnd.Int63n(int64(delay)))
+	case JitterEqual:
+		half := delay / 2
+		return half + time.Duration(b.rnd.Int63n(int64(delay-half)))
+	default:
+		return delay
+	}
+}
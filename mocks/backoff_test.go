@@ -0,0 +1,123 @@
+package mocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestBackoffShouldDoubleDelay(t *testing.T) {
+	b := testBackoff(t, 100*time.Millisecond, "")
+
+	assert.Equal(t, 100*time.Millisecond, b.retryDelay(1))
+	assert.Equal(t, 200*time.Millisecond, b.retryDelay(2))
+	assert.Equal(t, 400*time.Millisecond, b.retryDelay(3))
+}
+
+func TestBackoffShouldApplyJitter(t *testing.T) {
+	full := testBackoff(t, 100*time.Millisecond, JitterFull)
+	equal := testBackoff(t, 100*time.Millisecond, JitterEqual)
+	for retry := 1; retry <= 5; retry++ {
+		delay := 100 * time.Millisecond << uint(retry-1)
+
+		d := full.retryDelay(retry)
+		assert.True(t, d >= 0 && d < delay, d)
+
+		d = equal.retryDelay(retry)
+		assert.True(t, d >= delay/2 && d < delay, d)
+	}
+}
+
+func TestBackoffShouldReproduceDelaysWithSeed(t *testing.T) {
+	first := testBackoff(t, time.Second, JitterFull)
+	second := testBackoff(t, time.Second, JitterFull)
+	for retry := 1; retry <= 3; retry++ {
+		assert.Equal(t, first.retryDelay(retry), second.retryDelay(retry))
+	}
+}
+
+func TestBackoffShouldRejectUnknownJitter(t *testing.T) {
+	_, err := newBackoff(time.Second, "random", 0)
+	require.EqualError(t, err, "unknown jitter random, expected none, full or equal")
+}
+
+func TestLoadShouldSetProxyJitter(t *testing.T) {
+	l := NewLoader(NewNop("service"))
+	l.SetRetryJitter(JitterFull, 7)
+
+	load := func(definition string) (*backoff, error) {
+		var def map[interface{}]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(definition), &def))
+		strategy, err := l.loadProxyStrategy("$", def)
+		if err != nil {
+			return nil, err
+		}
+		return strategy.(*proxyReply).backoff, nil
+	}
+
+	b, err := load("url: http://upstream")
+	require.NoError(t, err)
+	assert.Equal(t, JitterFull, b.jitter)
+
+	b, err = load("url: http://upstream\nretryJitter: equal\nretryJitterSeed: 3")
+	require.NoError(t, err)
+	assert.Equal(t, JitterEqual, b.jitter)
+
+	_, err = load("url: http://upstream\nretryJitter: random")
+	assert.EqualError(t, err, "`retryJitter`: unknown jitter random, expected none, full or equal")
+}
+
+func TestLoadShouldSeedEveryProxyMock(t *testing.T) {
+	l := NewLoader(NewNop("orders", "users"))
+	l.SetRetryJitter(JitterFull, 7)
+
+	load := func(service string) *backoff {
+		var def map[interface{}]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte("url: http://upstream\nretryDelay: 1s"), &def))
+		l.service = service
+		strategy, err := l.loadProxyStrategy("$.proxy", def)
+		require.NoError(t, err)
+		return strategy.(*proxyReply).backoff
+	}
+	delays := func(b *backoff) []time.Duration {
+		var res []time.Duration
+		for retry := 1; retry <= 3; retry++ {
+			res = append(res, b.retryDelay(retry))
+		}
+		return res
+	}
+
+	orders := delays(load("orders"))
+	assert.NotEqual(t, orders, delays(load("users")), "mocks must not share the delays")
+	assert.Equal(t, orders, delays(load("orders")), "delays must be reproduced with the seed")
+}
+
+func TestLoadShouldParseRetryDelay(t *testing.T) {
+	l := NewLoader(NewNop("service"))
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "250ms", want: 250 * time.Millisecond},
+		{value: "2", want: 2 * time.Second},
+		{value: "0.5", want: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var def map[interface{}]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte("url: http://upstream\nretryDelay: "+tt.value), &def))
+			strategy, err := l.loadProxyStrategy("$", def)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, strategy.(*proxyReply).backoff.delay)
+		})
+	}
+
+	var def map[interface{}]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte("url: http://upstream\nretryDelay: -1"), &def))
+	_, err := l.loadProxyStrategy("$", def)
+	assert.EqualError(t, err, "`retryDelay`: duration -1 must not be negative")
+}
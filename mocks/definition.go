@@ -3,21 +3,55 @@ package mocks
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 )
 
 const callsNoConstraint = -1
 
+// callsRange limits the number of calls, callsNoConstraint means no limit
+type callsRange struct {
+	min int
+	max int
+}
+
+var noCallsRange = callsRange{min: callsNoConstraint, max: callsNoConstraint}
+
+func exactCalls(n int) callsRange {
+	return callsRange{min: n, max: n}
+}
+
+// check returns error if the actual number of calls is out of the range
+func (c callsRange) check(calls int) error {
+	tooFew := c.min != callsNoConstraint && calls < c.min
+	tooMany := c.max != callsNoConstraint && calls > c.max
+	if !tooFew && !tooMany {
+		return nil
+	}
+	switch {
+	case c.min == c.max:
+		return fmt.Errorf("expected %d, actual %d", c.min, calls)
+	case c.max == callsNoConstraint:
+		return fmt.Errorf("expected at least %d, actual %d", c.min, calls)
+	case c.min == callsNoConstraint:
+		return fmt.Errorf("expected at most %d, actual %d", c.max, calls)
+	default:
+		return fmt.Errorf("expected from %d to %d, actual %d", c.min, c.max, calls)
+	}
+}
+
 type definition struct {
 	path               string
 	requestConstraints []verifier
 	replyStrategy      replyStrategy
 	sync.Mutex
 	calls           int
-	callsConstraint int
+	callsConstraint callsRange
+	// suite is set for the definitions of the suite mocks, which the test may leave unused
+	suite bool
 }
 
-func newDefinition(path string, constraints []verifier, strategy replyStrategy, callsConstraint int) *definition {
+func newDefinition(path string, constraints []verifier, strategy replyStrategy, callsConstraint callsRange) *definition {
 	return &definition{
 		path:               path,
 		requestConstraints: constraints,
@@ -65,10 +99,51 @@ func (d *definition) EndRunningContext() []error {
 	if s, ok := d.replyStrategy.(contextAwareStrategy); ok {
 		errs = s.EndRunningContext()
 	}
-	if d.callsConstraint != callsNoConstraint && d.calls != d.callsConstraint {
-		err := fmt.Errorf("at path %s: number of calls does not match: expected %d, actual %d",
-			d.path, d.callsConstraint, d.calls)
-		errs = append(errs, err)
+	if err := d.callsConstraint.check(d.calls); err != nil {
+		errs = append(errs, fmt.Errorf("at path %s: number of calls does not match: %s", d.path, err.Error()))
 	}
 	return errs
 }
+
+// unusedPaths returns paths of the endpoints which were never called,
+// for the vary strategies each variant is considered as a separate endpoint
+func (d *definition) unusedPaths() []string {
+	if d.suite {
+		return nil
+	}
+	variants := d.variants()
+	if variants == nil {
+		d.Lock()
+		defer d.Unlock()
+		if d.calls == 0 {
+			return []string{d.path}
+		}
+		return nil
+	}
+
+	var paths []string
+	for _, def := range variants {
+		paths = append(paths, def.unusedPaths()...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// variants returns the definitions of the vary strategies by uri (method), nil for the other strategies
+func (d *definition) variants() map[string]*definition {
+	switch s := d.replyStrategy.(type) {
+	case *uriVaryReply:
+		return s.variants
+	case *methodVaryReply:
+		return s.variants
+	}
+	return nil
+}
+
+// markSuite marks the definition with its variants as the suite one
+func (d *definition) markSuite() {
+	d.suite = true
+	for _, def := range d.variants() {
+		def.markSuite()
+	}
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCallsRangeCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     callsRange
+		calls int
+		err   error
+	}{
+		{name: "no constraint", r: noCallsRange, calls: 5},
+		{name: "exact", r: exactCalls(2), calls: 2},
+		{name: "exact mismatch", r: exactCalls(1), calls: 2, err: errors.New("expected 1, actual 2")},
+		{name: "in range", r: callsRange{min: 1, max: 2}, calls: 2},
+		{name: "above range", r: callsRange{min: 1, max: 2}, calls: 3, err: errors.New("expected from 1 to 2, actual 3")},
+		{name: "below min", r: callsRange{min: 1, max: callsNoConstraint}, calls: 0, err: errors.New("expected at least 1, actual 0")},
+		{name: "above max", r: callsRange{min: callsNoConstraint, max: 2}, calls: 3, err: errors.New("expected at most 2, actual 3")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.r.check(tt.calls))
+		})
+	}
+}
+
+func TestLoadShouldAcceptCallsRange(t *testing.T) {
+	m := NewNop("service")
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+service:
+  strategy: nop
+  calls:
+    min: 1
+    max: 2
+`), &def))
+	require.NoError(t, NewLoader(m).Load(def))
+
+	for i := 0; i < 3; i++ {
+		m.Service("service").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	errs := m.Service("service").EndRunningContext()
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "mock service: at path $: number of calls does not match: expected from 1 to 2, actual 3")
+}
+
+func TestLoadShouldRejectInvalidCallsRange(t *testing.T) {
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+service:
+  strategy: nop
+  calls:
+    min: 3
+    max: 2
+`), &def))
+
+	err := NewLoader(NewNop("service")).Load(def)
+	assert.EqualError(t, err, "unable to load definition for service: at path $: `calls.min` must not be greater than `calls.max`")
+}
@@ -30,3 +30,17 @@ func (e *RequestConstraintError) Error() string {
 	}
 	return fmt.Sprintf("request constraint %s failed: %s, request was:\n %s", kind, e.error.Error(), req)
 }
+
+// UnexpectedRequestError is the request which does not match any endpoint of the mock
+type UnexpectedRequestError struct {
+	Method string
+	Path   string
+}
+
+func (e *UnexpectedRequestError) Error() string {
+	return fmt.Sprintf("unexpected request %s %s", e.Method, e.Path)
+}
+
+func newUnexpectedRequestError(r *http.Request) *UnexpectedRequestError {
+	return &UnexpectedRequestError{Method: r.Method, Path: r.URL.Path}
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// failingReply responds with the error status to the first calls of the test
+// and with the wrapped strategy afterwards, e.g. to test the retries of the service
+type failingReply struct {
+	replyStrategy
+	contextAwareStrategy
+
+	strategy   replyStrategy
+	times      int
+	statusCode int
+
+	sync.Mutex
+	calls int
+}
+
+func newFailingReply(strategy replyStrategy, times, statusCode int) replyStrategy {
+	return &failingReply{
+		strategy:   strategy,
+		times:      times,
+		statusCode: statusCode,
+	}
+}
+
+func (s *failingReply) HandleRequest(w http.ResponseWriter, r *http.Request) []error {
+	s.Lock()
+	s.calls++
+	fail := s.calls <= s.times
+	s.Unlock()
+	if fail {
+		w.WriteHeader(s.statusCode)
+		return nil
+	}
+	return s.strategy.HandleRequest(w, r)
+}
+
+func (s *failingReply) ResetRunningContext() {
+	s.Lock()
+	s.calls = 0
+	s.Unlock()
+}
+
+func (s *failingReply) EndRunningContext() []error {
+	return nil
+}
+
+// loadFailures wraps the strategy with failingReply if the definition has `failTimes`
+func loadFailures(strategy replyStrategy, def map[interface{}]interface{}) (replyStrategy, error) {
+	f, ok := def["failTimes"]
+	if !ok {
+		return strategy, nil
+	}
+	times, ok := f.(int)
+	if !ok || times < 0 {
+		return nil, errors.New("`failTimes` must be non-negative integer")
+	}
+	statusCode := http.StatusInternalServerError
+	if c, ok := def["failStatusCode"]; ok {
+		if statusCode, ok = c.(int); !ok {
+			return nil, errors.New("`failStatusCode` must be integer")
+		}
+	}
+	return newFailingReply(strategy, times, statusCode), nil
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailTimesShouldFailFirstCalls(t *testing.T) {
+	m, err := loadMock(t, `
+service:
+  strategy: constant
+  body: ok
+  failTimes: 2
+  failStatusCode: 503
+  calls: 3
+`)
+	require.NoError(t, err)
+
+	var codes []int
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		codes = append(codes, w.Code)
+		bodies = append(bodies, w.Body.String())
+	}
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}, codes)
+	assert.Equal(t, []string{"", "", "ok"}, bodies)
+	assert.Empty(t, m.EndRunningContext())
+
+	m.ResetRunningContext()
+	w := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "calls are counted per test")
+}
+
+func TestFailTimesShouldDefaultToInternalServerError(t *testing.T) {
+	m, err := loadMock(t, `
+service:
+  strategy: template
+  body: '{{ .Path }}'
+  failTimes: 1
+`)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/books", w.Body.String())
+
+	_, err = loadMock(t, `
+service:
+  strategy: constant
+  body: ok
+  failTimes: many
+`)
+	assert.EqualError(t, err, "unable to load definition for service: `failTimes` must be non-negative integer")
+}
@@ -3,11 +3,28 @@ package mocks
 import (
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/lamoda/gonkey/models"
 )
 
 type Loader struct {
-	mocks *Mocks
+	mocks     *Mocks
+	templates map[string]*template.Template
+
+	suiteMocks map[string]interface{}
+	strategies map[string]string
+	// requestHeaders are the headers of the test request the headerPropagated constraints compare with
+	requestHeaders map[string]string
+
+	retryJitter     string
+	retryJitterSeed int64
+	// service is the name of the mock whose definition is loaded
+	service string
 }
 
 func NewLoader(mocks *Mocks) *Loader {
@@ -16,18 +33,37 @@ func NewLoader(mocks *Mocks) *Loader {
 	}
 }
 
+// SetRetryJitter sets the default jitter of the proxy retries and the seed reproducing the delays,
+// see JitterFull and JitterEqual. Every proxy mock derives its own seed from the default one and its path.
+func (l *Loader) SetRetryJitter(jitter string, seed int64) {
+	l.retryJitter = jitter
+	l.retryJitterSeed = seed
+}
+
 func (l *Loader) Load(mocksDefinition map[string]interface{}) error {
-	for serviceName, definition := range mocksDefinition {
+	return l.LoadForRequest(mocksDefinition, nil)
+}
+
+// LoadForRequest loads the definitions like Load, the headerPropagated constraints check
+// that the requests to the mocks have the headers propagated from the given headers of the test request
+func (l *Loader) LoadForRequest(mocksDefinition map[string]interface{}, requestHeaders map[string]string) error {
+	l.requestHeaders = requestHeaders
+	definitions, layers, suiteItems := l.layerDefinitions(mocksDefinition)
+	l.strategies = make(map[string]string, len(definitions))
+	for serviceName, definition := range definitions {
 		service := l.mocks.Service(serviceName)
 		if service == nil {
 			return fmt.Errorf("service mock not defined: %s", serviceName)
 		}
+		l.service = serviceName
 		def, err := l.loadDefinition("$", definition)
 		if err != nil {
 			return fmt.Errorf("unable to load definition for %s: %v", serviceName, err)
 		}
+		markSuiteDefinitions(def, layers[serviceName], suiteItems[serviceName])
 		// load the definition into the mock
 		service.SetDefinition(def)
+		l.strategies[serviceName] = fmt.Sprintf("%s (%s)", strategyName(definition), layers[serviceName])
 	}
 	return nil
 }
@@ -38,6 +74,15 @@ func (l *Loader) loadDefinition(path string, rawDef interface{}) (*definition, e
 		return nil, fmt.Errorf("at path %s: definition must be key-values", path)
 	}
 
+	// the template is expanded into the regular definition
+	if _, ok := def["template"]; ok {
+		expanded, err := l.expandTemplate(path, def)
+		if err != nil {
+			return nil, err
+		}
+		return l.loadDefinition(path+".template("+def["template"].(string)+")", expanded)
+	}
+
 	// load request constraints
 	var requestConstraints []verifier
 	if constraints, ok := def["requestConstraints"]; ok {
@@ -75,10 +120,10 @@ func (l *Loader) loadDefinition(path string, rawDef interface{}) (*definition, e
 		return nil, err
 	}
 
-	callsConstraint := callsNoConstraint
-	if _, ok = def["calls"]; ok {
-		if value, ok := def["calls"].(int); ok {
-			callsConstraint = value
+	callsConstraint := noCallsRange
+	if c, ok := def["calls"]; ok {
+		if callsConstraint, err = loadCallsRange(c); err != nil {
+			return nil, fmt.Errorf("at path %s: %v", path, err)
 		}
 	}
 
@@ -103,8 +148,22 @@ func (l *Loader) loadStrategy(path, strategyName string, definition map[interfac
 		*ak = append(*ak, "filename", "statusCode", "headers")
 		return l.loadFileStrategy(path, definition)
 	case "constant":
-		*ak = append(*ak, "body", "statusCode", "headers")
-		return l.loadConstantStrategy(path, definition)
+		*ak = append(*ak, "body", "statusCode", "headers", "failTimes", "failStatusCode")
+		strategy, err := l.loadConstantStrategy(path, definition)
+		if err != nil {
+			return nil, err
+		}
+		return loadFailures(strategy, definition)
+	case "template":
+		*ak = append(*ak, "body", "statusCode", "headers", "pathPattern", "failTimes", "failStatusCode")
+		strategy, err := l.loadTemplateStrategy(path, definition)
+		if err != nil {
+			return nil, err
+		}
+		return loadFailures(strategy, definition)
+	case "proxy":
+		*ak = append(*ak, "url", "pathRewrite", "timeout", "retries", "retryDelay", "retryJitter", "retryJitterSeed")
+		return l.loadProxyStrategy(path, definition)
 	default:
 		return nil, fmt.Errorf("unknown strategy: %s", strategyName)
 	}
@@ -152,6 +211,35 @@ func (l *Loader) loadMethodVaryStrategy(path string, def map[interface{}]interfa
 	return newMethodVaryReply(methods), nil
 }
 
+// loadCallsRange accepts either the exact number of calls or a map with `min` and `max`
+func loadCallsRange(c interface{}) (callsRange, error) {
+	switch value := c.(type) {
+	case int:
+		return exactCalls(value), nil
+	case map[interface{}]interface{}:
+		if err := validateMapKeys(value, "min", "max"); err != nil {
+			return noCallsRange, err
+		}
+		r := noCallsRange
+		if v, ok := value["min"]; ok {
+			if r.min, ok = v.(int); !ok {
+				return noCallsRange, errors.New("`calls.min` must be integer")
+			}
+		}
+		if v, ok := value["max"]; ok {
+			if r.max, ok = v.(int); !ok {
+				return noCallsRange, errors.New("`calls.max` must be integer")
+			}
+		}
+		if r.min != callsNoConstraint && r.max != callsNoConstraint && r.min > r.max {
+			return noCallsRange, errors.New("`calls.min` must not be greater than `calls.max`")
+		}
+		return r, nil
+	default:
+		return noCallsRange, errors.New("`calls` must be integer or map with `min` and `max`")
+	}
+}
+
 func (l *Loader) loadFileStrategy(path string, def map[interface{}]interface{}) (replyStrategy, error) {
 	f, ok := def["filename"]
 	if !ok {
@@ -169,7 +257,7 @@ func (l *Loader) loadFileStrategy(path string, def map[interface{}]interface{})
 	if err != nil {
 		return nil, err
 	}
-	return newFileReplyWithCode(filename, statusCode, headers), nil
+	return newFileReplyWithCode(filename, statusCode, headers)
 }
 
 func (l *Loader) loadConstantStrategy(path string, def map[interface{}]interface{}) (replyStrategy, error) {
@@ -192,6 +280,129 @@ func (l *Loader) loadConstantStrategy(path string, def map[interface{}]interface
 	return newConstantReplyWithCode([]byte(body), statusCode, headers), nil
 }
 
+func (l *Loader) loadTemplateStrategy(path string, def map[interface{}]interface{}) (replyStrategy, error) {
+	c, ok := def["body"]
+	if !ok {
+		return nil, errors.New("`template` requires `body` key")
+	}
+	body, ok := c.(string)
+	if !ok {
+		return nil, errors.New("`body` must be string")
+	}
+	statusCode := http.StatusOK
+	if c, ok := def["statusCode"]; ok {
+		statusCode = c.(int)
+	}
+	var pathPattern string
+	if p, ok := def["pathPattern"]; ok {
+		if pathPattern, ok = p.(string); !ok {
+			return nil, errors.New("`pathPattern` must be string")
+		}
+	}
+	headers, err := l.loadHeaders(def)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := newTemplateReply(path, body, statusCode, headers, pathPattern)
+	if err != nil {
+		return nil, fmt.Errorf("`body`: %s", err.Error())
+	}
+	return reply, nil
+}
+
+func (l *Loader) loadProxyStrategy(path string, def map[interface{}]interface{}) (replyStrategy, error) {
+	u, ok := def["url"]
+	if !ok {
+		return nil, errors.New("`proxy` requires `url` key")
+	}
+	url, ok := u.(string)
+	if !ok {
+		return nil, errors.New("`url` must be string")
+	}
+	var rewrite *pathRewrite
+	if r, ok := def["pathRewrite"]; ok {
+		var err error
+		if rewrite, err = loadPathRewrite(r); err != nil {
+			return nil, err
+		}
+	}
+	timeout := defaultProxyTimeout
+	if t, ok := def["timeout"]; ok {
+		var err error
+		if timeout, err = models.ParseDuration(t); err != nil {
+			return nil, fmt.Errorf("`timeout`: %s", err.Error())
+		}
+	}
+	var retries int
+	if r, ok := def["retries"]; ok {
+		if retries, ok = r.(int); !ok {
+			return nil, errors.New("`retries` must be integer")
+		}
+	}
+	retryDelay := defaultProxyRetryDelay
+	if d, ok := def["retryDelay"]; ok {
+		var err error
+		if retryDelay, err = models.ParseDuration(d); err != nil {
+			return nil, fmt.Errorf("`retryDelay`: %s", err.Error())
+		}
+	}
+	jitter := l.retryJitter
+	if j, ok := def["retryJitter"]; ok {
+		if jitter, ok = j.(string); !ok {
+			return nil, errors.New("`retryJitter` must be string")
+		}
+	}
+	seed := mockSeed(l.retryJitterSeed, l.service, path)
+	if s, ok := def["retryJitterSeed"]; ok {
+		n, ok := s.(int)
+		if !ok {
+			return nil, errors.New("`retryJitterSeed` must be integer")
+		}
+		seed = int64(n)
+	}
+	backoff, err := newBackoff(retryDelay, jitter, seed)
+	if err != nil {
+		return nil, fmt.Errorf("`retryJitter`: %s", err.Error())
+	}
+	return newProxyReply(url, rewrite, timeout, retries, backoff), nil
+}
+
+// mockSeed derives the seed of the mock at the path so the mocks sharing the default seed
+// don't repeat the same delays, zero stays random
+func mockSeed(seed int64, service, path string) int64 {
+	if seed == 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(service + path))
+	return seed + int64(h.Sum64())
+}
+
+func loadPathRewrite(r interface{}) (*pathRewrite, error) {
+	def, ok := r.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("`pathRewrite` must be a map with `pattern` and `replacement`")
+	}
+	if err := validateMapKeys(def, "pattern", "replacement"); err != nil {
+		return nil, fmt.Errorf("`pathRewrite`: %s", err.Error())
+	}
+	pattern, ok := def["pattern"].(string)
+	if !ok {
+		return nil, errors.New("`pathRewrite` requires string `pattern`")
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("`pathRewrite.pattern`: %s", err.Error())
+	}
+	var replacement string
+	if v, ok := def["replacement"]; ok {
+		if replacement, ok = v.(string); !ok {
+			return nil, errors.New("`pathRewrite.replacement` must be string")
+		}
+	}
+	return &pathRewrite{pattern: rx, replacement: replacement}, nil
+}
+
 func (l *Loader) loadHeaders(def map[interface{}]interface{}) (map[string]string, error) {
 	var headers map[string]string
 	if h, ok := def["headers"]; ok {
@@ -256,8 +467,14 @@ func (l *Loader) loadConstraintOfKind(kind string, def map[interface{}]interface
 		*ak = append(*ak, "method")
 		return l.loadMethodIsConstraint(def)
 	case "headerIs":
-		*ak = append(*ak, "header", "value", "regexp")
+		*ak = append(*ak, "header", "value", "regexp", "absent")
 		return l.loadHeaderIsConstraint(def)
+	case "contentTypeIs":
+		*ak = append(*ak, "value")
+		return l.loadContentTypeIsConstraint(def)
+	case "headerPropagated":
+		*ak = append(*ak, "header", "from", "regexp")
+		return l.loadHeaderPropagatedConstraint(def)
 	default:
 		return nil, fmt.Errorf("unknown constraint: %s", kind)
 	}
@@ -321,7 +538,58 @@ func (l *Loader) loadHeaderIsConstraint(def map[interface{}]interface{}) (verifi
 			return nil, errors.New("`regexp` must be string")
 		}
 	}
-	return newHeaderConstraint(header, valueStr, regexpStr)
+	var absent bool
+	if a, ok := def["absent"]; ok {
+		absent, ok = a.(bool)
+		if !ok {
+			return nil, errors.New("`absent` must be bool")
+		}
+	}
+	return newHeaderConstraint(header, valueStr, regexpStr, absent)
+}
+
+func (l *Loader) loadContentTypeIsConstraint(def map[interface{}]interface{}) (verifier, error) {
+	c, ok := def["value"]
+	if !ok {
+		return nil, errors.New("`contentTypeIs` requires `value` key")
+	}
+	value, ok := c.(string)
+	if !ok || value == "" {
+		return nil, errors.New("`value` must be string")
+	}
+	return newContentTypeConstraint(value)
+}
+
+func (l *Loader) loadHeaderPropagatedConstraint(def map[interface{}]interface{}) (verifier, error) {
+	c, ok := def["header"]
+	if !ok {
+		return nil, errors.New("`headerPropagated` requires `header` key")
+	}
+	header, ok := c.(string)
+	if !ok || header == "" {
+		return nil, errors.New("`header` must be string")
+	}
+	from := header
+	if f, ok := def["from"]; ok {
+		from, ok = f.(string)
+		if !ok || from == "" {
+			return nil, errors.New("`from` must be string")
+		}
+	}
+	var regexpStr string
+	if regexp, ok := def["regexp"]; ok {
+		regexpStr, ok = regexp.(string)
+		if !ok || regexpStr == "" {
+			return nil, errors.New("`regexp` must be string")
+		}
+	}
+	var value string
+	for name, v := range l.requestHeaders {
+		if strings.EqualFold(name, from) {
+			value = v
+		}
+	}
+	return newHeaderPropagatedConstraint(header, from, value, regexpStr)
 }
 
 func validateMapKeys(m map[interface{}]interface{}, allowedKeys ...string) error {
@@ -17,7 +17,7 @@ func New(mocks ...*ServiceMock) *Mocks {
 func NewNop(serviceNames ...string) *Mocks {
 	mocksMap := make(map[string]*ServiceMock, len(serviceNames))
 	for _, name := range serviceNames {
-		mocksMap[name] = NewServiceMock(name, newDefinition("$", nil, &nopReply{}, callsNoConstraint))
+		mocksMap[name] = NewServiceMock(name, newDefinition("$", nil, &nopReply{}, noCallsRange))
 	}
 	return &Mocks{
 		mocks: mocksMap,
@@ -65,3 +65,19 @@ func (m *Mocks) EndRunningContext() []error {
 	}
 	return errors
 }
+
+func (m *Mocks) CheckUnusedEndpoints() []error {
+	var errors []error
+	for _, v := range m.mocks {
+		errors = append(errors, v.CheckUnusedEndpoints()...)
+	}
+	return errors
+}
+
+func (m *Mocks) CheckUnexpectedRequests() []error {
+	var errors []error
+	for _, v := range m.mocks {
+		errors = append(errors, v.CheckUnexpectedRequests()...)
+	}
+	return errors
+}
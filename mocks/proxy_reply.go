@@ -0,0 +1,172 @@
+package mocks
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProxyTimeout    = 10 * time.Second
+	defaultProxyRetryDelay = 100 * time.Millisecond
+)
+
+// UpstreamError is reported when the upstream of proxy strategy failed,
+// that is an environment problem rather than a test failure
+type UpstreamError struct {
+	error
+	URL      string
+	Attempts int
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("upstream %s failed after %d attempt(s): %s", e.URL, e.Attempts, e.error.Error())
+}
+
+// IsUpstreamError checks whether the mock error is caused by the upstream failure
+func IsUpstreamError(err error) bool {
+	if e, ok := err.(*Error); ok {
+		err = e.error
+	}
+	_, ok := err.(*UpstreamError)
+	return ok
+}
+
+type proxiedResponse struct {
+	statusCode int
+	headers    http.Header
+	body       []byte
+}
+
+func (r *proxiedResponse) write(w http.ResponseWriter) {
+	for k, v := range r.headers {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.statusCode)
+	w.Write(r.body)
+}
+
+// pathRewrite replaces the matches of the pattern in the path of the proxied request
+type pathRewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type proxyReply struct {
+	replyStrategy
+
+	url     string
+	rewrite *pathRewrite
+	client  *http.Client
+	retries int
+	backoff *backoff
+
+	sync.Mutex
+	// successful responses are replayed if the upstream fails on the same request later
+	cache map[string]*proxiedResponse
+}
+
+func newProxyReply(url string, rewrite *pathRewrite, timeout time.Duration, retries int, backoff *backoff) replyStrategy {
+	return &proxyReply{
+		url:     strings.TrimRight(url, "/"),
+		rewrite: rewrite,
+		client:  &http.Client{Timeout: timeout},
+		retries: retries,
+		backoff: backoff,
+		cache:   make(map[string]*proxiedResponse),
+	}
+}
+
+func (s *proxyReply) HandleRequest(w http.ResponseWriter, r *http.Request) []error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return []error{err}
+	}
+	key := r.Method + " " + r.URL.RequestURI() + "\n" + string(body)
+
+	resp, attempts, err := s.fetch(r, body)
+	if err == nil {
+		s.Lock()
+		s.cache[key] = resp
+		s.Unlock()
+		resp.write(w)
+		return nil
+	}
+
+	s.Lock()
+	cached, ok := s.cache[key]
+	s.Unlock()
+	if ok {
+		cached.write(w)
+		return nil
+	}
+
+	// pass the last upstream failure through
+	if resp != nil {
+		resp.write(w)
+	} else {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	return []error{&UpstreamError{
+		error:    err,
+		URL:      s.upstreamURL(r),
+		Attempts: attempts,
+	}}
+}
+
+// fetch requests the upstream retrying on network errors and 5xx responses,
+// the delays between the attempts are set by the backoff
+func (s *proxyReply) fetch(r *http.Request, body []byte) (*proxiedResponse, int, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := s.do(r, body)
+		if err == nil && resp.statusCode >= http.StatusInternalServerError {
+			err = fmt.Errorf("responded with status %d", resp.statusCode)
+		}
+		if err == nil || attempt > s.retries {
+			return resp, attempt, err
+		}
+		time.Sleep(s.backoff.retryDelay(attempt))
+	}
+}
+
+// upstreamURL appends the path and the query of the request to the url, the path is rewritten if configured
+func (s *proxyReply) upstreamURL(r *http.Request) string {
+	if s.rewrite == nil {
+		return s.url + r.URL.RequestURI()
+	}
+	u := *r.URL
+	u.Path = s.rewrite.pattern.ReplaceAllString(u.Path, s.rewrite.replacement)
+	u.RawPath = ""
+	return s.url + u.RequestURI()
+}
+
+func (s *proxyReply) do(r *http.Request, body []byte) (*proxiedResponse, error) {
+	req, err := http.NewRequest(r.Method, s.upstreamURL(r), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &proxiedResponse{
+		statusCode: resp.StatusCode,
+		headers:    resp.Header,
+		body:       respBody,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUpstream(failures int) (*httptest.Server, *int) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("upstream " + r.URL.RequestURI()))
+	}))
+	return srv, &calls
+}
+
+func testBackoff(t *testing.T, delay time.Duration, jitter string) *backoff {
+	b, err := newBackoff(delay, jitter, 1)
+	require.NoError(t, err)
+	return b
+}
+
+func TestProxyReplyShouldRetryUpstream(t *testing.T) {
+	upstream, calls := testUpstream(2)
+	defer upstream.Close()
+
+	s := newProxyReply(upstream.URL, nil, time.Second, 2, testBackoff(t, time.Millisecond, ""))
+	w := httptest.NewRecorder()
+	errs := s.HandleRequest(w, httptest.NewRequest(http.MethodGet, "/path?a=1", nil))
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "upstream /path?a=1", w.Body.String())
+}
+
+func TestProxyReplyShouldReportUpstreamFailure(t *testing.T) {
+	upstream, calls := testUpstream(10)
+	defer upstream.Close()
+
+	s := newProxyReply(upstream.URL, nil, time.Second, 1, testBackoff(t, time.Millisecond, ""))
+	w := httptest.NewRecorder()
+	errs := s.HandleRequest(w, httptest.NewRequest(http.MethodPost, "/path", strings.NewReader("body")))
+
+	assert.Equal(t, 2, *calls)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "upstream "+upstream.URL+"/path failed after 2 attempt(s): responded with status 503", errs[0].Error())
+	assert.True(t, IsUpstreamError(&Error{error: errs[0], ServiceName: "service"}))
+	assert.False(t, IsUpstreamError(&Error{error: assert.AnError, ServiceName: "service"}))
+}
+
+func TestProxyReplyShouldReplayCachedResponse(t *testing.T) {
+	upstream, calls := testUpstream(0)
+
+	s := newProxyReply(upstream.URL, nil, time.Second, 0, testBackoff(t, time.Millisecond, ""))
+	errs := s.HandleRequest(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", nil))
+	require.Empty(t, errs)
+	upstream.Close()
+
+	w := httptest.NewRecorder()
+	errs = s.HandleRequest(w, httptest.NewRequest(http.MethodGet, "/path", nil))
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, "upstream /path", w.Body.String())
+}
+
+func TestProxyReplyShouldRewritePath(t *testing.T) {
+	upstream, _ := testUpstream(0)
+	defer upstream.Close()
+
+	rewrite := &pathRewrite{pattern: regexp.MustCompile(`^/api/v1/`), replacement: "/v2/"}
+	s := newProxyReply(upstream.URL, rewrite, time.Second, 0, testBackoff(t, time.Millisecond, ""))
+	w := httptest.NewRecorder()
+	errs := s.HandleRequest(w, httptest.NewRequest(http.MethodGet, "/api/v1/books?id=1", nil))
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "upstream /v2/books?id=1", w.Body.String())
+}
@@ -1,9 +1,11 @@
 package mocks
 
 import (
-	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
+
+	"github.com/lamoda/gonkey/internal/files"
 )
 
 type replyStrategy interface {
@@ -23,14 +25,18 @@ type constantReply struct {
 	headers    map[string]string
 }
 
-func newFileReplyWithCode(filename string, statusCode int, headers map[string]string) replyStrategy {
-	content, _ := ioutil.ReadFile(filename)
+func newFileReplyWithCode(filename string, statusCode int, headers map[string]string) (replyStrategy, error) {
+	// files with .gz extension are served decompressed, the unreadable file is served as the empty body
+	content, err := files.ReadFile(filename)
+	if _, unreadable := err.(*os.PathError); err != nil && !unreadable {
+		return nil, err
+	}
 	r := &constantReply{
 		replyBody:  content,
 		statusCode: statusCode,
 		headers:    headers,
 	}
-	return r
+	return r, nil
 }
 
 func newConstantReplyWithCode(content []byte, statusCode int, headers map[string]string) replyStrategy {
@@ -82,7 +88,7 @@ func (s *uriVaryReply) HandleRequest(w http.ResponseWriter, r *http.Request) []e
 		}
 	}
 	w.WriteHeader(http.StatusNotFound)
-	return nil
+	return []error{newUnexpectedRequestError(r)}
 }
 
 func (s *uriVaryReply) ResetRunningContext() {
@@ -119,7 +125,7 @@ func (s *methodVaryReply) HandleRequest(w http.ResponseWriter, r *http.Request)
 		}
 	}
 	w.WriteHeader(http.StatusMethodNotAllowed)
-	return nil
+	return []error{newUnexpectedRequestError(r)}
 }
 
 func (s *methodVaryReply) ResetRunningContext() {
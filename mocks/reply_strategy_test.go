@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGzipFile(t *testing.T, path string, content string) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestFileReplyShouldDecompressGzipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "books.json.gz")
+	writeGzipFile(t, filename, `{"books": []}`)
+
+	reply, err := newFileReplyWithCode(filename, http.StatusOK, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	assert.Empty(t, reply.HandleRequest(w, httptest.NewRequest(http.MethodGet, "/books", nil)))
+	assert.Equal(t, `{"books": []}`, w.Body.String())
+}
+
+func TestFileReplyShouldReportCorruptedGzipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "books.json.gz")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`{"books": []}`), 0644))
+
+	_, err = newFileReplyWithCode(filename, http.StatusOK, nil)
+	assert.EqualError(t, err, "unable to decompress "+filename+": gzip: invalid header")
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -86,9 +87,13 @@ type headerConstraint struct {
 	header string
 	value  string
 	regexp *regexp.Regexp
+	absent bool
 }
 
-func newHeaderConstraint(header, value, re string) (verifier, error) {
+func newHeaderConstraint(header, value, re string, absent bool) (verifier, error) {
+	if absent && (value != "" || re != "") {
+		return nil, fmt.Errorf("header %s can't be both absent and have a value", header)
+	}
 	var reCompiled *regexp.Regexp
 	if re != "" {
 		var err error
@@ -101,12 +106,19 @@ func newHeaderConstraint(header, value, re string) (verifier, error) {
 		header: header,
 		value:  value,
 		regexp: reCompiled,
+		absent: absent,
 	}
 	return res, nil
 }
 
 func (c *headerConstraint) Verify(r *http.Request) []error {
 	value := r.Header.Get(c.header)
+	if c.absent {
+		if _, ok := r.Header[http.CanonicalHeaderKey(c.header)]; ok {
+			return []error{fmt.Errorf("request has header %s with value %s, expected to be absent", c.header, value)}
+		}
+		return nil
+	}
 	if value == "" {
 		return []error{fmt.Errorf("request doesn't have header %s", c.header)}
 	}
@@ -119,6 +131,111 @@ func (c *headerConstraint) Verify(r *http.Request) []error {
 	return nil
 }
 
+// contentTypeConstraint compares the media type of the request ignoring the parameters
+// unless they are expected, e.g. application/json matches application/json; charset=utf-8
+type contentTypeConstraint struct {
+	verifier
+
+	value     string
+	mediaType string
+	params    map[string]string
+}
+
+func newContentTypeConstraint(value string) (verifier, error) {
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %s: %s", value, err.Error())
+	}
+	res := &contentTypeConstraint{
+		value:     value,
+		mediaType: mediaType,
+		params:    params,
+	}
+	return res, nil
+}
+
+func (c *contentTypeConstraint) Verify(r *http.Request) []error {
+	value := r.Header.Get("Content-Type")
+	if value == "" {
+		return []error{fmt.Errorf("request doesn't have header Content-Type, expected %s", c.value)}
+	}
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return []error{fmt.Errorf("request has invalid content type %s: %s", value, err.Error())}
+	}
+	if mediaType != c.mediaType {
+		return []error{fmt.Errorf("request content type %s doesn't match expected %s", value, c.value)}
+	}
+	for name, expected := range c.params {
+		if !strings.EqualFold(params[name], expected) {
+			return []error{fmt.Errorf("request content type %s doesn't match expected %s", value, c.value)}
+		}
+	}
+	return nil
+}
+
+// headerPropagatedConstraint checks that the header of the mock request carries the value
+// of the test request header, or the first group of the regexp, e.g. the trace id of traceparent
+type headerPropagatedConstraint struct {
+	verifier
+
+	header string
+	from   string
+	value  string
+	regexp *regexp.Regexp
+}
+
+func newHeaderPropagatedConstraint(header, from, value, re string) (verifier, error) {
+	res := &headerPropagatedConstraint{
+		header: header,
+		from:   from,
+		value:  value,
+	}
+	if re != "" {
+		var err error
+		if res.regexp, err = regexp.Compile(re); err != nil {
+			return nil, err
+		}
+		if res.regexp.NumSubexp() < 1 {
+			return nil, fmt.Errorf("regexp %s must have a group of the propagated part", re)
+		}
+	}
+	return res, nil
+}
+
+func (c *headerPropagatedConstraint) Verify(r *http.Request) []error {
+	if c.value == "" {
+		return []error{fmt.Errorf("test request doesn't have header %s to propagate", c.from)}
+	}
+	value := r.Header.Get(c.header)
+	if value == "" {
+		return []error{fmt.Errorf("request doesn't have header %s propagated from %s of the test request", c.header, c.from)}
+	}
+	expected, actual := c.value, value
+	if c.regexp != nil {
+		var ok bool
+		if expected, ok = c.propagatedPart(c.value); !ok {
+			return []error{fmt.Errorf("%s header value %s of the test request doesn't match regexp %s", c.from, c.value, c.regexp)}
+		}
+		if actual, ok = c.propagatedPart(value); !ok {
+			return []error{fmt.Errorf("%s header value %s doesn't match regexp %s", c.header, value, c.regexp)}
+		}
+	}
+	if expected != actual {
+		return []error{fmt.Errorf("%s header is not propagated: %s expected from %s of the test request, got %s",
+			c.header, expected, c.from, actual)}
+	}
+	return nil
+}
+
+func (c *headerPropagatedConstraint) propagatedPart(value string) (string, bool) {
+	match := c.regexp.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
 type queryConstraint struct {
 	expectedQuery url.Values
 }
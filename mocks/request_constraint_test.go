@@ -102,3 +102,255 @@ func newTestRequest(query string) *http.Request {
 	r, _ := http.NewRequest("GET", "http://localhost/?"+query, nil)
 	return r
 }
+
+func TestHeaderPropagatedConstraint(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceIDRegexp = `^[0-9a-f]{2}-([0-9a-f]{32})-`
+	tests := []struct {
+		name       string
+		header     string
+		from       string
+		value      string
+		regexp     string
+		reqHeaders map[string]string
+		wantErrors int
+	}{
+		{
+			name:       "same value",
+			header:     "X-Request-Id",
+			from:       "X-Request-Id",
+			value:      "abc",
+			reqHeaders: map[string]string{"X-Request-Id": "abc"},
+			wantErrors: 0,
+		},
+		{
+			name:       "value from other header",
+			header:     "X-Correlation-Id",
+			from:       "X-Request-Id",
+			value:      "abc",
+			reqHeaders: map[string]string{"X-Correlation-Id": "abc"},
+			wantErrors: 0,
+		},
+		{
+			name:       "other value",
+			header:     "X-Request-Id",
+			from:       "X-Request-Id",
+			value:      "abc",
+			reqHeaders: map[string]string{"X-Request-Id": "def"},
+			wantErrors: 1,
+		},
+		{
+			name:       "header is not propagated",
+			header:     "X-Request-Id",
+			from:       "X-Request-Id",
+			value:      "abc",
+			wantErrors: 1,
+		},
+		{
+			name:       "test request without header",
+			header:     "X-Request-Id",
+			from:       "X-Request-Id",
+			reqHeaders: map[string]string{"X-Request-Id": "abc"},
+			wantErrors: 1,
+		},
+		{
+			name:       "same trace id in new span",
+			header:     "traceparent",
+			from:       "traceparent",
+			value:      traceparent,
+			regexp:     traceIDRegexp,
+			reqHeaders: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01"},
+			wantErrors: 0,
+		},
+		{
+			name:       "other trace id",
+			header:     "traceparent",
+			from:       "traceparent",
+			value:      traceparent,
+			regexp:     traceIDRegexp,
+			reqHeaders: map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			wantErrors: 1,
+		},
+		{
+			name:       "malformed traceparent",
+			header:     "traceparent",
+			from:       "traceparent",
+			value:      traceparent,
+			regexp:     traceIDRegexp,
+			reqHeaders: map[string]string{"traceparent": "broken"},
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newHeaderPropagatedConstraint(tt.header, tt.from, tt.value, tt.regexp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, _ := http.NewRequest("GET", "http://localhost/", nil)
+			for k, v := range tt.reqHeaders {
+				r.Header.Set(k, v)
+			}
+			if gotErrors := c.Verify(r); len(gotErrors) != tt.wantErrors {
+				t.Errorf("unexpected amount of errors. Got %v, want %v. Errors are: '%v'",
+					len(gotErrors), tt.wantErrors, gotErrors,
+				)
+			}
+		})
+	}
+}
+
+func TestLoadForRequestShouldTakeHeaderOfTestRequest(t *testing.T) {
+	l := NewLoader(NewNop("service"))
+	def := map[interface{}]interface{}{
+		"header": "X-Request-Id",
+	}
+	var ak []string
+	c, err := l.loadConstraintOfKind("headerPropagated", def, &ak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.(*headerPropagatedConstraint).value; got != "" {
+		t.Errorf("unexpected value without test request: %s", got)
+	}
+
+	l.requestHeaders = map[string]string{"x-request-id": "abc"}
+	c, err = l.loadConstraintOfKind("headerPropagated", def, &ak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.(*headerPropagatedConstraint).value; got != "abc" {
+		t.Errorf("unexpected value of test request header: %s", got)
+	}
+}
+
+func TestHeaderPropagatedConstraintRequiresRegexpGroup(t *testing.T) {
+	if _, err := newHeaderPropagatedConstraint("traceparent", "traceparent", "", "^00-"); err == nil {
+		t.Error("expected error of regexp without group")
+	}
+}
+
+func TestHeaderConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		regexp     string
+		absent     bool
+		reqHeaders map[string]string
+		wantErrors int
+	}{
+		{
+			name:       "exact value",
+			value:      "application/json",
+			reqHeaders: map[string]string{"Content-Type": "application/json"},
+			wantErrors: 0,
+		},
+		{
+			name:       "other value",
+			value:      "application/json",
+			reqHeaders: map[string]string{"Content-Type": "text/plain"},
+			wantErrors: 1,
+		},
+		{
+			name:       "regexp",
+			regexp:     "^application/(.+\\+)?json",
+			reqHeaders: map[string]string{"Content-Type": "application/problem+json"},
+			wantErrors: 0,
+		},
+		{
+			name:       "missing header",
+			value:      "application/json",
+			wantErrors: 1,
+		},
+		{
+			name:       "absent",
+			absent:     true,
+			wantErrors: 0,
+		},
+		{
+			name:       "present but expected to be absent",
+			absent:     true,
+			reqHeaders: map[string]string{"Content-Type": "application/json"},
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newHeaderConstraint("Content-Type", tt.value, tt.regexp, tt.absent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, _ := http.NewRequest("POST", "http://localhost/", nil)
+			for k, v := range tt.reqHeaders {
+				r.Header.Set(k, v)
+			}
+			if gotErrors := c.Verify(r); len(gotErrors) != tt.wantErrors {
+				t.Errorf("unexpected amount of errors. Got %v, want %v. Errors are: '%v'",
+					len(gotErrors), tt.wantErrors, gotErrors,
+				)
+			}
+		})
+	}
+}
+
+func TestContentTypeConstraint(t *testing.T) {
+	tests := []struct {
+		name        string
+		expected    string
+		contentType string
+		wantErrors  int
+	}{
+		{
+			name:        "same type",
+			expected:    "application/json",
+			contentType: "application/json",
+			wantErrors:  0,
+		},
+		{
+			name:        "parameters are ignored",
+			expected:    "application/json",
+			contentType: "application/json; charset=utf-8",
+			wantErrors:  0,
+		},
+		{
+			name:        "expected parameters",
+			expected:    "text/plain; charset=utf-8",
+			contentType: "text/plain; charset=UTF-8",
+			wantErrors:  0,
+		},
+		{
+			name:        "other parameters",
+			expected:    "text/plain; charset=utf-8",
+			contentType: "text/plain; charset=windows-1251",
+			wantErrors:  1,
+		},
+		{
+			name:        "other type",
+			expected:    "application/json",
+			contentType: "application/x-www-form-urlencoded",
+			wantErrors:  1,
+		},
+		{
+			name:       "no content type",
+			expected:   "application/json",
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newContentTypeConstraint(tt.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, _ := http.NewRequest("POST", "http://localhost/", nil)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if gotErrors := c.Verify(r); len(gotErrors) != tt.wantErrors {
+				t.Errorf("unexpected amount of errors. Got %v, want %v. Errors are: '%v'",
+					len(gotErrors), tt.wantErrors, gotErrors,
+				)
+			}
+		})
+	}
+}
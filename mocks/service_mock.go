@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"sync"
@@ -13,7 +14,8 @@ type ServiceMock struct {
 	mock              *definition
 	defaultDefinition *definition
 	sync.Mutex
-	errors []error
+	errors     []error
+	unexpected []error
 
 	ServiceName string
 }
@@ -54,14 +56,23 @@ func (m *ServiceMock) ServerAddr() string {
 func (m *ServiceMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.Lock()
 	defer m.Unlock()
-	if m.mock != nil {
-		errs := m.mock.Execute(w, r)
-		for _, e := range errs {
-			m.errors = append(m.errors, &Error{
-				error:       e,
-				ServiceName: m.ServiceName,
-			})
+	if m.mock == nil {
+		return
+	}
+	// the service is not declared by the test
+	if m.mock == m.defaultDefinition {
+		m.unexpected = append(m.unexpected, newUnexpectedRequestError(r))
+	}
+	errs := m.mock.Execute(w, r)
+	for _, e := range errs {
+		if _, ok := e.(*UnexpectedRequestError); ok {
+			m.unexpected = append(m.unexpected, e)
+			continue
 		}
+		m.errors = append(m.errors, &Error{
+			error:       e,
+			ServiceName: m.ServiceName,
+		})
 	}
 }
 
@@ -77,6 +88,7 @@ func (m *ServiceMock) ResetDefinition() {
 
 func (m *ServiceMock) ResetRunningContext() {
 	m.errors = nil
+	m.unexpected = nil
 	m.mock.ResetRunningContext()
 }
 
@@ -90,3 +102,39 @@ func (m *ServiceMock) EndRunningContext() []error {
 	}
 	return errs
 }
+
+// CheckUnusedEndpoints returns errors for the endpoints of the loaded definition which were never called
+func (m *ServiceMock) CheckUnusedEndpoints() []error {
+	m.Lock()
+	defer m.Unlock()
+
+	// nothing was declared by the test
+	if m.mock == nil || m.mock == m.defaultDefinition {
+		return nil
+	}
+
+	var errs []error
+	for _, path := range m.mock.unusedPaths() {
+		errs = append(errs, &Error{
+			error:       fmt.Errorf("at path %s: mock was never called", path),
+			ServiceName: m.ServiceName,
+		})
+	}
+	return errs
+}
+
+// CheckUnexpectedRequests returns errors for the requests which do not match any endpoint
+// declared by the test, including all the requests to the service not declared at all
+func (m *ServiceMock) CheckUnexpectedRequests() []error {
+	m.Lock()
+	defer m.Unlock()
+
+	var errs []error
+	for _, e := range m.unexpected {
+		errs = append(errs, &Error{
+			error:       e,
+			ServiceName: m.ServiceName,
+		})
+	}
+	return errs
+}
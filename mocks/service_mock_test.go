@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCheckUnusedEndpoints(t *testing.T) {
+	m := NewNop("service")
+	assert.Empty(t, m.CheckUnusedEndpoints(), "default definitions must not be reported")
+
+	var definition map[string]interface{}
+	err := yaml.Unmarshal([]byte(`
+service:
+  strategy: uriVary
+  uris:
+    /used:
+      strategy: nop
+    /unused:
+      strategy: nop
+`), &definition)
+	require.NoError(t, err)
+	require.NoError(t, NewLoader(m).Load(definition))
+
+	req := httptest.NewRequest(http.MethodGet, "/used", nil)
+	m.Service("service").ServeHTTP(httptest.NewRecorder(), req)
+
+	errs := m.CheckUnusedEndpoints()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "mock service: at path $.uriVary./unused: mock was never called", errs[0].Error())
+}
+
+func TestCheckUnexpectedRequests(t *testing.T) {
+	m := NewNop("service", "undeclared")
+
+	var definition map[string]interface{}
+	err := yaml.Unmarshal([]byte(`
+service:
+  strategy: uriVary
+  uris:
+    /books:
+      strategy: methodVary
+      methods:
+        GET:
+          strategy: nop
+`), &definition)
+	require.NoError(t, err)
+	require.NoError(t, NewLoader(m).Load(definition))
+
+	recorder := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/books", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	m.Service("service").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/authors", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	m.Service("service").ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/books", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	m.Service("undeclared").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	assert.Empty(t, m.EndRunningContext(), "unexpected requests must be reported only on demand")
+
+	var messages []string
+	for _, e := range m.CheckUnexpectedRequests() {
+		messages = append(messages, e.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"mock service: unexpected request GET /authors",
+		"mock service: unexpected request DELETE /books",
+		"mock undeclared: unexpected request POST /orders",
+	}, messages)
+
+	m.ResetRunningContext()
+	assert.Empty(t, m.CheckUnexpectedRequests())
+}
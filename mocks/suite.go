@@ -0,0 +1,179 @@
+package mocks
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	layerSuite = "suite"
+	layerTest  = "test"
+	layerBoth  = "test over suite"
+)
+
+// SetSuiteMocks sets the mocks definitions of all the tests by service, the definition of the test
+// replaces the suite one for the test only, the uris of uriVary and the methods of methodVary are merged
+func (l *Loader) SetSuiteMocks(definitions map[string]interface{}) {
+	l.suiteMocks = definitions
+}
+
+// LoadSuiteMocks reads the suite mocks from the YAML file of the same format as the mocks of the test
+func (l *Loader) LoadSuiteMocks(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var definitions map[string]interface{}
+	if err := yaml.Unmarshal(data, &definitions); err != nil {
+		return fmt.Errorf("unable to parse suite mocks %s: %v", filename, err)
+	}
+	l.SetSuiteMocks(definitions)
+	return nil
+}
+
+func (l *Loader) HasSuiteMocks() bool {
+	return len(l.suiteMocks) > 0
+}
+
+// Strategies returns the strategies of the last loaded definitions by service
+// with the layer they come from, e.g. "constant (suite)"
+func (l *Loader) Strategies() map[string]string {
+	return l.strategies
+}
+
+// layerDefinitions returns the definitions of the test over the suite ones, the layers of the definitions
+// and the uris (methods) of the merged definitions which come from the suite only by service
+func (l *Loader) layerDefinitions(test map[string]interface{}) (map[string]interface{}, map[string]string, map[string][]string) {
+	definitions := make(map[string]interface{}, len(l.suiteMocks)+len(test))
+	layers := make(map[string]string, len(l.suiteMocks)+len(test))
+	suiteItems := make(map[string][]string)
+	for serviceName, definition := range l.suiteMocks {
+		definitions[serviceName] = definition
+		layers[serviceName] = layerSuite
+	}
+	for serviceName, definition := range test {
+		suite, ok := definitions[serviceName]
+		if !ok {
+			definitions[serviceName] = definition
+			layers[serviceName] = layerTest
+			continue
+		}
+		if merged, items, ok := mergeDefinitions(suite, definition); ok {
+			definitions[serviceName] = merged
+			layers[serviceName] = layerBoth
+			suiteItems[serviceName] = items
+		} else {
+			definitions[serviceName] = definition
+			layers[serviceName] = layerTest
+		}
+	}
+	return definitions, layers, suiteItems
+}
+
+// markSuiteDefinitions excludes the suite mocks from the unused mocks check,
+// only the mocks of the test must be called by the test
+func markSuiteDefinitions(def *definition, layer string, suiteItems []string) {
+	switch layer {
+	case layerSuite:
+		def.markSuite()
+	case layerBoth:
+		variants := def.variants()
+		for _, item := range suiteItems {
+			if variant, ok := variants[item]; ok {
+				variant.markSuite()
+			}
+		}
+	}
+}
+
+// mergeDefinitions adds the uris (methods) of the test to the suite ones if both definitions
+// are uriVary with the same basePath (methodVary), the suite definition is left unchanged.
+// The uris (methods) not overridden by the test are returned as well
+func mergeDefinitions(suite, test interface{}) (interface{}, []string, bool) {
+	suiteDef, ok := suite.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	testDef, ok := test.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	strategy, ok := testDef["strategy"].(string)
+	if !ok || suiteDef["strategy"] != strategy {
+		return nil, nil, false
+	}
+	var key string
+	switch strategy {
+	case "uriVary":
+		if suiteDef["basePath"] != testDef["basePath"] {
+			return nil, nil, false
+		}
+		key = "uris"
+	case "methodVary":
+		key = "methods"
+	default:
+		return nil, nil, false
+	}
+	suiteItems, ok := suiteDef[key].(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	testItems, ok := testDef[key].(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	// the definitions with constraints are not merged
+	if !hasOnlyKeys(suiteDef, "strategy", "basePath", key) || !hasOnlyKeys(testDef, "strategy", "basePath", key) {
+		return nil, nil, false
+	}
+
+	items := make(map[interface{}]interface{}, len(suiteItems)+len(testItems))
+	var suiteOnly []string
+	for k, v := range suiteItems {
+		items[k] = v
+		if _, ok := testItems[k]; !ok {
+			if name, ok := k.(string); ok {
+				suiteOnly = append(suiteOnly, name)
+			}
+		}
+	}
+	for k, v := range testItems {
+		items[k] = v
+	}
+	merged := make(map[interface{}]interface{}, len(testDef))
+	for k, v := range testDef {
+		merged[k] = v
+	}
+	merged[key] = items
+	return merged, suiteOnly, true
+}
+
+func hasOnlyKeys(def map[interface{}]interface{}, keys ...string) bool {
+	for k := range def {
+		known := false
+		for _, key := range keys {
+			if k == key {
+				known = true
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+// strategyName names the strategy of the raw definition, the template definitions by the template
+func strategyName(definition interface{}) string {
+	def, ok := definition.(map[interface{}]interface{})
+	if !ok {
+		return ""
+	}
+	if name, ok := def["template"].(string); ok {
+		return "template " + name
+	}
+	name, _ := def["strategy"].(string)
+	return name
+}
@@ -0,0 +1,110 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const suiteMocks = `
+books:
+  strategy: uriVary
+  uris:
+    /books/1:
+      strategy: constant
+      body: '{"id": 1}'
+    /books/2:
+      strategy: constant
+      body: '{"id": 2}'
+users:
+  strategy: constant
+  body: '{"name": "suite"}'
+`
+
+func loadSuiteMocks(t *testing.T) (*Mocks, *Loader) {
+	m := NewNop("books", "users")
+	l := NewLoader(m)
+
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(suiteMocks), &def))
+	l.SetSuiteMocks(def)
+	return m, l
+}
+
+func loadTestMocks(t *testing.T, l *Loader, definition string) {
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &def))
+	require.NoError(t, l.Load(def))
+}
+
+func serve(m *Mocks, service, uri string) string {
+	w := httptest.NewRecorder()
+	m.Service(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, uri, nil))
+	return w.Body.String()
+}
+
+func TestLoadShouldLayerTestMocksOverSuiteMocks(t *testing.T) {
+	m, l := loadSuiteMocks(t)
+
+	loadTestMocks(t, l, `
+books:
+  strategy: uriVary
+  uris:
+    /books/2:
+      strategy: constant
+      body: '{"id": 2, "overridden": true}'
+`)
+
+	assert.Equal(t, `{"id": 1}`, serve(m, "books", "/books/1"))
+	assert.Equal(t, `{"id": 2, "overridden": true}`, serve(m, "books", "/books/2"))
+	assert.Equal(t, `{"name": "suite"}`, serve(m, "users", "/users/1"))
+	assert.Equal(t, map[string]string{
+		"books": "uriVary (test over suite)",
+		"users": "constant (suite)",
+	}, l.Strategies())
+}
+
+func TestLoadShouldReplaceSuiteMockOfOtherStrategy(t *testing.T) {
+	m, l := loadSuiteMocks(t)
+
+	loadTestMocks(t, l, `
+users:
+  strategy: uriVary
+  uris:
+    /users/1:
+      strategy: constant
+      body: '{"name": "test"}'
+`)
+	assert.Equal(t, `{"name": "test"}`, serve(m, "users", "/users/1"))
+	assert.Equal(t, "uriVary (test)", l.Strategies()["users"])
+
+	// the next test gets the suite mocks back
+	m.ResetDefinitions()
+	loadTestMocks(t, l, ``)
+	assert.Equal(t, `{"name": "suite"}`, serve(m, "users", "/users/1"))
+	assert.Equal(t, "constant (suite)", l.Strategies()["users"])
+}
+
+func TestLoadShouldNotChangeSuiteMocks(t *testing.T) {
+	m, l := loadSuiteMocks(t)
+
+	loadTestMocks(t, l, `
+books:
+  strategy: uriVary
+  uris:
+    /books/3:
+      strategy: constant
+      body: '{"id": 3}'
+`)
+	assert.Equal(t, `{"id": 3}`, serve(m, "books", "/books/3"))
+
+	m.ResetDefinitions()
+	loadTestMocks(t, l, ``)
+	w := httptest.NewRecorder()
+	m.Service("books").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books/3", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,84 @@
+package mocks
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// AddTemplate registers mock definition template, the definition is YAML text
+// with text/template placeholders filled with the arguments given in the test
+func (l *Loader) AddTemplate(name, definition string) error {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(definition)
+	if err != nil {
+		return fmt.Errorf("unable to parse mock template %s: %v", name, err)
+	}
+	if l.templates == nil {
+		l.templates = make(map[string]*template.Template)
+	}
+	l.templates[name] = tmpl
+	return nil
+}
+
+// LoadTemplates registers every .yaml (.yml) file of the directory as a template
+// named after the file without extension
+func (l *Loader) LoadTemplates(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return err
+		}
+		if err := l.AddTemplate(strings.TrimSuffix(file.Name(), ext), string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandTemplate returns the definition made of the template with the given arguments
+func (l *Loader) expandTemplate(path string, def map[interface{}]interface{}) (interface{}, error) {
+	name, ok := def["template"].(string)
+	if !ok {
+		return nil, fmt.Errorf("at path %s: `template` must be string", path)
+	}
+	tmpl, ok := l.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("at path %s: unknown mock template %s", path, name)
+	}
+	args := make(map[string]interface{})
+	if a, ok := def["args"]; ok {
+		argsMap, ok := a.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("at path %s: `args` must be a map", path)
+		}
+		for k, v := range argsMap {
+			args[fmt.Sprint(k)] = v
+		}
+	}
+	if err := validateMapKeys(def, "template", "args"); err != nil {
+		return nil, fmt.Errorf("at path %s: %v", path, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, args); err != nil {
+		return nil, fmt.Errorf("at path %s: unable to expand mock template %s: %v", path, name, err)
+	}
+	var expanded interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &expanded); err != nil {
+		return nil, fmt.Errorf("at path %s: mock template %s is not a valid YAML: %v", path, name, err)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,137 @@
+package mocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/lamoda/gonkey/compare"
+)
+
+// templateReply renders the response body for every request, the template gets the request as templateRequest
+type templateReply struct {
+	replyStrategy
+
+	body        *template.Template
+	statusCode  int
+	headers     map[string]string
+	pathPattern string
+}
+
+func newTemplateReply(path, body string, statusCode int, headers map[string]string, pathPattern string) (replyStrategy, error) {
+	tmpl, err := template.New(path).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return &templateReply{
+		body:        tmpl,
+		statusCode:  statusCode,
+		headers:     headers,
+		pathPattern: pathPattern,
+	}, nil
+}
+
+func (s *templateReply) HandleRequest(w http.ResponseWriter, r *http.Request) []error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return []error{err}
+	}
+	request := &templateRequest{
+		request: r,
+		body:    body,
+		params:  pathParams(s.pathPattern, r.URL.Path),
+	}
+
+	buf := &bytes.Buffer{}
+	if err := s.body.Execute(buf, request); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return []error{fmt.Errorf("unable to render the response template: %s", err.Error())}
+	}
+	for k, v := range s.headers {
+		w.Header().Add(k, v)
+	}
+	w.WriteHeader(s.statusCode)
+	w.Write(buf.Bytes())
+	return nil
+}
+
+// pathParams matches the {name} segments of the pattern with the segments of the path
+func pathParams(pattern, path string) map[string]string {
+	params := make(map[string]string)
+	if pattern == "" {
+		return params
+	}
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return params
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = pathSegments[i]
+		}
+	}
+	return params
+}
+
+// templateRequest is the request available to the response template
+type templateRequest struct {
+	request *http.Request
+	body    []byte
+	params  map[string]string
+}
+
+// Method returns the method of the request
+func (r *templateRequest) Method() string {
+	return r.request.Method
+}
+
+// Path returns the path of the request
+func (r *templateRequest) Path() string {
+	return r.request.URL.Path
+}
+
+// PathParam returns the path segment matched by {name} of pathPattern
+func (r *templateRequest) PathParam(name string) (string, error) {
+	value, ok := r.params[name]
+	if !ok {
+		return "", fmt.Errorf("path %s has no parameter %s", r.request.URL.Path, name)
+	}
+	return value, nil
+}
+
+// Query returns the first value of the query parameter, empty if there is none
+func (r *templateRequest) Query(name string) string {
+	return r.request.URL.Query().Get(name)
+}
+
+// Header returns the first value of the header, empty if there is none
+func (r *templateRequest) Header(name string) string {
+	return r.request.Header.Get(name)
+}
+
+// Body returns the body of the request as is
+func (r *templateRequest) Body() string {
+	return string(r.body)
+}
+
+// JSON returns the value found by the path in the JSON body, strings as is and other values as JSON
+func (r *templateRequest) JSON(path string) (string, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(r.body, &decoded); err != nil {
+		return "", fmt.Errorf("request body is not JSON: %s", err.Error())
+	}
+	value, err := compare.ResolvePath(decoded, path)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
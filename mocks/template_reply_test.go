@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func loadMock(t *testing.T, definition string) (*Mocks, error) {
+	m := NewNop("service")
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &def))
+	return m, NewLoader(m).Load(def)
+}
+
+func TestTemplateReplyShouldRenderRequestData(t *testing.T) {
+	m, err := loadMock(t, `
+service:
+  strategy: template
+  pathPattern: /users/{user}/orders
+  statusCode: 201
+  headers:
+    Content-Type: application/json
+  body: >
+    {"id": "{{ .PathParam "user" }}-{{ .JSON "$.number" }}", "items": {{ .JSON "$.items" }},
+    "source": "{{ .Query "source" }}", "by": "{{ .Header "X-User" }}", "method": "{{ .Method }}"}
+`)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/7/orders?source=web", strings.NewReader(`{"number": 42, "items": [1, 2]}`))
+	req.Header.Set("X-User", "admin")
+	w := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id": "7-42", "items": [1,2], "source": "web", "by": "admin", "method": "POST"}`, w.Body.String())
+	assert.Empty(t, m.EndRunningContext())
+}
+
+func TestTemplateReplyShouldReportRenderErrors(t *testing.T) {
+	m, err := loadMock(t, `
+service:
+  strategy: template
+  body: '{"id": {{ .JSON "$.id" }}}'
+`)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	errs := m.EndRunningContext()
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "unable to render the response template")
+
+	_, err = loadMock(t, `
+service:
+  strategy: template
+  body: '{{ .Body '
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "`body`: ")
+}
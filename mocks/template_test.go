@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const bookTemplate = `
+strategy: constant
+body: '{"id": {{ .id }}, "title": "{{ .title }}"}'
+statusCode: {{ .status }}
+`
+
+func loadTemplatedMock(t *testing.T, definition string) (*Mocks, error) {
+	m := NewNop("service")
+	l := NewLoader(m)
+	require.NoError(t, l.AddTemplate("book", bookTemplate))
+
+	var def map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &def))
+	return m, l.Load(def)
+}
+
+func TestLoadShouldExpandTemplate(t *testing.T) {
+	m, err := loadTemplatedMock(t, `
+service:
+  strategy: uriVary
+  uris:
+    /books/1:
+      template: book
+      args:
+        id: 1
+        title: Dune
+        status: 200
+    /books/2:
+      template: book
+      args:
+        id: 2
+        title: Unknown
+        status: 404
+`)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	m.Service("service").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books/2", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"id": 2, "title": "Unknown"}`, w.Body.String())
+}
+
+func TestLoadShouldReportTemplateErrors(t *testing.T) {
+	_, err := loadTemplatedMock(t, `
+service:
+  template: book
+  args:
+    id: 1
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at path $: unable to expand mock template book")
+
+	_, err = loadTemplatedMock(t, `
+service:
+  template: unknown
+`)
+	assert.EqualError(t, err, "unable to load definition for service: at path $: unknown mock template unknown")
+}
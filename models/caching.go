@@ -0,0 +1,24 @@
+package models
+
+const (
+	DefaultCacheHitHeader = "X-Cache"
+	DefaultCacheHitValue  = "HIT"
+)
+
+// CachingCheck describes the expected caching behaviour of the response
+type CachingCheck struct {
+	// Headers must be present in the response, e.g. Cache-Control, ETag or Vary
+	Headers []string
+	// NotModified repeats the request with If-None-Match set to the response ETag
+	// and expects 304 Not Modified
+	NotModified bool
+	// Hit repeats the request and expects the repeated response to be served from the cache
+	Hit *CacheHitCheck
+}
+
+// CacheHitCheck describes the header which marks the response served from the cache
+type CacheHitCheck struct {
+	Header string
+	// Value is compared as the value of responseHeaders, $matchRegexp is supported
+	Value string
+}
@@ -0,0 +1,56 @@
+package models
+
+type ErrorCategory string
+
+const (
+	ErrorCategoryMock        ErrorCategory = "mock"
+	ErrorCategoryUpstream    ErrorCategory = "upstream"
+	ErrorCategoryBody        ErrorCategory = "body"
+	ErrorCategoryBodyHash    ErrorCategory = "bodyHash"
+	ErrorCategoryBodyMatches ErrorCategory = "bodyMatches"
+	ErrorCategoryFields      ErrorCategory = "fields"
+	ErrorCategoryKeys        ErrorCategory = "keys"
+	ErrorCategoryValidation  ErrorCategory = "validation"
+	ErrorCategoryStructure   ErrorCategory = "structure"
+	ErrorCategoryNDJSON      ErrorCategory = "ndjson"
+	ErrorCategoryHeader      ErrorCategory = "header"
+	ErrorCategoryCookies     ErrorCategory = "cookies"
+	ErrorCategoryStatus      ErrorCategory = "status"
+	ErrorCategoryProblem     ErrorCategory = "problem"
+	ErrorCategorySchema      ErrorCategory = "schema"
+	ErrorCategoryDb          ErrorCategory = "db"
+	ErrorCategoryRedis       ErrorCategory = "redis"
+	ErrorCategoryIdempotency ErrorCategory = "idempotency"
+	ErrorCategoryRepeat      ErrorCategory = "repeat"
+	ErrorCategoryCaching     ErrorCategory = "caching"
+	ErrorCategoryParity      ErrorCategory = "parity"
+	ErrorCategoryPagination  ErrorCategory = "pagination"
+	ErrorCategoryCapture     ErrorCategory = "capture"
+	ErrorCategoryContinue    ErrorCategory = "continue"
+
+	ErrorCategoryContentLength ErrorCategory = "contentLength"
+	ErrorCategoryDbQueries     ErrorCategory = "dbQueries"
+	ErrorCategoryLoad          ErrorCategory = "load"
+	ErrorCategoryResponseTime  ErrorCategory = "responseTime"
+	ErrorCategoryConnections   ErrorCategory = "connectionReuse"
+	ErrorCategoryBodyForbidden ErrorCategory = "bodyForbidden"
+	// ErrorCategoryOther is counted for the errors without a category
+	ErrorCategoryOther ErrorCategory = "other"
+)
+
+// CheckError is an error found while checking the test result
+type CheckError struct {
+	error
+	category ErrorCategory
+}
+
+func NewCheckError(category ErrorCategory, err error) *CheckError {
+	return &CheckError{
+		error:    err,
+		category: category,
+	}
+}
+
+func (e *CheckError) GetCategory() ErrorCategory {
+	return e.category
+}
@@ -0,0 +1,10 @@
+package models
+
+// ChecksOrder describes the order of the checks of the test result
+type ChecksOrder struct {
+	// Order lists categories of the checks run first, the others run after them
+	// in the order the checkers are registered
+	Order []ErrorCategory
+	// StopOnFailure skips the rest of the checks once a check reports errors
+	StopOnFailure bool
+}
@@ -0,0 +1,25 @@
+package models
+
+// ConnectionReuseCheck describes counting the connections opened by the requests of the test
+type ConnectionReuseCheck struct {
+	// WarmUp is the number of the requests sent before the test to open the connections, they are not counted
+	WarmUp int
+	// MaxConnections is the limit of the new connections, not checked if nil
+	MaxConnections *int
+	// MaxTLSHandshakes is the limit of the TLS handshakes, not checked if nil
+	MaxTLSHandshakes *int
+	// ScenarioMaxConnections and ScenarioMaxTLSHandshakes limit the same counts of all the requests
+	// of the tests of the file run so far, warm-up ones included, not checked if nil
+	ScenarioMaxConnections   *int
+	ScenarioMaxTLSHandshakes *int
+}
+
+// ConnectionsResult counts the connections used by the requests of the test after the warm-up,
+// or by the requests of the scenario
+type ConnectionsResult struct {
+	Requests int
+	// Connections are the new connections, Reused are the idle connections of the previous requests
+	Connections   int
+	Reused        int
+	TLSHandshakes int
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ContinueResult describes the handling of the request sent with Expect: 100-continue
+type ContinueResult struct {
+	// Received is true if the server responded with 100 Continue before the body was sent
+	Received bool
+	// Delay is the time from sending the request headers to 100 Continue,
+	// or to the final response if there was no 100 Continue
+	Delay time.Duration
+}
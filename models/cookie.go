@@ -0,0 +1,8 @@
+package models
+
+// CookieCheck describes the expected Set-Cookie of the response
+type CookieCheck struct {
+	// Deleted is true when the cookie is expected to expire the stored one,
+	// i.e. to have the expiry in the past or non-positive Max-Age
+	Deleted bool
+}
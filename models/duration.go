@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Duration is set in YAML either as Go duration string, e.g. "1m30s" or "500ms",
+// or as a bare number of seconds, e.g. 3 or 0.5
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseDuration converts the value decoded from YAML to the duration
+func ParseDuration(value interface{}) (time.Duration, error) {
+	var d time.Duration
+	switch v := value.(type) {
+	case int:
+		d = time.Duration(v) * time.Second
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	case string:
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			return 0, fmt.Errorf("invalid duration %q, expected Go duration like \"1m30s\" or number of seconds", v)
+		}
+	default:
+		return 0, fmt.Errorf("invalid duration %v, expected Go duration like \"1m30s\" or number of seconds", value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %v must not be negative", value)
+	}
+	return d, nil
+}
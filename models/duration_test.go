@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDurationShouldAcceptStringsAndSeconds(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want time.Duration
+	}{
+		{yaml: `timeout: 3`, want: 3 * time.Second},
+		{yaml: `timeout: 0.5`, want: 500 * time.Millisecond},
+		{yaml: `timeout: 1m30s`, want: 90 * time.Second},
+		{yaml: `timeout: "250ms"`, want: 250 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			var v struct {
+				Timeout Duration `yaml:"timeout"`
+			}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.yaml), &v))
+			assert.Equal(t, tt.want, time.Duration(v.Timeout))
+		})
+	}
+}
+
+func TestDurationShouldRejectInvalidValues(t *testing.T) {
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{yaml: `timeout: soon`, err: `invalid duration "soon", expected Go duration like "1m30s" or number of seconds`},
+		{yaml: `timeout: [1]`, err: `invalid duration [1], expected Go duration like "1m30s" or number of seconds`},
+		{yaml: `timeout: -1`, err: `duration -1 must not be negative`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			var v struct {
+				Timeout Duration `yaml:"timeout"`
+			}
+			assert.EqualError(t, yaml.Unmarshal([]byte(tt.yaml), &v), tt.err)
+		})
+	}
+}
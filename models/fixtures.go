@@ -0,0 +1,9 @@
+package models
+
+// TableCleanup reports the rows removed from the table by the fixtures cleanup
+type TableCleanup struct {
+	Table   string
+	Deleted int
+	// Inserted is the number of rows of the table in the cleaned fixtures
+	Inserted int
+}
@@ -0,0 +1,12 @@
+package models
+
+// IdempotencyCheck describes sending the request twice with the same idempotency key
+// and comparing the responses
+type IdempotencyCheck struct {
+	// Header is the name of the header carrying the key
+	Header string
+	// Key is generated for every run if empty
+	Key string
+	// CompareHeaders are the response headers to compare besides status and body
+	CompareHeaders []string
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// LoadCheck describes repeating the request to measure the response time
+type LoadCheck struct {
+	// Requests is the number of the repeated requests
+	Requests int
+	// Concurrency is the number of the requests sent at once
+	Concurrency int
+	// P95Under is the limit of the 95th percentile of the response time, not checked if zero
+	P95Under time.Duration
+}
+
+// LoadResult is the response time of the repeated requests
+type LoadResult struct {
+	Requests int
+	// Failed are the requests which failed or responded with other status than the checked request
+	Failed int
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+}
@@ -0,0 +1,12 @@
+package models
+
+// NDJSONCheck describes the expected lines of the newline-delimited JSON response
+type NDJSONCheck struct {
+	// Lines are JSON documents compared with the lines of the response the same way as response body
+	Lines []string
+	// Unordered matches the lines in any order instead of one by one
+	Unordered bool
+	// Count is the expected number of the lines, the number of Lines by default;
+	// if it's greater, Lines are compared with the first lines or, unordered, with any of them
+	Count int
+}
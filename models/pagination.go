@@ -0,0 +1,15 @@
+package models
+
+// DefaultMaxPages limits the pages fetched if the test doesn't set the limit
+const DefaultMaxPages = 10
+
+// Pagination describes how the pages following the response are fetched,
+// the items of all the pages are compared with the expected response as a single array
+type Pagination struct {
+	// Items is JSONPath of the items array of a page
+	Items string
+	// Next is JSONPath of the URL of the next page, the last page has no such value
+	Next string
+	// MaxPages is the number of pages after which the fetching fails
+	MaxPages int
+}
@@ -0,0 +1,10 @@
+package models
+
+// ProblemDetails describes the expected standard fields of RFC 7807
+// application/problem+json response, empty fields are not checked
+type ProblemDetails struct {
+	Type   string
+	Title  string
+	Status int
+	Detail string
+}
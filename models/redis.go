@@ -0,0 +1,14 @@
+package models
+
+// RedisCheck describes the expected state of the Redis key after the request
+type RedisCheck struct {
+	Key string
+	// Exists is false when the key is expected to be absent
+	Exists bool
+	// Value is compared only if set
+	Value *string
+	// TTLMin and TTLMax are bounds of the remaining key time to live in seconds,
+	// zero means there is no bound
+	TTLMin int
+	TTLMax int
+}
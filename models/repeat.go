@@ -0,0 +1,13 @@
+package models
+
+// RepeatCheck describes sending the request twice and checking the status of the second response,
+// e.g. DELETE responding with 404 to the repeated request
+type RepeatCheck struct {
+	Status int
+}
+
+// RepeatResult has the statuses of both responses
+type RepeatResult struct {
+	FirstStatus  int
+	SecondStatus int
+}
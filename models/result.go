@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Result of test execution
 type Result struct {
 	Path                string // TODO: remove
@@ -7,16 +9,86 @@ type Result struct {
 	RequestBody         string
 	ResponseStatusCode  int
 	ResponseStatus      string
+	ResponseStatusText  string
+	ResponseProto       string
 	ResponseContentType string
 	ResponseBody        string
 	ResponseHeaders     map[string][]string
 	DbQuery             string
 	DbResponse          []string
-	Errors              []error
-	Test                TestInterface
+	RedisResponse       []string
+	// FixturesCleanup is reported when the test skips fixtures
+	FixturesCleanup []TableCleanup
+	// FixturesLog is the debug output of loading the fixtures of the test
+	FixturesLog string
+	// Duration is the time of the test execution with its fixtures, mocks and checks
+	Duration time.Duration
+	// ResponseTime is the time of the request till the whole response body is read
+	ResponseTime time.Duration
+	// Load is reported for the test repeating the request
+	Load *LoadResult
+	// Attempts is the number of the requests sent by the test with the retry policy
+	Attempts int
+	// AttemptResults are the outcomes of all the attempts in order, reported if there was more than one
+	AttemptResults []AttemptResult
+	// Connections are reported for the test counting the connections of its requests
+	Connections *ConnectionsResult
+	// ScenarioConnections are reported for the test limiting the connections of the tests of its file
+	ScenarioConnections *ConnectionsResult
+	// Mocks are the strategies of the loaded mocks by service, see mocks.Loader.Strategies
+	Mocks map[string]string
+	// Repeat is reported for the test repeating the request to check the status of the second response
+	Repeat *RepeatResult
+	// Continue is reported for the request sent with Expect: 100-continue
+	Continue *ContinueResult
+	Errors   []error
+	Test     TestInterface
 }
 
+// Statuses of the test result
+const (
+	StatusPassed = "passed"
+	StatusFailed = "failed"
+	// StatusXFail is the status of the failed test expected to fail, it doesn't fail the run
+	StatusXFail = "xfail"
+	// StatusXPass is the status of the passed test expected to fail
+	StatusXPass = "xpass"
+)
+
 // Passed returns true if test passed (false otherwise)
 func (r *Result) Passed() bool {
 	return len(r.Errors) == 0
 }
+
+// Status returns passed or failed, xfail or xpass for the test expected to fail
+func (r *Result) Status() string {
+	expectedToFail := r.Test != nil && r.Test.ExpectedToFail()
+	switch {
+	case r.Passed() && expectedToFail:
+		return StatusXPass
+	case r.Passed():
+		return StatusPassed
+	case expectedToFail:
+		return StatusXFail
+	default:
+		return StatusFailed
+	}
+}
+
+// Failed returns true if the test failed the run, unlike the failed test expected to fail
+func (r *Result) Failed() bool {
+	return r.Status() == StatusFailed
+}
+
+// AllureStatus returns the Allure status of the result: the expected failure is skipped
+// and the unexpected pass is broken to be noticed
+func (r *Result) AllureStatus() string {
+	switch r.Status() {
+	case StatusXFail:
+		return "skipped"
+	case StatusXPass:
+		return "broken"
+	default:
+		return r.Status()
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RetryPolicy describes sending the request of the failed test again
+type RetryPolicy struct {
+	// Attempts is the maximum number of the requests, the first one included
+	Attempts int
+	// Delay is the pause before every next attempt
+	Delay time.Duration
+	// RetryOnStatus limits the retries to the responses with these statuses, any failed response is retried if empty
+	RetryOnStatus []int
+}
+
+// AttemptResult is the outcome of a request of the test sent with the retry policy
+type AttemptResult struct {
+	ResponseStatus string
+	Errors         []error
+}
@@ -1,40 +1,125 @@
 package models
 
+import "time"
+
 // Common Test interface
 type TestInterface interface {
 	ToQuery() string
+	// GetQueryParams returns the parameters added to the query, a value holding
+	// JSON array is sent as the repeated parameter
+	GetQueryParams() map[string][]string
 	GetRequest() string
 	ToJSON() ([]byte, error)
 	GetMethod() string
 	Path() string
 	GetResponses() map[int]string
+	// GetResponseMergeBases, GetResponseNDJSONLines and GetResponseBodyPatterns return the per-status values
+	// the variables are substituted in
+	GetResponseMergeBases() map[int]string
+	GetResponseNDJSONLines() map[int][]string
+	GetResponseBodyPatterns() map[int]string
 	GetResponse(code int) (string, bool)
 	GetResponseHeaders(code int) (map[string]string, bool)
+	GetResponseLinks(code int) (map[string]string, bool)
+	GetResponseCookies(code int) (map[string]CookieCheck, bool)
+	GetResponseProblem(code int) (*ProblemDetails, bool)
+	GetResponseBodyHash(code int) (map[string]string, bool)
+	GetRequiredFields(code int) ([]string, bool)
+	GetResponseKeys(code int) (map[string][]string, bool)
+	GetResponseValidationErrors(code int) (*ValidationErrorsCheck, bool)
+	GetResponseFiles(code int) ([]string, bool)
+	// GetResponseSchema returns the JSON Schema of the response body, inline or the path of its file
+	GetResponseSchema(code int) (string, bool)
+	// GetResponseMergeBase returns the JSON the request body is merged into to get the response body
+	GetResponseMergeBase(code int) (string, bool)
+	GetResponseNDJSON(code int) (*NDJSONCheck, bool)
+	GetResponseBodyMatches(code int) (string, bool)
+	// GetResponseBodyForbidden returns the regular expressions the response body of any status must not contain
+	GetResponseBodyForbidden() []string
+	// BodyComparator returns the name of the registered comparator replacing the default body comparison
+	BodyComparator() string
+	// GetStructureSnapshot returns the path of the snapshot of the response structure for the status
+	GetStructureSnapshot(code int) (string, bool)
+	GetStatusText() string
+	GetProtocol() string
+	// GetStatusLine returns the expected protocol, code and reason phrase, e.g. HTTP/1.1 200 OK
+	GetStatusLine() string
 	GetName() string
+	// GetFileName returns the file the test is defined in, empty if it isn't loaded from a file
+	GetFileName() string
+	// DependsOn lists identifiers of the tests which must be run before this one, if they are run at all
+	DependsOn() []string
+	// Export lists the variables set by the test for the tests of the other files
+	Export() []string
 	Fixtures() []string
+	// InlineFixtures are YAML documents of the fixtures defined in the test, loaded after Fixtures
+	InlineFixtures() []string
+	// SkipFixtures is true when the test runs against the tables with no fixtures data
+	SkipFixtures() bool
+	// ExpectedToFail marks the known-broken test, its failure doesn't fail the run
+	ExpectedToFail() bool
 	ServiceMocks() map[string]interface{}
-	Pause() int
+	DisallowUnusedMocks() bool
+	// DisallowUnexpectedMockRequests is true when requests not matching the declared mocks fail the test
+	DisallowUnexpectedMockRequests() bool
+	Pause() time.Duration
+	// ExpectContinue is true when the request is sent with Expect: 100-continue
+	// and the server must respond with 100 Continue or the final response before the body is sent
+	ExpectContinue() bool
+	// MaxDbQueries is the number of DB queries the service may make during the request, not checked if nil
+	MaxDbQueries() *int
+	// MaxResponseTime is the limit of the response time of the request, not checked if zero
+	MaxResponseTime() time.Duration
+	// CheckContentLength is true when the Content-Length of the response must match its body
+	CheckContentLength() bool
 	BeforeScriptPath() string
-	BeforeScriptTimeout() int
+	BeforeScriptTimeout() time.Duration
 	Cookies() map[string]string
 	Headers() map[string]string
 	DbQueryString() string
 	DbResponseJson() []string
+	RedisChecks() []RedisCheck
+	Idempotency() *IdempotencyCheck
+	Repeat() *RepeatCheck
+	Caching() *CachingCheck
+	Pagination() *Pagination
+	// Meta describes the test for the reports, e.g. the issue and the owner
+	Meta() map[string]string
+	// ParityIgnore are JSON paths not compared with the response of the parity host
+	ParityIgnore() []string
+	// Load repeats the request to measure the response time
+	Load() *LoadCheck
+	// ConnectionReuse counts the connections opened by the requests of the test
+	ConnectionReuse() *ConnectionReuseCheck
+	// GetRetryPolicy sends the request of the failed test again
+	GetRetryPolicy() *RetryPolicy
+	ChecksOrder() *ChecksOrder
 	GetVariables() map[string]string
 	GetVariablesToSet() map[int]map[string]string
+	// Capture returns JSON paths of the response values by the names of the variables to set
+	Capture() map[string]string
 
 	// setters
 	SetQuery(string)
+	SetQueryParams(map[string][]string)
 	SetMethod(string)
 	SetPath(string)
 	SetRequest(string)
 	SetResponses(map[int]string)
+	SetResponseMergeBases(map[int]string)
+	SetResponseNDJSONLines(map[int][]string)
+	SetResponseBodyPatterns(map[int]string)
 	SetHeaders(map[string]string)
+	SetServiceMocks(map[string]interface{})
+	SetDbQueryString(string)
+	SetDbResponseJson([]string)
 
 	// comparison properties
 	NeedsCheckingValues() bool
 	IgnoreArraysOrdering() bool
 	DisallowExtraFields() bool
+	// Wildcard is the expected value matching any scalar, doubled it matches any subtree, disabled if empty
+	Wildcard() string
 
 	// Clone returns copy of current object
 	Clone() TestInterface
@@ -44,4 +129,16 @@ type Summary struct {
 	Success bool
 	Failed  int
 	Total   int
+	// XFailed are the failed tests expected to fail, they aren't counted as Failed
+	XFailed int
+	// XPassed are identifiers of the passed tests expected to fail
+	XPassed []string
+	// Skipped are identifiers of the tests not run due to the tests selection
+	Skipped []string
+	// SkippedTests are the tests of Skipped
+	SkippedTests []TestInterface
+	// ErrorsByCategory counts errors of all the tests by the checks found them
+	ErrorsByCategory map[ErrorCategory]int
+	// MaxFailures is the number of the failed tests tolerated by the run, nil if none are
+	MaxFailures *int
 }
@@ -0,0 +1,13 @@
+package models
+
+// DefaultValidationErrorsPath is where the validation errors are looked for if the test sets no path
+const DefaultValidationErrorsPath = "$.errors"
+
+// ValidationErrorsCheck describes the exact set of the validation errors of the response
+type ValidationErrorsCheck struct {
+	// Path is JSONPath of the errors array
+	Path string
+	// Errors are matched in any order, the fields of an error not listed here are ignored,
+	// the values can be matched with $matchRegexp
+	Errors []map[string]string
+}
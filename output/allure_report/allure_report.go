@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/lamoda/gonkey/models"
 	"github.com/lamoda/gonkey/output"
+	"github.com/lamoda/gonkey/output/allure_report/beans"
 )
 
 type AllureReportOutput struct {
@@ -38,13 +41,32 @@ func NewOutput(suiteName, reportLocation string) *AllureReportOutput {
 func (o *AllureReportOutput) Process(t models.TestInterface, result *models.Result) error {
 	testCase := o.allure.StartCase(t.GetName(), time.Now())
 	testCase.AddLabel("story", result.Path)
+	addMeta(testCase, t.Meta())
+	if result.ResponseTime > 0 {
+		testCase.AddParameter("responseTime", result.ResponseTime.String())
+	}
+	for _, service := range sortedKeys(result.Mocks) {
+		testCase.AddParameter("mock "+service, result.Mocks[service])
+	}
+	if result.Repeat != nil {
+		testCase.AddParameter("repeatStatuses", fmt.Sprintf("%d, %d", result.Repeat.FirstStatus, result.Repeat.SecondStatus))
+	}
+	for i, attempt := range result.AttemptResults {
+		testCase.AddStep(o.attemptStep(i+1, attempt))
+	}
+	if result.FixturesLog != "" {
+		o.allure.AddAttachment(
+			*bytes.NewBufferString("Fixtures"),
+			*bytes.NewBufferString(result.FixturesLog),
+			"txt")
+	}
 	o.allure.AddAttachment(
 		*bytes.NewBufferString("Request"),
 		*bytes.NewBufferString(fmt.Sprintf(`Query: %s \n Body: %s`, result.Query, result.RequestBody)),
 		"txt")
 	o.allure.AddAttachment(
 		*bytes.NewBufferString("Response"),
-		*bytes.NewBufferString(fmt.Sprintf(`Body: %s`, result.ResponseBody)),
+		*bytes.NewBufferString(fmt.Sprintf(`Protocol: %s \n Body: %s`, result.ResponseProto, result.ResponseBody)),
 		"txt")
 	if result.DbQuery != "" {
 		o.allure.AddAttachment(
@@ -56,18 +78,103 @@ func (o *AllureReportOutput) Process(t models.TestInterface, result *models.Resu
 			*bytes.NewBufferString(fmt.Sprintf(`Respone: %s`, result.DbResponse)),
 			"txt")
 	}
-	if !result.Passed() {
+	if len(result.RedisResponse) > 0 {
+		testCase.AddStep(o.redisStep(result))
+	}
+	if len(result.FixturesCleanup) > 0 {
+		var cleanup []string
+		for _, c := range result.FixturesCleanup {
+			cleanup = append(cleanup, fmt.Sprintf("%s: %d rows deleted, %d inserted by the fixtures", c.Table, c.Deleted, c.Inserted))
+		}
+		o.allure.AddAttachment(
+			*bytes.NewBufferString("Fixtures Cleanup"),
+			*bytes.NewBufferString(strings.Join(cleanup, "\n")),
+			"txt")
+	}
+	switch {
+	case !result.Passed():
 		ers := ""
 		for _, e := range result.Errors {
 			ers = ers + e.Error() + "\n"
 		}
-		o.allure.EndCase("failed", errors.New(ers), time.Now())
-	} else {
-		o.allure.EndCase("passed", nil, time.Now())
+		o.allure.EndCase(result.AllureStatus(), errors.New(ers), time.Now())
+	case result.Status() == models.StatusXPass:
+		o.allure.EndCase(result.AllureStatus(), errors.New("expected to fail, but passed"), time.Now())
+	default:
+		o.allure.EndCase(result.AllureStatus(), nil, time.Now())
 	}
 	return nil
 }
 
+// metaLabels are the keys of the test meta reported as Allure labels, the rest are parameters
+var metaLabels = map[string]bool{
+	"issue": true,
+	"owner": true,
+}
+
+func addMeta(testCase *beans.TestCase, meta map[string]string) {
+	for _, key := range sortedKeys(meta) {
+		if metaLabels[key] {
+			testCase.AddLabel(key, meta[key])
+		} else {
+			testCase.AddParameter(key, meta[key])
+		}
+	}
+}
+
+// attemptStep reports the request of the test sent with the retry policy,
+// the errors of the failed attempt are attached
+func (o *AllureReportOutput) attemptStep(number int, attempt models.AttemptResult) *beans.Step {
+	step := beans.NewStep(fmt.Sprintf("Attempt %d: %s", number, attempt.ResponseStatus), time.Now())
+	status := "passed"
+	if len(attempt.Errors) > 0 {
+		status = "failed"
+		var errs []string
+		for _, e := range attempt.Errors {
+			errs = append(errs, e.Error())
+		}
+		step.Attachments = append(step.Attachments, o.attachment("Errors", strings.Join(errs, "\n")))
+	}
+	step.End(status, time.Now())
+	return step
+}
+
+// redisStep reports the check of the Redis keys with their state and the errors of the check attached
+func (o *AllureReportOutput) redisStep(result *models.Result) *beans.Step {
+	step := beans.NewStep("Redis", time.Now())
+	step.Attachments = append(step.Attachments, o.attachment("Redis Response", strings.Join(result.RedisResponse, "\n")))
+
+	var errs []string
+	for _, e := range result.Errors {
+		if e, ok := e.(*models.CheckError); ok && e.GetCategory() == models.ErrorCategoryRedis {
+			errs = append(errs, e.Error())
+		}
+	}
+	status := "passed"
+	if len(errs) > 0 {
+		status = "failed"
+		step.Attachments = append(step.Attachments, o.attachment("Redis Errors", strings.Join(errs, "\n")))
+	}
+	step.End(status, time.Now())
+	return step
+}
+
+func (o *AllureReportOutput) attachment(title, content string) *beans.Attachment {
+	buf := bytes.NewBufferString(content)
+	mime, ext := getBufferInfo(*buf, "txt")
+	name, _ := writeBuffer(o.allure.TargetDir, *buf, ext)
+	return beans.NewAttachment(title, mime, name, buf.Len())
+}
+
 func (o *AllureReportOutput) Finalize() {
 	o.allure.EndSuite(time.Now())
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
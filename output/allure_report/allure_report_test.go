@@ -0,0 +1,110 @@
+package allure_report
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/output/allure_report/beans"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func processResult(t *testing.T, result *models.Result) *beans.TestCase {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := NewOutput("suite", dir)
+	require.NoError(t, o.Process(result.Test, result))
+
+	cases := o.allure.GetCurrentSuite().TestCases.Cases
+	require.Len(t, cases, 1)
+	return cases[0]
+}
+
+func processTest(t *testing.T, meta map[string]string) *beans.TestCase {
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{Name: "list books", MetaVal: meta}}
+	return processResult(t, &models.Result{Test: test, Path: "/books"})
+}
+
+func TestProcessShouldReportMetaAsLabelsAndParameters(t *testing.T) {
+	testCase := processTest(t, map[string]string{
+		"owner":    "catalog-team",
+		"issue":    "BOOK-12",
+		"severity": "critical",
+	})
+
+	assert.Equal(t, []*beans.Label{
+		{Name: "story", Value: "/books"},
+		{Name: "issue", Value: "BOOK-12"},
+		{Name: "owner", Value: "catalog-team"},
+	}, testCase.Labels.Label)
+	assert.Equal(t, []*beans.Parameter{
+		{Kind: "argument", Name: "severity", Value: "critical"},
+	}, testCase.Parameters.Parameter)
+}
+
+func TestProcessShouldReportNoMetaOfTestWithout(t *testing.T) {
+	testCase := processTest(t, nil)
+
+	assert.Equal(t, []*beans.Label{{Name: "story", Value: "/books"}}, testCase.Labels.Label)
+	assert.Empty(t, testCase.Parameters.Parameter)
+}
+
+func TestProcessShouldReportRedisCheckAsStep(t *testing.T) {
+	test := &yaml_file.Test{}
+	testCase := processResult(t, &models.Result{
+		Test:          test,
+		RedisResponse: []string{"book:1: <missing>"},
+		Errors: []error{
+			models.NewCheckError(models.ErrorCategoryStatus, errors.New("status does not match")),
+			models.NewCheckError(models.ErrorCategoryRedis, errors.New("redis key book:1 is missing")),
+		},
+	})
+
+	require.Len(t, testCase.Steps.Steps, 1)
+	step := testCase.Steps.Steps[0]
+	assert.Equal(t, "Redis", step.Name)
+	assert.Equal(t, "failed", step.Status)
+	require.Len(t, step.Attachments, 2)
+	assert.Equal(t, "Redis Response", step.Attachments[0].Title)
+	assert.Equal(t, len("book:1: <missing>"), step.Attachments[0].Size)
+	assert.Equal(t, "Redis Errors", step.Attachments[1].Title)
+	assert.Equal(t, len("redis key book:1 is missing"), step.Attachments[1].Size)
+
+	testCase = processResult(t, &models.Result{Test: test, RedisResponse: []string{"book:1: dune (ttl 1m0s)"}})
+	require.Len(t, testCase.Steps.Steps, 1)
+	assert.Equal(t, "passed", testCase.Steps.Steps[0].Status)
+	assert.Len(t, testCase.Steps.Steps[0].Attachments, 1)
+
+	testCase = processResult(t, &models.Result{Test: test})
+	assert.Empty(t, testCase.Steps.Steps, "the test without Redis checks must have no step")
+}
+
+func TestProcessShouldReportEveryAttemptAsStep(t *testing.T) {
+	test := &yaml_file.Test{}
+	testCase := processResult(t, &models.Result{
+		Test:     test,
+		Attempts: 2,
+		AttemptResults: []models.AttemptResult{
+			{ResponseStatus: "503 Service Unavailable", Errors: []error{errors.New("server responded with status 503")}},
+			{ResponseStatus: "200 OK"},
+		},
+	})
+
+	require.Len(t, testCase.Steps.Steps, 2)
+	failed := testCase.Steps.Steps[0]
+	assert.Equal(t, "Attempt 1: 503 Service Unavailable", failed.Name)
+	assert.Equal(t, "failed", failed.Status)
+	require.Len(t, failed.Attachments, 1)
+	assert.Equal(t, "Errors", failed.Attachments[0].Title)
+	passed := testCase.Steps.Steps[1]
+	assert.Equal(t, "Attempt 2: 200 OK", passed.Name)
+	assert.Equal(t, "passed", passed.Status)
+	assert.Empty(t, passed.Attachments)
+}
@@ -30,6 +30,9 @@ type TestCase struct {
 	Labels struct {
 		Label []*Label `xml:"label"`
 	} `xml:"labels"`
+	Parameters struct {
+		Parameter []*Parameter `xml:"parameter"`
+	} `xml:"parameters"`
 	Attachments struct {
 		Attachment []*Attachment `xml:"attachment"`
 	} `xml:"attachments"`
@@ -45,6 +48,20 @@ type Label struct {
 	Value string `xml:"value,attr"`
 }
 
+type Parameter struct {
+	Kind  string `xml:"kind,attr"`
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func (t *TestCase) AddParameter(name, value string) {
+	t.Parameters.Parameter = append(t.Parameters.Parameter, &Parameter{
+		Kind:  "argument",
+		Name:  name,
+		Value: value,
+	})
+}
+
 func (t *TestCase) SetDescription(desc string) {
 	t.Desc = desc
 }
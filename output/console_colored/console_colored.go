@@ -2,7 +2,12 @@ package console_colored
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/fatih/color"
@@ -10,46 +15,72 @@ import (
 	"github.com/lamoda/gonkey/output"
 )
 
-const dotsPerLine = 80
-
 type ConsoleColoredOutput struct {
 	output.OutputInterface
 
-	verbose bool
-	dots    int
+	w          io.Writer
+	verbose    bool
+	prettyJSON bool
+
+	dotsPerLine   int
+	progressStyle ProgressStyle
+	total         int
+	processed     int
+	passed        int
+	// lineOpen is true while the cursor is left on the line of the progress
+	lineOpen bool
 }
 
-func NewOutput(verbose bool) *ConsoleColoredOutput {
-	return &ConsoleColoredOutput{
-		verbose: verbose,
+func NewOutput(verbose bool, opts ...Option) *ConsoleColoredOutput {
+	o := &ConsoleColoredOutput{
+		w:             os.Stdout,
+		verbose:       verbose,
+		dotsPerLine:   defaultDotsPerLine,
+		progressStyle: ProgressDots,
+	}
+	if width := terminalWidth(); width > 0 && width < o.dotsPerLine {
+		o.dotsPerLine = width
+	}
+	for _, opt := range opts {
+		opt(o)
 	}
+	return o
+}
+
+// SetPrettyJSON enables printing JSON request and response bodies indented
+func (o *ConsoleColoredOutput) SetPrettyJSON(pretty bool) {
+	o.prettyJSON = pretty
 }
 
 func (o *ConsoleColoredOutput) Process(t models.TestInterface, result *models.Result) error {
-	if !result.Passed() || o.verbose {
-		text, err := renderResult(result)
+	o.processed++
+	if result.Status() != models.StatusPassed || o.verbose {
+		text, err := renderResult(result, o.prettyJSON)
 		if err != nil {
 			return err
 		}
-		fmt.Print(text)
+		o.endProgress()
+		fmt.Fprint(o.w, text)
 	} else {
-		fmt.Print(".")
-		o.dots++
-		if o.dots%dotsPerLine == 0 {
-			fmt.Print("\n")
-		}
+		o.progress()
 	}
 	return nil
 }
 
-func renderResult(result *models.Result) (string, error) {
+func renderResult(result *models.Result, prettyJSON bool) (string, error) {
 	text := `
        Name: {{ green .Test.GetName }}
+{{- if .Test.Meta }}
+       Meta:
+{{- range $key, $value := .Test.Meta }}
+      {{ $key }}: {{ $value }}
+{{- end }}
+{{- end }}
 
 Request:
      Method: {{ cyan .Test.GetMethod }}
        Path: {{ cyan .Test.Path }}
-      Query: {{ cyan .Test.ToQuery }}
+      Query: {{ cyan .Query }}
 {{- if .Test.Headers }}
     Headers: 
 {{- range $key, $value := .Test.Headers }}
@@ -63,12 +94,34 @@ Request:
 {{- end }}
 {{- end }}
        Body:
-{{ if .RequestBody }}{{ cyan .RequestBody }}{{ else }}{{ cyan "<no body>" }}{{ end }}
+{{ if .RequestBody }}{{ cyan (body .RequestBody) }}{{ else }}{{ cyan "<no body>" }}{{ end }}
 
 Response:
      Status: {{ cyan .ResponseStatus }}
+   Protocol: {{ cyan .ResponseProto }}
+{{- if .ResponseTime }}
+       Time: {{ cyan .ResponseTime.String }}
+{{- end }}
+{{- if gt .Attempts 1 }}
+   Attempts: {{ .Attempts }}
+{{- end }}
+{{- if .Repeat }}
+     Repeat: {{ cyan (printf "%d" .Repeat.FirstStatus) }} then {{ cyan (printf "%d" .Repeat.SecondStatus) }}
+{{- end }}
+{{- if .Continue }}
+   Continue: {{ if .Continue.Received }}{{ cyan "100 Continue" }} in {{ .Continue.Delay }}{{ else }}{{ yellow "not received" }}{{ end }}
+{{- end }}
+{{- if .Connections }}
+Connections: {{ .Connections.Connections }} new, {{ .Connections.Reused }} reused, {{ .Connections.TLSHandshakes }} TLS handshakes by {{ .Connections.Requests }} requests
+{{- end }}
+{{- if .ScenarioConnections }}
+   Scenario: {{ .ScenarioConnections.Connections }} new, {{ .ScenarioConnections.Reused }} reused, {{ .ScenarioConnections.TLSHandshakes }} TLS handshakes by {{ .ScenarioConnections.Requests }} requests
+{{- end }}
+{{- if .Load }}
+       Load: {{ .Load.Requests }} requests, p50 {{ .Load.P50 }}, p95 {{ .Load.P95 }}, p99 {{ .Load.P99 }}{{ if .Load.Failed }}, {{ yellow (printf "%d failed" .Load.Failed) }}{{ end }}
+{{- end }}
        Body:
-{{ if .ResponseBody }}{{ yellow .ResponseBody }}{{ else }}{{ yellow "<no body>" }}{{ end }}
+{{ if .ResponseBody }}{{ yellow (body .ResponseBody) }}{{ else }}{{ yellow "<no body>" }}{{ end }}
 
 {{ if .DbQuery }}
        Db Request:
@@ -78,26 +131,48 @@ Response:
 {{ yellow $value }}{{ end }}
 {{ end }}
 
+{{ if .RedisResponse }}
+       Redis Response:
+{{ range $value := .RedisResponse }}
+{{ yellow $value }}{{ end }}
+{{ end }}
+
 {{ if .Errors }}
-     Result: {{ danger "ERRORS!" }}
+     Result: {{ if eq .Status "xfail" }}{{ yellow "XFAIL (expected to fail)" }}{{ else }}{{ danger "ERRORS!" }}{{ end }}
 
 Errors:
 {{ range $i, $e := .Errors }}
 {{ inc $i }}) {{ $e.Error }}
 {{ end }}
 {{ else }}
-     Result: {{ success "OK" }}
+     Result: {{ if eq .Status "xpass" }}{{ yellow "XPASS (expected to fail, but passed)" }}{{ else }}{{ success "OK" }}{{ end }}
 {{ end }}
 `
 
 	var buffer bytes.Buffer
-	t := template.Must(template.New("letter").Funcs(templateFuncMap()).Parse(text))
+	funcs := templateFuncMap()
+	funcs["body"] = func(body string) string {
+		if prettyJSON {
+			return indentJSON(body)
+		}
+		return body
+	}
+	t := template.Must(template.New("letter").Funcs(funcs).Parse(text))
 	if err := t.Execute(&buffer, result); err != nil {
 		return "", err
 	}
 	return buffer.String(), nil
 }
 
+// indentJSON returns the body as is if it is not JSON
+func indentJSON(body string) string {
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return buffer.String()
+}
+
 func templateFuncMap() template.FuncMap {
 	return template.FuncMap{
 		"green":   color.GreenString,
@@ -110,5 +185,51 @@ func templateFuncMap() template.FuncMap {
 }
 
 func (o *ConsoleColoredOutput) ShowSummary(summary *models.Summary) {
-	fmt.Printf("\nFailed tests: %d/%d\n", summary.Failed, summary.Total)
+	o.endProgress()
+	fmt.Fprintf(o.w, "\nFailed tests: %d/%d\n", summary.Failed, summary.Total)
+	if summary.MaxFailures != nil {
+		status := "within budget"
+		if summary.Failed > *summary.MaxFailures {
+			status = "budget exceeded"
+		}
+		fmt.Fprintf(o.w, "Failures budget: %d allowed, %s\n", *summary.MaxFailures, status)
+	}
+	if summary.XFailed > 0 {
+		fmt.Fprintf(o.w, "Expected failures: %d\n", summary.XFailed)
+	}
+	if len(summary.XPassed) > 0 {
+		fmt.Fprintf(o.w, "Unexpected passes: %d\n", len(summary.XPassed))
+		for _, id := range summary.XPassed {
+			fmt.Fprintf(o.w, "  %s\n", id)
+		}
+	}
+	if len(summary.Skipped) > 0 {
+		fmt.Fprintf(o.w, "Skipped tests: %d\n", len(summary.Skipped))
+		for _, id := range summary.Skipped {
+			fmt.Fprintf(o.w, "  %s\n", id)
+		}
+	}
+	if len(summary.ErrorsByCategory) > 0 {
+		fmt.Fprintf(o.w, "Errors by category: %s\n", formatErrorsByCategory(summary.ErrorsByCategory))
+	}
+}
+
+// formatErrorsByCategory lists the most frequent categories first, e.g. "body: 12, status: 3"
+func formatErrorsByCategory(counts map[models.ErrorCategory]int) string {
+	categories := make([]models.ErrorCategory, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	items := make([]string, len(categories))
+	for i, category := range categories {
+		items[i] = fmt.Sprintf("%s: %d", category, counts[category])
+	}
+	return strings.Join(items, ", ")
 }
@@ -0,0 +1,36 @@
+package console_colored
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func renderTest(t *testing.T, meta map[string]string) string {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{Name: "list books", MetaVal: meta}}
+	text, err := renderResult(&models.Result{Test: test}, false)
+	require.NoError(t, err)
+	return text
+}
+
+func TestRenderResultShouldShowMeta(t *testing.T) {
+	text := renderTest(t, map[string]string{"owner": "catalog-team", "issue": "BOOK-12"})
+
+	assert.Contains(t, text, "       Name: list books\n       Meta:\n      issue: BOOK-12\n      owner: catalog-team\n\nRequest:")
+}
+
+func TestRenderResultShouldShowNoMetaOfTestWithout(t *testing.T) {
+	text := renderTest(t, nil)
+
+	assert.Contains(t, text, "       Name: list books\n\nRequest:")
+	assert.NotContains(t, text, "Meta:")
+}
@@ -0,0 +1,108 @@
+package console_colored
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
+)
+
+const defaultDotsPerLine = 80
+
+// ProgressStyle is how the passed tests are shown unless the output is verbose
+type ProgressStyle string
+
+const (
+	// ProgressDots prints a dot for every passed test
+	ProgressDots ProgressStyle = "dots"
+	// ProgressCounter rewrites the line with the number of the tests, e.g. [12/340]
+	ProgressCounter ProgressStyle = "counter"
+	// ProgressSpinner rewrites the line with the spinner and the number of the tests
+	ProgressSpinner ProgressStyle = "spinner"
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+type Option func(o *ConsoleColoredOutput)
+
+// WithDotsPerLine sets the number of the dots of a line, 80 or the terminal width if it is less by default
+func WithDotsPerLine(n int) Option {
+	return func(o *ConsoleColoredOutput) {
+		if n > 0 {
+			o.dotsPerLine = n
+		}
+	}
+}
+
+// WithProgressStyle sets the progress style, the dots by default
+func WithProgressStyle(style ProgressStyle) Option {
+	return func(o *ConsoleColoredOutput) {
+		o.progressStyle = style
+	}
+}
+
+// ParseProgressStyle returns the style by name, e.g. from the command line
+func ParseProgressStyle(name string) (ProgressStyle, error) {
+	switch style := ProgressStyle(name); style {
+	case ProgressDots, ProgressCounter, ProgressSpinner:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown progress style %s, expected one of dots, counter, spinner", name)
+	}
+}
+
+// SetTotal sets the number of the tests shown by the counter and the spinner, e.g. [12/340]
+func (o *ConsoleColoredOutput) SetTotal(total int) {
+	o.total = total
+}
+
+// progress shows the passed test, the counter and the spinner rewrite the line of the progress
+func (o *ConsoleColoredOutput) progress() {
+	o.passed++
+	switch o.progressStyle {
+	case ProgressCounter:
+		fmt.Fprintf(o.w, "\r%s", o.counter())
+		o.lineOpen = true
+	case ProgressSpinner:
+		fmt.Fprintf(o.w, "\r%s %s", spinnerFrames[o.passed%len(spinnerFrames)], o.counter())
+		o.lineOpen = true
+	default:
+		fmt.Fprint(o.w, ".")
+		o.lineOpen = o.passed%o.dotsPerLine != 0
+		if !o.lineOpen {
+			fmt.Fprint(o.w, "\n")
+		}
+	}
+}
+
+// endProgress moves the cursor off the line of the progress before the other output
+func (o *ConsoleColoredOutput) endProgress() {
+	if o.lineOpen {
+		fmt.Fprint(o.w, "\n")
+		o.lineOpen = false
+	}
+}
+
+func (o *ConsoleColoredOutput) counter() string {
+	if o.total > 0 {
+		return fmt.Sprintf("[%d/%d]", o.processed, o.total)
+	}
+	return fmt.Sprintf("[%d]", o.processed)
+}
+
+// terminalWidth returns the width of the terminal of stdout,
+// the COLUMNS environment variable if stdout is not a terminal, zero if it is not set
+func terminalWidth() int {
+	fd := os.Stdout.Fd()
+	if isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd) {
+		if width := terminalSize(fd); width > 0 {
+			return width
+		}
+	}
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
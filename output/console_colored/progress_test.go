@@ -0,0 +1,102 @@
+package console_colored
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newTestOutput(opts ...Option) (*ConsoleColoredOutput, *bytes.Buffer) {
+	var buf bytes.Buffer
+	o := NewOutput(false, opts...)
+	o.w = &buf
+	return o, &buf
+}
+
+func process(t *testing.T, o *ConsoleColoredOutput, failed bool) {
+	result := &models.Result{Test: &yaml_file.Test{}}
+	if failed {
+		result.Errors = []error{errors.New("status does not match")}
+	}
+	require.NoError(t, o.Process(result.Test, result))
+}
+
+func TestDotsShouldWrapByDotsPerLine(t *testing.T) {
+	o, buf := newTestOutput(WithDotsPerLine(2))
+
+	for i := 0; i < 5; i++ {
+		process(t, o, false)
+	}
+
+	assert.Equal(t, "..\n..\n.", buf.String())
+}
+
+func TestCounterShouldShowTotal(t *testing.T) {
+	o, buf := newTestOutput(WithProgressStyle(ProgressCounter))
+	o.SetTotal(3)
+
+	process(t, o, false)
+	process(t, o, false)
+
+	assert.Equal(t, "\r[1/3]\r[2/3]", buf.String())
+}
+
+func TestSpinnerShouldShowCounter(t *testing.T) {
+	o, buf := newTestOutput(WithProgressStyle(ProgressSpinner))
+
+	process(t, o, false)
+	process(t, o, false)
+
+	assert.Equal(t, "\r/ [1]\r- [2]", buf.String())
+}
+
+func TestProgressShouldEndLineBeforeFailure(t *testing.T) {
+	o, buf := newTestOutput(WithProgressStyle(ProgressCounter))
+	o.SetTotal(2)
+
+	process(t, o, false)
+	process(t, o, true)
+
+	assert.True(t, strings.HasPrefix(buf.String(), "\r[1/2]\n\n       Name:"), buf.String())
+}
+
+func TestProgressShouldEndLineBeforeSummary(t *testing.T) {
+	o, buf := newTestOutput(WithDotsPerLine(1))
+
+	process(t, o, false)
+	o.ShowSummary(&models.Summary{Total: 1})
+	assert.Equal(t, ".\n\nFailed tests: 0/1\n", buf.String(), "the full line must not be ended twice")
+
+	o, buf = newTestOutput(WithProgressStyle(ProgressSpinner))
+	process(t, o, false)
+	o.ShowSummary(&models.Summary{Total: 1})
+	assert.Equal(t, "\r/ [1]\n\nFailed tests: 0/1\n", buf.String())
+}
+
+func TestTerminalWidthShouldFallBackToColumns(t *testing.T) {
+	columns, set := os.LookupEnv("COLUMNS")
+	defer func() {
+		if set {
+			_ = os.Setenv("COLUMNS", columns)
+		} else {
+			_ = os.Unsetenv("COLUMNS")
+		}
+	}()
+
+	// stdout of the tests is not a terminal
+	require.NoError(t, os.Setenv("COLUMNS", "40"))
+	assert.Equal(t, 40, terminalWidth())
+	assert.Equal(t, 40, NewOutput(false).dotsPerLine)
+
+	require.NoError(t, os.Unsetenv("COLUMNS"))
+	assert.Equal(t, 0, terminalWidth())
+	assert.Equal(t, defaultDotsPerLine, NewOutput(false).dotsPerLine)
+}
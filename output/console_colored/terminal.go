@@ -0,0 +1,16 @@
+// +build !windows
+
+package console_colored
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// terminalSize returns the number of the columns of the terminal, zero if unknown
+func terminalSize(fd uintptr) int {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
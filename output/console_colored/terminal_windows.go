@@ -0,0 +1,14 @@
+package console_colored
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// terminalSize returns the number of the columns of the console, zero if unknown
+func terminalSize(fd uintptr) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right - info.Window.Left + 1)
+}
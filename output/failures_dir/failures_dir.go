@@ -0,0 +1,167 @@
+package failures_dir
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kylelemons/godebug/diff"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/output"
+)
+
+var (
+	colorRx    = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	fileNameRx = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	failureRx  = regexp.MustCompile(`^\d{3,}-.*\.diff$`)
+)
+
+// the outputs of the process share the numbering of the directory,
+// e.g. the runs of several tests of the package writing to the same directory
+var (
+	dirsMutex sync.Mutex
+	dirs      = make(map[string]*int)
+)
+
+type FailuresDirOutput struct {
+	output.OutputInterface
+
+	dir    string
+	failed *int
+}
+
+// NewOutput writes every failed test to a file of the directory: the request,
+// the errors of the checks and the diff of the expected and actual response bodies.
+// The failures of the previous runs are removed from the directory by the first output of the process
+func NewOutput(dir string) (*FailuresDirOutput, error) {
+	key, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dirsMutex.Lock()
+	defer dirsMutex.Unlock()
+	failed, ok := dirs[key]
+	if !ok {
+		if err := removeFailures(dir); err != nil {
+			return nil, err
+		}
+		failed = new(int)
+		dirs[key] = failed
+	}
+	return &FailuresDirOutput{
+		dir:    dir,
+		failed: failed,
+	}, nil
+}
+
+// removeFailures removes the files written by the outputs, the other files of the directory are kept
+func removeFailures(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || !failureRx.MatchString(file.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *FailuresDirOutput) Process(t models.TestInterface, result *models.Result) error {
+	if !result.Failed() {
+		return nil
+	}
+	if err := os.MkdirAll(o.dir, 0777); err != nil {
+		return err
+	}
+	dirsMutex.Lock()
+	*o.failed++
+	name := fmt.Sprintf("%03d-%s.diff", *o.failed, fileName(testName(t)))
+	dirsMutex.Unlock()
+	return ioutil.WriteFile(filepath.Join(o.dir, name), []byte(renderFailure(t, result)), 0644)
+}
+
+func renderFailure(t models.TestInterface, result *models.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Name: %s\n", testName(t))
+	if t.GetFileName() != "" {
+		fmt.Fprintf(&b, "File: %s\n", t.GetFileName())
+	}
+
+	fmt.Fprintf(&b, "\nRequest:\n%s %s", strings.ToUpper(t.GetMethod()), t.Path())
+	if result.Query != "" {
+		fmt.Fprintf(&b, "?%s", result.Query)
+	}
+	b.WriteString("\n")
+	for _, key := range sortedKeys(t.Headers()) {
+		fmt.Fprintf(&b, "%s: %s\n", key, t.Headers()[key])
+	}
+	if result.RequestBody != "" {
+		fmt.Fprintf(&b, "\n%s\n", indentJSON(result.RequestBody))
+	}
+
+	fmt.Fprintf(&b, "\nResponse:\n%s\n", result.ResponseStatus)
+
+	b.WriteString("\nErrors:\n")
+	for i, err := range result.Errors {
+		fmt.Fprintf(&b, "%d) %s\n", i+1, colorRx.ReplaceAllString(err.Error(), ""))
+	}
+
+	// the bodies are indented alike so that only the differing lines are marked
+	if expected, ok := t.GetResponse(result.ResponseStatusCode); ok {
+		fmt.Fprintf(&b, "\nBody diff (- expected, + actual):\n%s\n",
+			diff.Diff(indentJSON(expected), indentJSON(result.ResponseBody)))
+	} else {
+		fmt.Fprintf(&b, "\nBody:\n%s\n", indentJSON(result.ResponseBody))
+	}
+	return b.String()
+}
+
+// indentJSON returns the body as is if it is not JSON
+func indentJSON(body string) string {
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return buffer.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func testName(t models.TestInterface) string {
+	if t.GetName() != "" {
+		return t.GetName()
+	}
+	return strings.ToUpper(t.GetMethod()) + " " + t.Path()
+}
+
+func fileName(name string) string {
+	name = strings.Trim(fileNameRx.ReplaceAllString(name, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return name
+}
@@ -0,0 +1,122 @@
+package failures_dir
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newTest(name string) *yaml_file.Test {
+	return &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			Name:       name,
+			Method:     "post",
+			RequestURL: "/orders",
+			HeadersVal: map[string]string{"X-Request-Id": "1"},
+		},
+		Responses: map[int]string{201: `{"id": 1, "status": "new"}`},
+	}
+}
+
+func failedResult() *models.Result {
+	return &models.Result{
+		Query:              "draft=true",
+		RequestBody:        `{"sku": "a"}`,
+		ResponseStatus:     "201 Created",
+		ResponseStatusCode: 201,
+		ResponseBody:       `{"id": 1, "status": "paid"}`,
+		Errors:             []error{errors.New("path $.status: values do not match:\n     expected: \x1b[32mnew\x1b[0m")},
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	return dir
+}
+
+func fileNames(t *testing.T, dir string) []string {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func TestOutputShouldWriteFailedTests(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	o, err := NewOutput(dir)
+	require.NoError(t, err)
+	require.NoError(t, o.Process(newTest("create order"), &models.Result{}))
+	require.NoError(t, o.Process(newTest("create order"), failedResult()))
+
+	assert.Equal(t, []string{"001-create_order.diff"}, fileNames(t, dir))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "001-create_order.diff"))
+	require.NoError(t, err)
+	assert.Equal(t, `Name: create order
+
+Request:
+POST /orders?draft=true
+X-Request-Id: 1
+
+{
+  "sku": "a"
+}
+
+Response:
+201 Created
+
+Errors:
+1) path $.status: values do not match:
+     expected: new
+
+Body diff (- expected, + actual):
+ {
+   "id": 1,
+-  "status": "new"
++  "status": "paid"
+ }
+`, string(data))
+}
+
+func TestOutputShouldRemoveFailuresOfPreviousRun(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"001-stale.diff", "012-stale.diff", "notes.txt"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	o, err := NewOutput(dir)
+	require.NoError(t, err)
+	require.NoError(t, o.Process(newTest("create order"), failedResult()))
+
+	assert.Equal(t, []string{"001-create_order.diff", "notes.txt"}, fileNames(t, dir))
+}
+
+func TestOutputsShouldShareNumberingOfDirectory(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	first, err := NewOutput(dir)
+	require.NoError(t, err)
+	require.NoError(t, first.Process(newTest("create order"), failedResult()))
+
+	// e.g. another test of the package running gonkey with the same directory
+	second, err := NewOutput(dir)
+	require.NoError(t, err)
+	require.NoError(t, second.Process(newTest("create order"), failedResult()))
+
+	assert.Equal(t, []string{"001-create_order.diff", "002-create_order.diff"}, fileNames(t, dir))
+}
@@ -0,0 +1,186 @@
+package junit_xml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/output"
+)
+
+var colorRx = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+type JUnitXMLOutput struct {
+	output.OutputInterface
+
+	reportPath string
+	suites     []*testSuite
+	suitesByID map[string]*testSuite
+}
+
+// NewOutput collects the results of the tests into a JUnit XML report written to the path by Finalize,
+// the tests of every file make a test suite
+func NewOutput(reportPath string) *JUnitXMLOutput {
+	return &JUnitXMLOutput{
+		reportPath: reportPath,
+		suitesByID: make(map[string]*testSuite),
+	}
+}
+
+type testSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []*testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []*testCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type testCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Time      string    `xml:"time,attr"`
+	Failure   *failure  `xml:"failure,omitempty"`
+	Skipped   *struct{} `xml:"skipped,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func (o *JUnitXMLOutput) Process(t models.TestInterface, result *models.Result) error {
+	suite := o.suite(t.GetFileName())
+	c := &testCase{
+		Name:      testName(t),
+		ClassName: suite.Name,
+		Time:      seconds(result.Duration),
+	}
+	if result.Status() == models.StatusXFail {
+		// the expected failure is reported as skipped so that it doesn't fail the build
+		c.Skipped = &struct{}{}
+		suite.Skipped++
+	} else if !result.Passed() {
+		var errs []string
+		for _, e := range result.Errors {
+			errs = append(errs, sanitize(e.Error()))
+		}
+		c.Failure = &failure{
+			Message: fmt.Sprintf("%d error(s): %s", len(errs), firstLine(errs[0])),
+			Text:    strings.Join(errs, "\n"),
+		}
+		suite.Failures++
+	}
+	suite.Cases = append(suite.Cases, c)
+	suite.Tests++
+	suite.duration += result.Duration
+	return nil
+}
+
+// ShowSummary adds the tests skipped by the tests selection to the suites of their files
+func (o *JUnitXMLOutput) ShowSummary(summary *models.Summary) {
+	for _, t := range summary.SkippedTests {
+		suite := o.suite(t.GetFileName())
+		suite.Cases = append(suite.Cases, &testCase{
+			Name:      testName(t),
+			ClassName: suite.Name,
+			Time:      seconds(0),
+			Skipped:   &struct{}{},
+		})
+		suite.Tests++
+		suite.Skipped++
+	}
+}
+
+// Finalize writes the report
+func (o *JUnitXMLOutput) Finalize() error {
+	report := testSuites{Suites: o.suites}
+	var duration time.Duration
+	for _, suite := range o.suites {
+		suite.Time = seconds(suite.duration)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		duration += suite.duration
+	}
+	report.Time = seconds(duration)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(o.reportPath); dir != "" {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(o.reportPath, append([]byte(xml.Header), append(data, '\n')...), 0644)
+}
+
+func (o *JUnitXMLOutput) suite(name string) *testSuite {
+	if name == "" {
+		name = "gonkey"
+	}
+	suite, ok := o.suitesByID[name]
+	if !ok {
+		suite = &testSuite{Name: name}
+		o.suitesByID[name] = suite
+		o.suites = append(o.suites, suite)
+	}
+	return suite
+}
+
+func testName(t models.TestInterface) string {
+	if t.GetName() != "" {
+		return t.GetName()
+	}
+	return strings.ToUpper(t.GetMethod()) + " " + t.Path()
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// sanitize removes the colors of the errors and the characters not allowed in XML,
+// which make the report unparseable even in CDATA
+func sanitize(s string) string {
+	s = colorRx.ReplaceAllString(s, "")
+	return strings.Map(func(r rune) rune {
+		if isXMLChar(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// isXMLChar reports whether the character is allowed by XML 1.0
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
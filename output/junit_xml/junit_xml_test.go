@@ -0,0 +1,98 @@
+package junit_xml
+
+import (
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newTest(name, file string, expectedToFail bool) *yaml_file.Test {
+	return &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			Name:              name,
+			ExpectedToFailVal: expectedToFail,
+		},
+		FileName: file,
+	}
+}
+
+func writeReport(t *testing.T, o *JUnitXMLOutput) testSuites {
+	require.NoError(t, o.Finalize())
+	data, err := ioutil.ReadFile(o.reportPath)
+	require.NoError(t, err)
+
+	var report testSuites
+	require.NoError(t, xml.Unmarshal(data, &report), "report must be valid XML:\n%s", data)
+	return report
+}
+
+func TestReportShouldBeValidXMLWithColoredErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := NewOutput(filepath.Join(dir, "report.xml"))
+
+	err = errors.New("path $.id: values do not match:\n     expected: \x1b[32m1\x1b[0m\n       actual: \x1b[31m2\x1b[0m\x00 ]]> <tag>")
+	require.NoError(t, o.Process(newTest("get", "books.yaml", false), &models.Result{Errors: []error{err}}))
+
+	report := writeReport(t, o)
+	require.Len(t, report.Suites, 1)
+	require.Len(t, report.Suites[0].Cases, 1)
+	f := report.Suites[0].Cases[0].Failure
+	require.NotNil(t, f)
+	assert.Equal(t, "1 error(s): path $.id: values do not match:", f.Message)
+	assert.Equal(t, "path $.id: values do not match:\n     expected: 1\n       actual: 2 ]]> <tag>", f.Text)
+	assert.Equal(t, 1, report.Failures)
+}
+
+func TestReportShouldHaveSkippedTestsInSuitesOfTheirFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := NewOutput(filepath.Join(dir, "report.xml"))
+
+	require.NoError(t, o.Process(newTest("list", "books.yaml", false), &models.Result{}))
+	o.ShowSummary(&models.Summary{
+		Skipped:      []string{"get", "health"},
+		SkippedTests: []models.TestInterface{newTest("get", "books.yaml", false), newTest("health", "health.yaml", false)},
+	})
+
+	report := writeReport(t, o)
+	require.Len(t, report.Suites, 2)
+	assert.Equal(t, "books.yaml", report.Suites[0].Name)
+	assert.Equal(t, 2, report.Suites[0].Tests)
+	assert.Equal(t, 1, report.Suites[0].Skipped)
+	assert.NotNil(t, report.Suites[0].Cases[1].Skipped)
+	assert.Equal(t, "health.yaml", report.Suites[1].Name)
+	assert.Equal(t, "health", report.Suites[1].Cases[0].Name)
+	assert.Equal(t, 2, report.Skipped)
+}
+
+func TestReportShouldHaveExpectedFailureSkipped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := NewOutput(filepath.Join(dir, "report.xml"))
+
+	test := newTest("known bug", "books.yaml", true)
+	require.NoError(t, o.Process(test, &models.Result{Test: test, Errors: []error{errors.New("wrong")}}))
+
+	report := writeReport(t, o)
+	c := report.Suites[0].Cases[0]
+	assert.NotNil(t, c.Skipped)
+	assert.Nil(t, c.Failure)
+	assert.Equal(t, 0, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+}
@@ -7,3 +7,9 @@ import (
 type OutputInterface interface {
 	Process(models.TestInterface, *models.Result) error
 }
+
+// TotalInterface is implemented by the outputs showing the progress of the run,
+// the number of the tests to run is set before the first test
+type TotalInterface interface {
+	SetTotal(total int)
+}
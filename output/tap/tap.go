@@ -0,0 +1,145 @@
+package tap
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/output"
+)
+
+var colorRx = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+type TAPOutput struct {
+	output.OutputInterface
+
+	w       io.Writer
+	verbose bool
+	started bool
+	planned bool
+	n       int
+}
+
+// NewOutput writes the results of the tests to w in the Test Anything Protocol as they come,
+// the plan is written by ShowSummary unless SetPlan is called before the tests.
+// The verbose output has the request and the response in the diagnostics of every test
+func NewOutput(w io.Writer, verbose bool) *TAPOutput {
+	return &TAPOutput{
+		w:       w,
+		verbose: verbose,
+	}
+}
+
+// SetPlan writes the plan up front if the number of the tests is known
+func (o *TAPOutput) SetPlan(total int) {
+	o.start()
+	fmt.Fprintf(o.w, "1..%d\n", total)
+	o.planned = true
+}
+
+func (o *TAPOutput) Process(t models.TestInterface, result *models.Result) error {
+	o.start()
+	o.n++
+
+	status := "ok"
+	if !result.Passed() {
+		status = "not ok"
+	}
+	line := fmt.Sprintf("%s %d - %s", status, o.n, escape(testName(t)))
+	// TODO is the TAP directive of the tests expected to fail, their failures don't count
+	if t.ExpectedToFail() {
+		line += " # TODO expected to fail"
+	}
+	fmt.Fprintln(o.w, line)
+
+	if result.Passed() && !o.verbose {
+		return nil
+	}
+	return o.writeDiagnostics(t, result)
+}
+
+// ShowSummary reports the tests skipped by the tests selection and writes the plan
+func (o *TAPOutput) ShowSummary(summary *models.Summary) {
+	o.start()
+	for _, id := range summary.Skipped {
+		o.n++
+		fmt.Fprintf(o.w, "ok %d - %s # SKIP not selected\n", o.n, escape(id))
+	}
+	if !o.planned {
+		fmt.Fprintf(o.w, "1..%d\n", o.n)
+		o.planned = true
+	}
+	fmt.Fprintf(o.w, "# failed %d/%d\n", summary.Failed, summary.Total)
+}
+
+func (o *TAPOutput) start() {
+	if !o.started {
+		fmt.Fprintln(o.w, "TAP version 13")
+		o.started = true
+	}
+}
+
+// writeDiagnostics writes the YAML block indented by two spaces after the test line
+func (o *TAPOutput) writeDiagnostics(t models.TestInterface, result *models.Result) error {
+	var diagnostics yaml.MapSlice
+	if !result.Passed() {
+		errs := make([]string, len(result.Errors))
+		for i, err := range result.Errors {
+			errs[i] = colorRx.ReplaceAllString(err.Error(), "")
+		}
+		diagnostics = append(diagnostics,
+			yaml.MapItem{Key: "message", Value: fmt.Sprintf("%d error(s)", len(errs))},
+			yaml.MapItem{Key: "severity", Value: "fail"},
+			yaml.MapItem{Key: "errors", Value: errs},
+		)
+	}
+	if o.verbose {
+		request := yaml.MapSlice{
+			{Key: "method", Value: strings.ToUpper(t.GetMethod())},
+			{Key: "path", Value: t.Path()},
+		}
+		if result.Query != "" {
+			request = append(request, yaml.MapItem{Key: "query", Value: result.Query})
+		}
+		if result.RequestBody != "" {
+			request = append(request, yaml.MapItem{Key: "body", Value: result.RequestBody})
+		}
+		response := yaml.MapSlice{
+			{Key: "status", Value: result.ResponseStatus},
+		}
+		if result.ResponseBody != "" {
+			response = append(response, yaml.MapItem{Key: "body", Value: result.ResponseBody})
+		}
+		diagnostics = append(diagnostics,
+			yaml.MapItem{Key: "request", Value: request},
+			yaml.MapItem{Key: "response", Value: response},
+		)
+	}
+
+	data, err := yaml.Marshal(diagnostics)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.w, "  ---")
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		fmt.Fprintf(o.w, "  %s\n", line)
+	}
+	fmt.Fprintln(o.w, "  ...")
+	return nil
+}
+
+func testName(t models.TestInterface) string {
+	if t.GetName() != "" {
+		return t.GetName()
+	}
+	return strings.ToUpper(t.GetMethod()) + " " + t.Path()
+}
+
+// escape keeps the name from being read as a directive
+func escape(name string) string {
+	return strings.ReplaceAll(name, "#", `\#`)
+}
@@ -0,0 +1,135 @@
+package tap
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newTest(name string, expectedToFail bool) *yaml_file.Test {
+	return &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			Name:              name,
+			Method:            "post",
+			RequestURL:        "/books",
+			ExpectedToFailVal: expectedToFail,
+		},
+	}
+}
+
+func TestOutputShouldWritePlanAfterTests(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, false)
+
+	require.NoError(t, o.Process(newTest("create book", false), &models.Result{}))
+	require.NoError(t, o.Process(newTest("", false), &models.Result{}))
+	o.ShowSummary(&models.Summary{Total: 2})
+
+	assert.Equal(t, `TAP version 13
+ok 1 - create book
+ok 2 - POST /books
+1..2
+# failed 0/2
+`, buf.String())
+}
+
+func TestOutputShouldWritePlanBeforeTests(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, false)
+
+	o.SetPlan(1)
+	require.NoError(t, o.Process(newTest("create book", false), &models.Result{}))
+	o.ShowSummary(&models.Summary{Total: 1})
+
+	assert.Equal(t, `TAP version 13
+1..1
+ok 1 - create book
+# failed 0/1
+`, buf.String())
+}
+
+func TestOutputShouldMarkExpectedToFailAndSkippedTests(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, false)
+
+	require.NoError(t, o.Process(newTest("passes", true), &models.Result{}))
+	o.ShowSummary(&models.Summary{Total: 1, Skipped: []string{"list books"}})
+
+	assert.Equal(t, `TAP version 13
+ok 1 - passes # TODO expected to fail
+ok 2 - list books # SKIP not selected
+1..2
+# failed 0/1
+`, buf.String())
+}
+
+func TestOutputShouldWriteErrorsAsYAMLDiagnostics(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, false)
+
+	result := &models.Result{Errors: []error{
+		errors.New("status does not match:\n     expected: \x1b[32m201\x1b[0m\n       actual: \x1b[31m500\x1b[0m"),
+	}}
+	require.NoError(t, o.Process(newTest("create book", true), result))
+
+	assert.Equal(t, `TAP version 13
+not ok 1 - create book # TODO expected to fail
+  ---
+  message: 1 error(s)
+  severity: fail
+  errors:
+  - |-
+    status does not match:
+         expected: 201
+           actual: 500
+  ...
+`, buf.String())
+}
+
+func TestOutputShouldWriteRequestAndResponseInVerboseMode(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, true)
+
+	result := &models.Result{
+		Query:          "draft=true",
+		RequestBody:    `{"title": "Dune"}`,
+		ResponseStatus: "201 Created",
+		ResponseBody:   `{"id": 1}`,
+	}
+	require.NoError(t, o.Process(newTest("create book", false), result))
+
+	assert.Equal(t, `TAP version 13
+ok 1 - create book
+  ---
+  request:
+    method: POST
+    path: /books
+    query: draft=true
+    body: '{"title": "Dune"}'
+  response:
+    status: 201 Created
+    body: '{"id": 1}'
+  ...
+`, buf.String())
+}
+
+func TestOutputShouldEscapeHashInNames(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewOutput(&buf, false)
+
+	require.NoError(t, o.Process(newTest("book #1 # TODO", false), &models.Result{}))
+	o.ShowSummary(&models.Summary{Total: 1, Skipped: []string{"book #2"}})
+
+	assert.Equal(t, `TAP version 13
+ok 1 - book \#1 \# TODO
+ok 2 - book \#2 # SKIP not selected
+1..2
+# failed 0/1
+`, buf.String())
+}
@@ -21,12 +21,17 @@ func NewOutput(t *testing.T) *TestingOutput {
 }
 
 func (o *TestingOutput) Process(t models.TestInterface, result *models.Result) error {
-	if !result.Passed() {
+	switch result.Status() {
+	case models.StatusFailed:
 		text, err := renderResult(result)
 		if err != nil {
 			return err
 		}
 		o.testing.Error(text)
+	case models.StatusXFail:
+		o.testing.Logf("%s failed as expected with %d error(s)", t.GetName(), len(result.Errors))
+	case models.StatusXPass:
+		o.testing.Logf("%s is expected to fail, but passed", t.GetName())
 	}
 	return nil
 }
@@ -38,7 +43,7 @@ func renderResult(result *models.Result) (string, error) {
 Request:
      Method: {{ .Test.GetMethod }}
        Path: {{ .Test.Path }}
-      Query: {{ .Test.ToQuery }}
+      Query: {{ .Query }}
 {{- if .Test.Headers }}
     Headers: 
 {{- range $key, $value := .Test.Headers }}
@@ -56,6 +61,7 @@ Request:
 
 Response:
      Status: {{ .ResponseStatus }}
+   Protocol: {{ .ResponseProto }}
        Body:
 {{ if .ResponseBody }}{{ .ResponseBody }}{{ else }}{{ "<no body>" }}{{ end }}
 
@@ -67,6 +73,12 @@ Response:
 {{ $value }}{{ end }}
 {{ end }}
 
+{{ if .RedisResponse }}
+       Redis Response:
+{{ range $value := .RedisResponse }}
+{{ $value }}{{ end }}
+{{ end }}
+
 {{ if .Errors }}
      Result: {{ "ERRORS!" }}
 
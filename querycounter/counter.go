@@ -0,0 +1,57 @@
+// Package querycounter counts the queries the service under test sends to its database,
+// the database of the service is opened with the driver wrapped by Counter.Wrap
+package querycounter
+
+import (
+	"database/sql/driver"
+	"sync"
+)
+
+// Counter remembers the statements executed through the wrapped drivers since the last Reset
+type Counter struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func New() *Counter {
+	return &Counter{}
+}
+
+// Reset forgets the counted queries
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	c.queries = nil
+	c.mu.Unlock()
+}
+
+// Queries returns the statements counted since the last Reset in the order of execution
+func (c *Counter) Queries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+func (c *Counter) add(query string) {
+	c.mu.Lock()
+	c.queries = append(c.queries, query)
+	c.mu.Unlock()
+}
+
+// Wrap returns the driver counting the queries of the connections opened by d,
+// it's registered with sql.Register and used by the service instead of d
+func (c *Counter) Wrap(d driver.Driver) driver.Driver {
+	return &countingDriver{Driver: d, counter: c}
+}
+
+type countingDriver struct {
+	driver.Driver
+	counter *Counter
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, counter: d.counter}, nil
+}
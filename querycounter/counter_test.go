@@ -0,0 +1,47 @@
+package querycounter
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestCounterShouldCountQueriesOfWrappedDriver(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("querycounter")
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	counter := New()
+	sql.Register("querycounter-sqlmock", counter.Wrap(mockDB.Driver()))
+	db, err := sql.Open("querycounter-sqlmock", "querycounter")
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("UPDATE orders").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT status FROM orders").
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("new"))
+
+	var id int
+	require.NoError(t, db.QueryRow("SELECT id FROM orders").Scan(&id))
+	_, err = db.Exec("UPDATE orders SET status = 'paid' WHERE id = $1", id)
+	require.NoError(t, err)
+	stmt, err := db.Prepare("SELECT status FROM orders WHERE id = $1")
+	require.NoError(t, err)
+	var status string
+	require.NoError(t, stmt.QueryRow(id).Scan(&status))
+	require.NoError(t, stmt.Close())
+
+	assert.Equal(t, []string{
+		"SELECT id FROM orders",
+		"UPDATE orders SET status = 'paid' WHERE id = $1",
+		"SELECT status FROM orders WHERE id = $1",
+	}, counter.Queries())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	counter.Reset()
+	assert.Empty(t, counter.Queries())
+}
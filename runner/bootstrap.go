@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// runBootstrap executes the bootstrap tests once before the suite,
+// the variables they set are available to all the tests of the run
+func (r *Runner) runBootstrap(client *http.Client) error {
+	if r.config.Bootstrap == nil {
+		return nil
+	}
+
+	tests, err := r.config.Bootstrap.Load()
+	if err != nil {
+		return fmt.Errorf("unable to load bootstrap tests: %s", err.Error())
+	}
+
+	for v := range tests {
+		result, err := r.executeTest(v, client)
+		if err != nil {
+			return fmt.Errorf("bootstrap test %s failed: %s", testID(v), err.Error())
+		}
+		if len(result.Errors) > 0 {
+			messages := make([]string, len(result.Errors))
+			for i, e := range result.Errors {
+				messages[i] = e.Error()
+			}
+			return fmt.Errorf("bootstrap test %s failed, the tests are not run:\n%s",
+				testID(v), strings.Join(messages, "\n"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func testAuthServer() (*httptest.Server, *int) {
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/login" {
+			body, _ := ioutil.ReadAll(r.Body)
+			if !strings.Contains(string(body), "gonkey") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			logins++
+			_, _ = w.Write([]byte(`{"token": "secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/profile" {
+			_, _ = w.Write([]byte(`{"user": "gonkey"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	return srv, &logins
+}
+
+func newBootstrapRunner(srv *httptest.Server, bootstrap string) (*Runner, *resultsCollector) {
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+			Bootstrap: yaml_file.NewLoader(filepath.Join("testdata", "bootstrap", bootstrap)),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "bootstrap", "tests")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+	return r, collector
+}
+
+func TestBootstrapVariablesShouldBeAvailableToAllTests(t *testing.T) {
+	srv, logins := testAuthServer()
+	defer srv.Close()
+
+	r, collector := newBootstrapRunner(srv, "login")
+	summary, err := r.Run()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *logins)
+	assert.True(t, summary.Success)
+	assert.Equal(t, 2, summary.Total, "bootstrap tests must not be counted")
+	require.Len(t, collector.results, 2)
+	for _, result := range collector.results {
+		assert.Empty(t, result.Errors)
+	}
+}
+
+func TestBootstrapFailureShouldAbortRun(t *testing.T) {
+	srv, _ := testAuthServer()
+	defer srv.Close()
+
+	r, collector := newBootstrapRunner(srv, "failed-login")
+	_, err := r.Run()
+
+	assert.EqualError(t, err, "bootstrap test login failed, the tests are not run:\nserver responded with status 403")
+	assert.Empty(t, collector.results)
+}
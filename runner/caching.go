@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+// checkCaching reports the missing caching headers and, if requested,
+// makes the conditional request expecting 304 Not Modified
+// and repeats the request expecting the response from the cache
+func (r *Runner) checkCaching(v models.TestInterface, client *http.Client, check *models.CachingCheck,
+	first *http.Response) ([]error, error) {
+
+	var errs []error
+	for _, name := range check.Headers {
+		if _, ok := first.Header[http.CanonicalHeaderKey(name)]; !ok {
+			errs = append(errs, fmt.Errorf("response caching header %s is missing", name))
+		}
+	}
+
+	if check.NotModified {
+		notModifiedErrs, err := r.checkNotModified(v, client, first)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, notModifiedErrs...)
+	}
+
+	if check.Hit != nil {
+		hitErrs, err := r.checkCacheHit(v, client, check.Hit, first)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, hitErrs...)
+	}
+	return errs, nil
+}
+
+// checkNotModified makes the conditional request with the ETag of the first response
+func (r *Runner) checkNotModified(v models.TestInterface, client *http.Client, first *http.Response) ([]error, error) {
+	etag := first.Header.Get("ETag")
+	if etag == "" {
+		return []error{fmt.Errorf("response has no ETag to make the conditional request")}, nil
+	}
+
+	req, err := newRequest(r.config, v)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("If-None-Match", etag)
+
+	resp, err := repeatRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusNotModified {
+		return []error{fmt.Errorf(
+			"conditional request with If-None-Match %s: expected status %d, actual %d",
+			etag,
+			http.StatusNotModified,
+			resp.StatusCode,
+		)}, nil
+	}
+	return nil, nil
+}
+
+// checkCacheHit repeats the request, the first one warms the cache
+// and the repeated one must be marked as served from the cache
+func (r *Runner) checkCacheHit(v models.TestInterface, client *http.Client, check *models.CacheHitCheck,
+	first *http.Response) ([]error, error) {
+
+	req, err := newRequest(r.config, v)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := repeatRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	values := resp.Header.Values(check.Header)
+	for _, value := range values {
+		if len(compare.Compare(check.Value, value, compare.CompareParams{})) == 0 {
+			return nil, nil
+		}
+	}
+	return []error{fmt.Errorf(
+		"repeated response is not served from the cache: expected %s: %s, first response %s, repeated response %s",
+		check.Header,
+		check.Value,
+		headerValues(check.Header, first.Header.Values(check.Header)),
+		headerValues(check.Header, values),
+	)}, nil
+}
+
+func repeatRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+func headerValues(name string, values []string) string {
+	if len(values) == 0 {
+		return "has no " + name
+	}
+	return name + ": " + strings.Join(values, ", ")
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func runCachingTest(t *testing.T, dir string, handler http.HandlerFunc) []error {
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", dir)),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 1)
+	return collector.results[0].Errors
+}
+
+func TestCachingShouldPassForCacheableResponse(t *testing.T) {
+	errs := runCachingTest(t, "caching", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Encoding")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("page"))
+	})
+
+	assert.Empty(t, errs)
+}
+
+func TestCachingShouldReportMissingHeaders(t *testing.T) {
+	errs := runCachingTest(t, "caching", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write([]byte("page"))
+	})
+
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCaching, errors.New("response caching header ETag is missing")),
+		models.NewCheckError(models.ErrorCategoryCaching, errors.New("response caching header Vary is missing")),
+		models.NewCheckError(models.ErrorCategoryCaching, errors.New("response has no ETag to make the conditional request")),
+	}, errs)
+}
+
+func TestCachingShouldReportFailedRevalidation(t *testing.T) {
+	errs := runCachingTest(t, "caching", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Encoding")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("page"))
+	})
+
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCaching, errors.New(`conditional request with If-None-Match "v1": expected status 304, actual 200`)),
+	}, errs)
+}
+
+func TestCachingShouldPassForCacheHit(t *testing.T) {
+	requests := 0
+	errs := runCachingTest(t, "caching-hit", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-Cache", "MISS")
+		} else {
+			w.Header().Set("X-Cache", "HIT from edge")
+		}
+		_, _ = w.Write([]byte("page"))
+	})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingShouldReportBothResponsesIfNotCacheHit(t *testing.T) {
+	requests := 0
+	errs := runCachingTest(t, "caching-hit", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-Cache", "MISS")
+		}
+		_, _ = w.Write([]byte("page"))
+	})
+
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCaching, errors.New(
+			"repeated response is not served from the cache: expected X-Cache: $matchRegexp(^HIT), "+
+				"first response X-Cache: MISS, repeated response has no X-Cache")),
+	}, errs)
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+// captureVariables sets the variables from the values found in the JSON response by the paths,
+// nothing is set unless all the paths are found
+func (r *Runner) captureVariables(t models.TestInterface, body string) []error {
+	paths := t.Capture()
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		return []error{fmt.Errorf("unable to capture variables, response body is not JSON: %s", err.Error())}
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]string, len(names))
+	var errs []error
+	for _, name := range names {
+		value, err := compare.ResolvePath(decoded, paths[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to capture %s from %s: %s", name, paths[name], err.Error()))
+			continue
+		}
+		values[name] = capturedValue(value)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for name, value := range values {
+		r.config.Variables.Set(name, value)
+	}
+	return nil
+}
+
+// checkExported reports the variables the test promises to the tests of the other files but doesn't set
+func (r *Runner) checkExported(t models.TestInterface) []error {
+	var errs []error
+	for _, name := range t.Export() {
+		if !r.config.Variables.IsSet(name) {
+			errs = append(errs, fmt.Errorf("exported variable %s is not set by the test", name))
+		}
+	}
+	return errs
+}
+
+// capturedValue returns strings as is and other values as JSON
+func capturedValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
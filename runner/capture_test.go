@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func testCaptureServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/login":
+			_, _ = w.Write([]byte(`{"token": "secret", "data": {"id": 7}}`))
+		case r.URL.Path == "/users/7" && r.Header.Get("Authorization") == "Bearer secret":
+			_, _ = w.Write([]byte(`{"user": "gonkey"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func runCaptureTests(t *testing.T, dir string) (*Runner, *resultsCollector) {
+	srv := testCaptureServer()
+	t.Cleanup(srv.Close)
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "capture", dir)),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	return r, collector
+}
+
+func TestCaptureShouldSetVariablesFromResponse(t *testing.T) {
+	r, collector := runCaptureTests(t, "found")
+
+	require.Len(t, collector.results, 2)
+	for _, result := range collector.results {
+		assert.Empty(t, result.Errors)
+	}
+	assert.Equal(t, "/users/7", collector.results[1].Path)
+	assert.Equal(t, 2, r.config.Variables.Len())
+}
+
+func TestCaptureShouldFailOnMissingPaths(t *testing.T) {
+	r, collector := runCaptureTests(t, "missing")
+
+	require.Len(t, collector.results, 1)
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCapture, errors.New("unable to capture id from $.user.id: no field user")),
+		models.NewCheckError(models.ErrorCategoryCapture, errors.New("unable to capture role from $.data.role: no field role")),
+	}, collector.results[0].Errors)
+	assert.Equal(t, 0, r.config.Variables.Len(), "nothing must be captured when a path is missing")
+}
+
+func TestExportedVariablesShouldBeAvailableToDependentTestsOfOtherFiles(t *testing.T) {
+	_, collector := runCaptureTests(t, "shared")
+
+	require.Len(t, collector.results, 2)
+	assert.Equal(t, "login", collector.results[0].Test.GetName(), "dependency must run first")
+	for _, result := range collector.results {
+		assert.Empty(t, result.Errors)
+	}
+	assert.Equal(t, "/users/7", collector.results[1].Path)
+}
+
+func TestExportShouldFailOnVariablesNotSet(t *testing.T) {
+	_, collector := runCaptureTests(t, "not-exported")
+
+	require.Len(t, collector.results, 1)
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCapture, errors.New("exported variable id is not set by the test")),
+	}, collector.results[0].Errors)
+}
+
+func TestDependentTestShouldRunWithoutFileOfDependency(t *testing.T) {
+	srv := testCaptureServer()
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "capture", "shared", "a_profile.yaml")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err, "the unknown dependency must not abort the run")
+	require.Len(t, collector.results, 1)
+}
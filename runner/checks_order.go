@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+// orderCheckers puts the checkers of the listed categories first in the given order,
+// the rest of the checkers keep the order they were registered in
+func orderCheckers(checkers []checker.CheckerInterface, order []models.ErrorCategory) ([]checker.CheckerInterface, error) {
+	ordered := make([]checker.CheckerInterface, 0, len(checkers))
+	used := make(map[int]bool)
+	for _, category := range order {
+		found := false
+		for i, c := range checkers {
+			categorized, ok := c.(checker.CategorizedChecker)
+			if !ok || used[i] || categorized.Category() != category {
+				continue
+			}
+			ordered = append(ordered, c)
+			used[i] = true
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no checker of category %s to order", category)
+		}
+	}
+	for i, c := range checkers {
+		if !used[i] {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/checker/response_status"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+type categoryChecker struct {
+	checker.CheckerInterface
+	category models.ErrorCategory
+}
+
+func (c *categoryChecker) Category() models.ErrorCategory {
+	return c.category
+}
+
+func (c *categoryChecker) Check(models.TestInterface, *models.Result) ([]error, error) {
+	return []error{errors.New(string(c.category))}, nil
+}
+
+func TestOrderCheckersShouldPutListedCategoriesFirst(t *testing.T) {
+	body := &categoryChecker{category: models.ErrorCategoryBody}
+	status := &categoryChecker{category: models.ErrorCategoryStatus}
+	schema := &categoryChecker{category: models.ErrorCategorySchema}
+
+	ordered, err := orderCheckers([]checker.CheckerInterface{body, status, schema}, []models.ErrorCategory{
+		models.ErrorCategorySchema,
+		models.ErrorCategoryStatus,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []checker.CheckerInterface{schema, status, body}, ordered)
+
+	_, err = orderCheckers([]checker.CheckerInterface{body}, []models.ErrorCategory{models.ErrorCategorySchema})
+	assert.EqualError(t, err, "no checker of category schema to order")
+}
+
+func TestStopOnFailureShouldSkipRestOfChecks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "checks-order")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	r.AddCheckers(response_status.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 1)
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryStatus, errors.New(`response status text does not match: expected "Found", actual "OK"`)),
+	}, collector.results[0].Errors)
+}
@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// connectionTrace counts the connections of the requests sent by the traced client
+type connectionTrace struct {
+	sync.Mutex
+	result models.ConnectionsResult
+}
+
+type connectionTraceTransport struct {
+	next  http.RoundTripper
+	trace *connectionTrace
+}
+
+// traceConnections returns the client sharing the connections of the given one
+// and tracing all the requests sent with it
+func traceConnections(client *http.Client) (*http.Client, *connectionTrace) {
+	trace := &connectionTrace{}
+	traced := *client
+	traced.Transport = &connectionTraceTransport{next: transportOf(client), trace: trace}
+	return &traced, trace
+}
+
+func (t *connectionTraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.trace.Lock()
+			defer t.trace.Unlock()
+			if info.Reused {
+				t.trace.result.Reused++
+			} else {
+				t.trace.result.Connections++
+			}
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err != nil {
+				return
+			}
+			t.trace.Lock()
+			defer t.trace.Unlock()
+			t.trace.result.TLSHandshakes++
+		},
+	})
+	t.trace.Lock()
+	t.trace.result.Requests++
+	t.trace.Unlock()
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+func (c *connectionTrace) counts() *models.ConnectionsResult {
+	c.Lock()
+	defer c.Unlock()
+	result := c.result
+	return &result
+}
+
+// warmUp sends the request of the test to open the connections reused by the test,
+// the responses are read till the end so that the connections are returned to the pool
+func warmUp(config *Config, v models.TestInterface, client *http.Client, requests int) error {
+	for i := 0; i < requests; i++ {
+		req, err := newRequest(config, v)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("warm-up request failed: %s", err.Error())
+		}
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	return nil
+}
+
+// scenarioClient returns the client counting the connections of the tests of the file of the test,
+// the counts are kept through the run
+func (r *Runner) scenarioClient(v models.TestInterface, client *http.Client) (*http.Client, *connectionTrace) {
+	if r.scenarios == nil {
+		r.scenarios = map[string]*connectionTrace{}
+	}
+	trace, ok := r.scenarios[v.GetFileName()]
+	if !ok {
+		trace = &connectionTrace{}
+		r.scenarios[v.GetFileName()] = trace
+	}
+	traced := *client
+	traced.Transport = &connectionTraceTransport{next: transportOf(client), trace: trace}
+	return &traced, trace
+}
+
+func transportOf(client *http.Client) http.RoundTripper {
+	if client.Transport == nil {
+		return http.DefaultTransport
+	}
+	return client.Transport
+}
+
+// checkConnectionReuse reports the requests of the test which opened too many connections
+func checkConnectionReuse(check *models.ConnectionReuseCheck, result *models.ConnectionsResult) []error {
+	return checkConnections("", check.MaxConnections, check.MaxTLSHandshakes, result)
+}
+
+// checkScenarioConnectionReuse reports the requests of the tests of the file which opened too many connections
+func checkScenarioConnectionReuse(check *models.ConnectionReuseCheck, result *models.ConnectionsResult) []error {
+	return checkConnections(" of the scenario", check.ScenarioMaxConnections, check.ScenarioMaxTLSHandshakes, result)
+}
+
+func checkConnections(scope string, maxConnections, maxTLSHandshakes *int, result *models.ConnectionsResult) []error {
+	var errs []error
+	if maxConnections != nil && result.Connections > *maxConnections {
+		errs = append(errs, fmt.Errorf("%d request(s)%s opened %d new connection(s), expected at most %d, %d reused",
+			result.Requests, scope, result.Connections, *maxConnections, result.Reused))
+	}
+	if maxTLSHandshakes != nil && result.TLSHandshakes > *maxTLSHandshakes {
+		errs = append(errs, fmt.Errorf("%d request(s)%s made %d TLS handshake(s), expected at most %d",
+			result.Requests, scope, result.TLSHandshakes, *maxTLSHandshakes))
+	}
+	return errs
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestConnectionReuseShouldCountConnections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/close" {
+			w.Header().Set("Connection", "close")
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "connection-reuse")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 2)
+
+	reused := collector.results[0]
+	assert.Empty(t, reused.Errors)
+	assert.Equal(t, &models.ConnectionsResult{Requests: 4, Connections: 0, Reused: 4}, reused.Connections)
+
+	// the first request reuses the connection of the previous test
+	closed := collector.results[1]
+	assert.Equal(t, &models.ConnectionsResult{Requests: 3, Connections: 2, Reused: 1}, closed.Connections)
+	require.Len(t, closed.Errors, 1)
+	assert.Equal(t, models.ErrorCategoryConnections, closed.Errors[0].(*models.CheckError).GetCategory())
+	assert.Equal(t, "3 request(s) opened 2 new connection(s), expected at most 1, 1 reused", closed.Errors[0].Error())
+}
+
+func TestConnectionReuseShouldCountConnectionsOfScenario(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/close" {
+			w.Header().Set("Connection", "close")
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "connection-reuse-scenario")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 5)
+
+	for _, result := range collector.results[:2] {
+		assert.Nil(t, result.ScenarioConnections, "the tests without scenario limits must not report it")
+	}
+	kept := collector.results[2]
+	assert.Empty(t, kept.Errors)
+	assert.Equal(t, &models.ConnectionsResult{Requests: 1, Reused: 1}, kept.Connections, "the test itself is not limited")
+	assert.Equal(t, &models.ConnectionsResult{Requests: 3, Connections: 1, Reused: 2, TLSHandshakes: 1}, kept.ScenarioConnections)
+
+	// the scenario of the other file reuses the connection first, then the closed one is opened again
+	closed := collector.results[4]
+	assert.Equal(t, &models.ConnectionsResult{Requests: 2, Connections: 1, Reused: 1, TLSHandshakes: 1}, closed.ScenarioConnections)
+	require.Len(t, closed.Errors, 1)
+	assert.Equal(t, models.ErrorCategoryConnections, closed.Errors[0].(*models.CheckError).GetCategory())
+	assert.Equal(t, "2 request(s) of the scenario made 1 TLS handshake(s), expected at most 0", closed.Errors[0].Error())
+}
@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// readBody reads the response body, the body cut short of its Content-Length
+// is returned as is when the length is checked
+func readBody(resp *http.Response, checkLength bool) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == io.ErrUnexpectedEOF && checkLength {
+		return body, nil
+	}
+	return body, err
+}
+
+// checkContentLength compares the Content-Length header with the length of the received body,
+// chunked and transparently decompressed responses have no length to compare
+func checkContentLength(req *http.Request, resp *http.Response, body []byte) []error {
+	header := resp.Header.Get("Content-Length")
+	if header == "" || len(resp.TransferEncoding) > 0 || resp.Uncompressed || req.Method == http.MethodHead {
+		return nil
+	}
+	length, err := strconv.Atoi(header)
+	if err != nil {
+		return []error{fmt.Errorf("invalid Content-Length %s", header)}
+	}
+	if length != len(body) {
+		return []error{fmt.Errorf("Content-Length is %d, but the received body has %d bytes", length, len(body))}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestCheckContentLengthShouldReportTruncatedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/full":
+			_, _ = w.Write([]byte(`{"status": "ok"}`))
+		case "/chunked":
+			_, _ = w.Write([]byte(`{"status": `))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(`"ok"}`))
+		case "/truncated":
+			conn, buf, _ := w.(http.Hijacker).Hijack()
+			_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n{\"status\": \"ok\"}")
+			_ = buf.Flush()
+			_ = conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "content-length")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 3)
+
+	assert.Empty(t, collector.results[0].Errors)
+	assert.Empty(t, collector.results[1].Errors)
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryContentLength,
+			errors.New("Content-Length is 100, but the received body has 16 bytes")),
+	}, collector.results[2].Errors)
+}
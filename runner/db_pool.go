@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DBPoolConfig holds connection pool settings of the DB used by fixtures and DB checks.
+// Zero values keep database/sql defaults.
+type DBPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func (c DBPoolConfig) Apply(db *sql.DB) {
+	if db == nil {
+		return
+	}
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+}
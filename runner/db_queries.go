@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// QueryCounter counts the queries of the service to its database, see querycounter.Counter
+type QueryCounter interface {
+	Reset()
+	Queries() []string
+}
+
+// checkDbQueries reports the queries made during the request if there are more than the test allows
+func (r *Runner) checkDbQueries(v models.TestInterface) []error {
+	max := v.MaxDbQueries()
+	if max == nil {
+		return nil
+	}
+	if r.config.QueryCounter == nil {
+		return []error{fmt.Errorf("maxDbQueries can not be checked without QueryCounter in the runner config")}
+	}
+	queries := r.config.QueryCounter.Queries()
+	if len(queries) <= *max {
+		return nil
+	}
+	return []error{fmt.Errorf("service made %d DB queries, expected at most %d:\n%s",
+		len(queries), *max, strings.Join(queries, "\n"))}
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/querycounter"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestMaxDbQueriesShouldReportExtraQueries(t *testing.T) {
+	counter := &testCounter{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		counter.queries = append(counter.queries, "SELECT id FROM orders")
+		for i := 1; i <= limit; i++ {
+			counter.queries = append(counter.queries, "SELECT * FROM items WHERE order_id = "+strconv.Itoa(i))
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:         srv.URL,
+			Variables:    variables.New(),
+			QueryCounter: counter,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "db-queries")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 2)
+
+	assert.Empty(t, collector.results[0].Errors)
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryDbQueries, errors.New(
+			"service made 4 DB queries, expected at most 2:\n"+
+				"SELECT id FROM orders\n"+
+				"SELECT * FROM items WHERE order_id = 1\n"+
+				"SELECT * FROM items WHERE order_id = 2\n"+
+				"SELECT * FROM items WHERE order_id = 3")),
+	}, collector.results[1].Errors)
+}
+
+var _ QueryCounter = querycounter.New()
+
+// testCounter stands for querycounter.Counter used by the service
+type testCounter struct {
+	queries []string
+}
+
+func (c *testCounter) Reset() {
+	c.queries = nil
+}
+
+func (c *testCounter) Queries() []string {
+	return c.queries
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// expectContinueTimeout is how long the default transport waits for 100 Continue before sending the body anyway
+const expectContinueTimeout = time.Second
+
+// continueTrace records the interim response to the request sent with Expect: 100-continue
+type continueTrace struct {
+	sync.Mutex
+	wroteHeaders time.Time
+	got100       time.Time
+	gotResponse  time.Time
+}
+
+// traceContinue returns the context tracing the request sent with it
+func traceContinue(ctx context.Context) (context.Context, *continueTrace) {
+	c := &continueTrace{}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaders: func() {
+			c.Lock()
+			defer c.Unlock()
+			c.wroteHeaders = time.Now()
+		},
+		Got100Continue: func() {
+			c.Lock()
+			defer c.Unlock()
+			c.got100 = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			c.Lock()
+			defer c.Unlock()
+			// the first byte of the interim response is reported too
+			if c.gotResponse.IsZero() {
+				c.gotResponse = time.Now()
+			}
+		},
+	}), c
+}
+
+func (c *continueTrace) result() *models.ContinueResult {
+	c.Lock()
+	defer c.Unlock()
+	if !c.got100.IsZero() {
+		return &models.ContinueResult{Received: true, Delay: c.got100.Sub(c.wroteHeaders)}
+	}
+	return &models.ContinueResult{Delay: c.gotResponse.Sub(c.wroteHeaders)}
+}
+
+// checkContinue reports the server which didn't respond before the transport had to send the body,
+// an early final response (e.g. 413) without 100 Continue is the valid rejection of the body
+func checkContinue(result *models.ContinueResult, resp *http.Response) []error {
+	if result.Delay < expectContinueTimeout {
+		return nil
+	}
+	if result.Received {
+		return []error{fmt.Errorf(
+			"server responded with 100 Continue in %s after the request headers, the body was sent without it in %s",
+			result.Delay,
+			expectContinueTimeout,
+		)}
+	}
+	return []error{fmt.Errorf(
+		"server did not respond in %s after the request headers, the body was sent without 100 Continue, the final response %s came in %s",
+		expectContinueTimeout,
+		resp.Status,
+		result.Delay,
+	)}
+}
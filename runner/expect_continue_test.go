@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestExpectContinueShouldReportInterimResponse(t *testing.T) {
+	var expect []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect = append(expect, r.Header.Get("Expect"))
+		switch r.URL.Path {
+		case "/reject":
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		case "/slow":
+			time.Sleep(expectContinueTimeout + 100*time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		// reading the body makes the server respond with 100 Continue
+		_, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "expect-continue")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 3)
+	assert.Equal(t, []string{"100-continue", "100-continue", "100-continue"}, expect)
+
+	continued := collector.results[0]
+	assert.Empty(t, continued.Errors)
+	require.NotNil(t, continued.Continue)
+	assert.True(t, continued.Continue.Received)
+
+	// the final response before the body is sent is as good as 100 Continue
+	rejected := collector.results[1]
+	require.NotNil(t, rejected.Continue)
+	assert.False(t, rejected.Continue.Received)
+	assert.Empty(t, rejected.Errors)
+
+	slow := collector.results[2]
+	require.NotNil(t, slow.Continue)
+	assert.False(t, slow.Continue.Received)
+	require.Len(t, slow.Errors, 1)
+	assert.True(t, strings.HasPrefix(slow.Errors[0].Error(),
+		"server did not respond in 1s after the request headers, the body was sent without 100 Continue, the final response 201 Created came in"),
+		slow.Errors[0].Error())
+}
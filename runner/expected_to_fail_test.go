@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestExpectedToFailShouldNotFailRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "expected-to-fail")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	summary, err := r.Run()
+	require.NoError(t, err)
+
+	require.Len(t, collector.results, 3)
+	assert.Equal(t, models.StatusXFail, collector.results[0].Status())
+	assert.Equal(t, "skipped", collector.results[0].AllureStatus())
+	assert.Equal(t, models.StatusXPass, collector.results[1].Status())
+	assert.Equal(t, "broken", collector.results[1].AllureStatus())
+	assert.Equal(t, models.StatusPassed, collector.results[2].Status())
+
+	assert.True(t, summary.Success)
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, 1, summary.XFailed)
+	assert.Equal(t, []string{"fixed"}, summary.XPassed)
+	assert.Empty(t, summary.ErrorsByCategory)
+}
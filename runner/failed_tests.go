@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// testID returns identifier of the test used to remember it between runs
+func testID(t models.TestInterface) string {
+	if t.GetName() != "" {
+		return t.GetName()
+	}
+	return strings.ToUpper(t.GetMethod()) + " " + t.Path()
+}
+
+// loadFailedTests reads identifiers of the tests failed during the previous run.
+// Returns nil if there is no such file, meaning that all the tests must be run.
+func loadFailedTests(path string) (map[string]bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			failed[id] = true
+		}
+	}
+	return failed, scanner.Err()
+}
+
+// saveFailedTests writes identifiers of the failed tests to the file,
+// the file is removed when there are no failed tests.
+func saveFailedTests(path string, ids []string) error {
+	if len(ids) == 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(ids, "\n")+"\n"), 0644)
+}
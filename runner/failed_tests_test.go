@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestSaveAndLoadFailedTests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "failed")
+
+	failed, err := loadFailedTests(path)
+	require.NoError(t, err)
+	assert.Nil(t, failed, "missing file means all tests must be run")
+
+	require.NoError(t, saveFailedTests(path, []string{"first test", "GET /second"}))
+	failed, err = loadFailedTests(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"first test": true, "GET /second": true}, failed)
+
+	require.NoError(t, saveFailedTests(path, nil))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "file must be removed after successful run")
+}
+
+func TestRerunFailedRunsOnlyListedTests(t *testing.T) {
+	srv := testServerRedirect()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "failed")
+	require.NoError(t, saveFailedTests(path, []string{"some other test"}))
+
+	r := New(
+		&Config{
+			Host:            srv.URL,
+			Variables:       variables.New(),
+			RerunFailedFrom: path,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "dont-follow-redirects")),
+	)
+
+	summary, err := r.Run()
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Total)
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// prepareFixtures loads the fixtures of the test. For the test skipping fixtures
+// the tables of all the fixtures loaded before are truncated instead,
+// the suite fixtures are loaded again for the next test which doesn't skip them
+func (r *Runner) prepareFixtures(v models.TestInterface) ([]models.TableCleanup, error) {
+	if v.SkipFixtures() && (len(v.Fixtures()) > 0 || len(v.InlineFixtures()) > 0) {
+		return nil, errors.New("fixtures can not be loaded by the test which skips fixtures")
+	}
+	if r.config.FixturesLoader == nil {
+		return nil, nil
+	}
+
+	if v.SkipFixtures() {
+		names := r.loadedFixtures
+		if !r.suiteCleaned {
+			names = append(append([]string{}, r.config.SuiteFixtures...), names...)
+		}
+		if len(names) == 0 && len(r.loadedInline) == 0 {
+			return nil, nil
+		}
+		cleanups, err := r.config.FixturesLoader.CleanWithInline(names, r.loadedInline)
+		if err != nil {
+			return nil, fmt.Errorf("unable to clean fixtures: %s", err.Error())
+		}
+		r.loadedFixtures = nil
+		r.loadedInline = nil
+		r.suiteCleaned = len(r.config.SuiteFixtures) > 0
+		return cleanups, nil
+	}
+
+	if r.suiteCleaned {
+		if err := r.setUpSuite(); err != nil {
+			return nil, err
+		}
+		r.suiteCleaned = false
+	}
+	if v.Fixtures() == nil && v.InlineFixtures() == nil {
+		return nil, nil
+	}
+	if err := r.config.FixturesLoader.LoadWithInline(v.Fixtures(), v.InlineFixtures()); err != nil {
+		return nil, err
+	}
+	r.loadedFixtures = remember(r.loadedFixtures, v.Fixtures())
+	r.loadedInline = remember(r.loadedInline, v.InlineFixtures())
+	return nil, nil
+}
+
+// remember appends the fixtures not known yet
+func remember(known []string, fixtures []string) []string {
+	for _, fixture := range fixtures {
+		found := false
+		for _, loaded := range known {
+			if loaded == fixture {
+				found = true
+				break
+			}
+		}
+		if !found {
+			known = append(known, fixture)
+		}
+	}
+	return known
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// newIdempotencyKey returns the key given in the test or a random one
+func newIdempotencyKey(check *models.IdempotencyCheck) (string, error) {
+	if check.Key != "" {
+		return check.Key, nil
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// checkIdempotency repeats the request with the same idempotency key
+// and reports the first difference between the responses
+func (r *Runner) checkIdempotency(v models.TestInterface, client *http.Client, check *models.IdempotencyCheck,
+	key string, first *http.Response, firstBody string) ([]error, error) {
+
+	req, err := newRequest(r.config, v)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(check.Header, key)
+
+	second, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(second.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = second.Body.Close()
+
+	if err := compareIdempotentResponses(check, first, firstBody, second, string(body)); err != nil {
+		return []error{err}, nil
+	}
+	return nil, nil
+}
+
+func compareIdempotentResponses(check *models.IdempotencyCheck, first *http.Response, firstBody string,
+	second *http.Response, secondBody string) error {
+
+	if first.StatusCode != second.StatusCode {
+		return idempotencyError(check, "status", first.StatusCode, second.StatusCode)
+	}
+	for _, name := range check.CompareHeaders {
+		if first.Header.Get(name) != second.Header.Get(name) {
+			return idempotencyError(check, "header "+name, first.Header.Get(name), second.Header.Get(name))
+		}
+	}
+	if firstBody != secondBody {
+		return idempotencyError(check, "body", firstBody, secondBody)
+	}
+	return nil
+}
+
+func idempotencyError(check *models.IdempotencyCheck, field string, first, second interface{}) error {
+	return fmt.Errorf(
+		"responses to the requests with the same %s differ in %s:\n     first: %v\n    second: %v",
+		check.Header,
+		field,
+		first,
+		second,
+	)
+}
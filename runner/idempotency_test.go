@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+type resultsCollector struct {
+	results []*models.Result
+}
+
+func (c *resultsCollector) Process(_ models.TestInterface, result *models.Result) error {
+	c.results = append(c.results, result)
+	return nil
+}
+
+func testIdempotentServer(repeatStatus int) (*httptest.Server, *[]string) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusCreated
+		if len(keys) > 0 {
+			status = repeatStatus
+		}
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	return srv, &keys
+}
+
+func runIdempotencyTest(t *testing.T, srv *httptest.Server) *models.Result {
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "idempotency")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 1)
+	return collector.results[0]
+}
+
+func TestIdempotencyShouldRepeatRequestWithSameKey(t *testing.T) {
+	srv, keys := testIdempotentServer(http.StatusCreated)
+	defer srv.Close()
+
+	result := runIdempotencyTest(t, srv)
+
+	assert.Empty(t, result.Errors)
+	require.Len(t, *keys, 2)
+	assert.NotEmpty(t, (*keys)[0])
+	assert.Equal(t, (*keys)[0], (*keys)[1])
+}
+
+func TestIdempotencyShouldReportDifferentResponses(t *testing.T) {
+	srv, _ := testIdempotentServer(http.StatusConflict)
+	defer srv.Close()
+
+	result := runIdempotencyTest(t, srv)
+
+	require.Len(t, result.Errors, 1)
+	assert.True(t, strings.Contains(result.Errors[0].Error(), "differ in status"), result.Errors[0].Error())
+}
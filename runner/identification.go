@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// Version of gonkey sent in the default User-Agent,
+// set at build time with -ldflags "-X github.com/lamoda/gonkey/runner.Version=..."
+var Version = "dev"
+
+// Headers identifying the requests of the tests in the logs of the service
+const (
+	TestNameHeader  = "X-Test-Name"
+	TestRunIDHeader = "X-Test-Run-Id"
+)
+
+// defaultUserAgent is sent if neither the config nor the test sets User-Agent
+func defaultUserAgent() string {
+	return "gonkey/" + Version
+}
+
+// newRunID returns the random identifier of the run
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// identifyRequest sets User-Agent, the name of the test and the identifier of the run
+// unless the test sets these headers itself
+func identifyRequest(config *Config, test models.TestInterface, request *http.Request) {
+	if config.DisableRequestIdentification {
+		return
+	}
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent()
+	}
+	setDefaultHeader(request, "User-Agent", userAgent)
+	setDefaultHeader(request, TestNameHeader, headerValue(testID(test)))
+	setDefaultHeader(request, TestRunIDHeader, config.RunID)
+}
+
+// headerValue makes the name a valid header value: a folded or multi-line YAML name
+// is joined into one line, the other control characters are dropped
+func headerValue(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\r' || r == '\n' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func setDefaultHeader(request *http.Request, name, value string) {
+	if value != "" && request.Header.Get(name) == "" {
+		request.Header.Set(name, value)
+	}
+}
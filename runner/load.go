@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// runLoad repeats the request of the test and measures the time of the responses,
+// the requests must respond with the status of the checked one
+func (r *Runner) runLoad(v models.TestInterface, client *http.Client, load *models.LoadCheck, status int) (*models.LoadResult, []error, error) {
+	requests := make([]*http.Request, load.Requests)
+	for i := range requests {
+		req, err := newRequest(r.config, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		requests[i] = req
+	}
+
+	durations := make([]time.Duration, 0, load.Requests)
+	failed := 0
+	var mu sync.Mutex
+	jobs := make(chan *http.Request)
+	var wg sync.WaitGroup
+	for i := 0; i < load.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range jobs {
+				duration, ok := timeRequest(client, req, status)
+				mu.Lock()
+				durations = append(durations, duration)
+				if !ok {
+					failed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, req := range requests {
+		jobs <- req
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result := &models.LoadResult{
+		Requests: load.Requests,
+		Failed:   failed,
+		P50:      percentile(durations, 50),
+		P95:      percentile(durations, 95),
+		P99:      percentile(durations, 99),
+	}
+
+	var errs []error
+	if failed > 0 {
+		errs = append(errs, fmt.Errorf("%d of %d load requests failed or did not respond with status %d",
+			failed, load.Requests, status))
+	}
+	if load.P95Under > 0 && result.P95 >= load.P95Under {
+		errs = append(errs, fmt.Errorf("response time p95 %s is not under %s (p50 %s, p99 %s)",
+			result.P95, load.P95Under, result.P50, result.P99))
+	}
+	return result, errs, nil
+}
+
+// timeRequest returns the time till the whole response is read and whether it has the status
+func timeRequest(client *http.Client, req *http.Request, status int) (time.Duration, bool) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Since(start), false
+	}
+	_, err = ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	return time.Since(start), err == nil && resp.StatusCode == status
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestLoadShouldCheckResponseTimePercentile(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "load")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 2)
+	assert.Equal(t, map[string]int{"/fast": 21, "/slow": 5}, calls)
+
+	fast := collector.results[0]
+	assert.Empty(t, fast.Errors)
+	require.NotNil(t, fast.Load)
+	assert.Equal(t, 20, fast.Load.Requests)
+	assert.Zero(t, fast.Load.Failed)
+	assert.True(t, fast.Load.P50 <= fast.Load.P95 && fast.Load.P95 <= fast.Load.P99)
+
+	slow := collector.results[1]
+	require.Len(t, slow.Errors, 1)
+	assert.True(t, strings.HasPrefix(slow.Errors[0].Error(), "response time p95 "), slow.Errors[0].Error())
+	assert.Contains(t, slow.Errors[0].Error(), "is not under 20ms")
+}
+
+func TestPercentileShouldUseNearestRank(t *testing.T) {
+	var durations []time.Duration
+	for i := 1; i <= 20; i++ {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 10*time.Millisecond, percentile(durations, 50))
+	assert.Equal(t, 19*time.Millisecond, percentile(durations, 95))
+	assert.Equal(t, 20*time.Millisecond, percentile(durations, 99))
+	assert.Equal(t, time.Duration(0), percentile(nil, 95))
+}
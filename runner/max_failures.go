@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// failuresBudget is the number of the failed tests tolerated by the run,
+// either absolute or the percentage of the executed tests
+type failuresBudget struct {
+	count   int
+	percent float64
+}
+
+// parseMaxFailures parses the budget like "3" or "5%", nil means that no failures are tolerated
+func parseMaxFailures(s string) (*failuresBudget, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid max failures %q, expected a number of tests or a percentage from 0%% to 100%%", s)
+		}
+		return &failuresBudget{percent: percent}, nil
+	}
+	count, err := strconv.Atoi(s)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid max failures %q, expected a number of tests or a percentage from 0%% to 100%%", s)
+	}
+	return &failuresBudget{count: count}, nil
+}
+
+// allowed returns the number of the tests allowed to fail, the percentage is rounded down
+func (b *failuresBudget) allowed(total int) int {
+	if b.percent > 0 {
+		return int(float64(total) * b.percent / 100)
+	}
+	return b.count
+}
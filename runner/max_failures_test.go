@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestParseMaxFailures(t *testing.T) {
+	tests := []struct {
+		value   string
+		total   int
+		allowed int
+	}{
+		{value: "3", total: 10, allowed: 3},
+		{value: "0", total: 10, allowed: 0},
+		{value: "25%", total: 10, allowed: 2},
+		{value: "100%", total: 7, allowed: 7},
+	}
+	for _, tt := range tests {
+		budget, err := parseMaxFailures(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.allowed, budget.allowed(tt.total), tt.value)
+	}
+
+	budget, err := parseMaxFailures("")
+	assert.NoError(t, err)
+	assert.Nil(t, budget)
+
+	for _, value := range []string{"-1", "many", "120%"} {
+		_, err := parseMaxFailures(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestMaxFailuresShouldPassRunWithinBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	for maxFailures, success := range map[string]bool{"1": true, "0": false, "100%": true, "50%": false} {
+		r := New(
+			&Config{
+				Host:        srv.URL,
+				Variables:   variables.New(),
+				MaxFailures: maxFailures,
+			},
+			yaml_file.NewLoader(filepath.Join("testdata", "error-categories")),
+		)
+		r.AddCheckers(response_body.NewChecker())
+
+		summary, err := r.Run()
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Failed, maxFailures)
+		assert.Equal(t, success, summary.Success, maxFailures)
+		require.NotNil(t, summary.MaxFailures, maxFailures)
+	}
+}
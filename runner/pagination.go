@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+// fetchPages follows the next page URLs starting from the first response
+// and returns the JSON array of the items of all the pages
+func fetchPages(client *http.Client, first *http.Request, firstBody string,
+	pagination *models.Pagination) (string, []error, error) {
+
+	var items []interface{}
+	body := firstBody
+	pageURL := first.URL
+	for page := 1; ; page++ {
+		pageItems, next, err := parsePage(body, pagination)
+		if err != nil {
+			return "", []error{fmt.Errorf("page %d: %s", page, err.Error())}, nil
+		}
+		items = append(items, pageItems...)
+		if next == "" {
+			break
+		}
+		if page == pagination.MaxPages {
+			return "", []error{fmt.Errorf("pagination does not end within %d pages", pagination.MaxPages)}, nil
+		}
+
+		nextURL, err := pageURL.Parse(next)
+		if err != nil {
+			return "", []error{fmt.Errorf("page %d: invalid next page URL %s: %s", page, next, err.Error())}, nil
+		}
+		var status int
+		if status, body, err = fetchPage(client, first, nextURL); err != nil {
+			return "", nil, err
+		}
+		if status != http.StatusOK {
+			return "", []error{fmt.Errorf("page %d (%s) responded with status %d", page+1, nextURL, status)}, nil
+		}
+		pageURL = nextURL
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+	combined, err := json.Marshal(items)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(combined), nil, nil
+}
+
+// parsePage returns the items of the page and the URL of the next one if any
+func parsePage(body string, pagination *models.Pagination) ([]interface{}, string, error) {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		return nil, "", fmt.Errorf("response body is not JSON: %s", err.Error())
+	}
+
+	value, err := compare.ResolvePath(decoded, pagination.Items)
+	if err != nil {
+		return nil, "", fmt.Errorf("no items at %s: %s", pagination.Items, err.Error())
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("items at %s are not an array", pagination.Items)
+	}
+
+	// the last page has no next URL, an empty or a null one
+	next, err := compare.ResolvePath(decoded, pagination.Next)
+	if err != nil || next == nil {
+		return items, "", nil
+	}
+	nextURL, ok := next.(string)
+	if !ok {
+		return nil, "", fmt.Errorf("next page URL at %s is not a string", pagination.Next)
+	}
+	return items, nextURL, nil
+}
+
+// fetchPage requests the page with the headers of the first request
+func fetchPage(client *http.Client, first *http.Request, pageURL *url.URL) (int, string, error) {
+	req, err := http.NewRequest(http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header = first.Header.Clone()
+	req.Header.Del("Content-Type")
+
+	resp, err := client.Do(req.WithContext(first.Context()))
+	if err != nil {
+		return 0, "", err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
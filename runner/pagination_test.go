@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func testPaginatedServer() *httptest.Server {
+	pages := map[string]string{
+		"":  `{"data": [{"id": 1}, {"id": 2}], "links": {"next": "/items?page=2"}}`,
+		"2": `{"data": [{"id": 3}], "links": {"next": "items?page=3"}}`,
+		"3": `{"data": [], "links": {"next": null}}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" && r.URL.Path == "/items" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/endless" {
+			_, _ = fmt.Fprintf(w, `{"data": [], "links": {"next": "/endless?page=%s1"}}`, r.URL.Query().Get("page"))
+			return
+		}
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+}
+
+func runPaginationTest(t *testing.T, dir string) *models.Result {
+	srv := testPaginatedServer()
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "pagination", dir)),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 1)
+	return collector.results[0]
+}
+
+func TestPaginationShouldCompareItemsOfAllPages(t *testing.T) {
+	result := runPaginationTest(t, "pages")
+
+	assert.Empty(t, result.Errors)
+	assert.JSONEq(t, `[{"id": 1}, {"id": 2}, {"id": 3}]`, result.ResponseBody)
+}
+
+func TestPaginationShouldStopAtMaxPages(t *testing.T) {
+	result := runPaginationTest(t, "endless")
+
+	require.NotEmpty(t, result.Errors)
+	assert.Equal(t,
+		models.NewCheckError(models.ErrorCategoryPagination, errors.New("pagination does not end within 3 pages")),
+		result.Errors[0],
+	)
+}
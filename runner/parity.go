@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+// checkParity sends the request of the test to the parity host and reports the first difference
+// of its response from the response of the host under test, the ignored paths are not compared
+func (r *Runner) checkParity(v models.TestInterface, client *http.Client, resp *http.Response, body string) ([]error, error) {
+	parityConfig := *r.config
+	parityConfig.Host = r.config.ParityHost
+	req, err := newRequest(&parityConfig, v)
+	if err != nil {
+		return nil, err
+	}
+	parityResp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	parityBody, err := ioutil.ReadAll(parityResp.Body)
+	_ = parityResp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if parityResp.StatusCode != resp.StatusCode {
+		return []error{fmt.Errorf("parity host %s responded with status %d, expected %d",
+			r.config.ParityHost, parityResp.StatusCode, resp.StatusCode)}, nil
+	}
+
+	ignore := append(append([]string{}, r.config.ParityIgnore...), v.ParityIgnore()...)
+	diff, err := parityDiff(body, string(parityBody), ignore)
+	if err != nil || len(diff) == 0 {
+		return nil, err
+	}
+	// the rest of the differences are often caused by the first one
+	return []error{fmt.Errorf("parity host %s response differs: %s", r.config.ParityHost, diff[0].Error())}, nil
+}
+
+// parityDiff compares the bodies, JSON bodies are compared without the ignored paths
+func parityDiff(expected, actual string, ignore []string) ([]error, error) {
+	var expectedJSON, actualJSON interface{}
+	if json.Unmarshal([]byte(expected), &expectedJSON) != nil || json.Unmarshal([]byte(actual), &actualJSON) != nil {
+		if expected != actual {
+			return []error{fmt.Errorf("bodies are not equal:\n%s", strings.TrimSpace(actual))}, nil
+		}
+		return nil, nil
+	}
+
+	for _, path := range ignore {
+		if err := compare.RemovePath(expectedJSON, path); err != nil {
+			return nil, err
+		}
+		if err := compare.RemovePath(actualJSON, path); err != nil {
+			return nil, err
+		}
+	}
+	return compare.Compare(expectedJSON, actualJSON, compare.CompareParams{DisallowExtraFields: true}), nil
+}
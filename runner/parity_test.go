@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func testOrdersServer(order string, missingStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orders/1" {
+			w.WriteHeader(missingStatus)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(order))
+	}))
+}
+
+func runParity(t *testing.T, host, parityHost string, ignore []string) []*models.Result {
+	r := New(
+		&Config{
+			Host:         host,
+			Variables:    variables.New(),
+			ParityHost:   parityHost,
+			ParityIgnore: ignore,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "parity")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 2)
+	return collector.results
+}
+
+func TestParityShouldIgnoreListedPaths(t *testing.T) {
+	srv := testOrdersServer(`{"id": 1, "status": "new", "updated_at": "2020-01-01", "items": [{"id": 10}]}`, http.StatusNotFound)
+	defer srv.Close()
+	parity := testOrdersServer(`{"id": 1, "status": "new", "updated_at": "2021-02-02", "items": [{"id": 20}]}`, http.StatusNotFound)
+	defer parity.Close()
+
+	for _, result := range runParity(t, srv.URL, parity.URL, []string{"$.updated_at"}) {
+		assert.Empty(t, result.Errors, result.Test.GetName())
+	}
+}
+
+func TestParityShouldReportDifferences(t *testing.T) {
+	srv := testOrdersServer(`{"id": 1, "status": "new", "items": [{"id": 10}]}`, http.StatusNotFound)
+	defer srv.Close()
+	parity := testOrdersServer(`{"id": 1, "status": "paid", "items": [{"id": 20}]}`, http.StatusInternalServerError)
+	defer parity.Close()
+
+	results := runParity(t, srv.URL, parity.URL, nil)
+
+	require.Len(t, results[0].Errors, 1)
+	checkErr, ok := results[0].Errors[0].(*models.CheckError)
+	require.True(t, ok)
+	assert.Equal(t, models.ErrorCategoryParity, checkErr.GetCategory())
+	assert.Contains(t, results[0].Errors[0].Error(), "parity host "+parity.URL+" response differs: ")
+	assert.Contains(t, results[0].Errors[0].Error(), "paid")
+
+	require.Len(t, results[1].Errors, 1)
+	assert.EqualError(t, results[1].Errors[0], "parity host "+parity.URL+" responded with status 500, expected 404")
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// checkRepeat sends the request once again, with the same idempotency key if any,
+// and checks the status of the second response
+func (r *Runner) checkRepeat(v models.TestInterface, client *http.Client, check *models.RepeatCheck,
+	idempotencyKey string, first *http.Response) (*models.RepeatResult, []error, error) {
+
+	req, err := newRequest(r.config, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	if idempotency := v.Idempotency(); idempotency != nil {
+		req.Header.Set(idempotency.Header, idempotencyKey)
+	}
+
+	second, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, _ = io.Copy(ioutil.Discard, second.Body)
+	_ = second.Body.Close()
+
+	result := &models.RepeatResult{
+		FirstStatus:  first.StatusCode,
+		SecondStatus: second.StatusCode,
+	}
+	if second.StatusCode != check.Status {
+		return result, []error{fmt.Errorf(
+			"repeated request responded with status %d, expected %d (first response: %d)",
+			second.StatusCode,
+			check.Status,
+			first.StatusCode,
+		)}, nil
+	}
+	return result, nil, nil
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func runRepeatTest(t *testing.T, repeatStatus int) (*models.Result, int) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(repeatStatus)
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "repeat")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 1)
+	return collector.results[0], requests
+}
+
+func TestRepeatShouldCheckSecondStatus(t *testing.T) {
+	result, requests := runRepeatTest(t, http.StatusNotFound)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, &models.RepeatResult{FirstStatus: 204, SecondStatus: 404}, result.Repeat)
+}
+
+func TestRepeatShouldReportUnexpectedSecondStatus(t *testing.T) {
+	result, _ := runRepeatTest(t, http.StatusNoContent)
+
+	require.Len(t, result.Errors, 1)
+	assert.EqualError(t, result.Errors[0], "repeated request responded with status 204, expected 404 (first response: 204)")
+	assert.Equal(t, &models.RepeatResult{FirstStatus: 204, SecondStatus: 204}, result.Repeat)
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -12,9 +13,34 @@ import (
 	"github.com/lamoda/gonkey/models"
 )
 
-func newClient() (*http.Client, error) {
+// newClient returns the client of the config if any, otherwise the one not following redirects
+func newClient(config *Config) (*http.Client, error) {
+	if config.HTTPClient != nil {
+		return config.HTTPClient, nil
+	}
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// newTransport returns the transport of the config if any,
+// otherwise the one skipping TLS verification and using HTTP_PROXY, HTTP/2 is used only if enabled
+func newTransport(config *Config) (http.RoundTripper, error) {
+	if config.Transport != nil {
+		return config.Transport, nil
+	}
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+		ExpectContinueTimeout: expectContinueTimeout,
+		// custom TLS config disables HTTP/2 unless it's forced
+		ForceAttemptHTTP2: config.EnableHTTP2,
 	}
 	if os.Getenv("HTTP_PROXY") != "" {
 		proxyUrl, err := url.Parse(os.Getenv("HTTP_PROXY"))
@@ -23,23 +49,63 @@ func newClient() (*http.Client, error) {
 		}
 		transport.Proxy = http.ProxyURL(proxyUrl)
 	}
+	return transport, nil
+}
 
-	return &http.Client{
-		Transport: transport,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}, nil
+// requestQuery appends the query parameters to the query of the test,
+// the parameter with JSON array value is repeated for every element
+func requestQuery(test models.TestInterface) string {
+	query := test.ToQuery()
+	params := test.GetQueryParams()
+	if len(params) == 0 {
+		return query
+	}
+
+	values := url.Values{}
+	for name, list := range params {
+		for _, value := range list {
+			values[name] = append(values[name], expandArray(value)...)
+		}
+	}
+
+	if query == "" || query == "?" {
+		return "?" + values.Encode()
+	}
+	return query + "&" + values.Encode()
 }
 
-func newRequest(host string, test models.TestInterface) (*http.Request, error) {
+// expandArray returns elements of JSON array or the value itself
+func expandArray(value string) []string {
+	if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		return []string{value}
+	}
+	var elements []interface{}
+	if err := json.Unmarshal([]byte(value), &elements); err != nil {
+		return []string{value}
+	}
+	res := make([]string, len(elements))
+	for i, element := range elements {
+		if s, ok := element.(string); ok {
+			res[i] = s
+			continue
+		}
+		data, _ := json.Marshal(element)
+		res[i] = string(data)
+	}
+	return res
+}
+
+func newRequest(config *Config, test models.TestInterface) (*http.Request, error) {
 	body, err := test.ToJSON()
 	if err != nil {
 		return nil, err
 	}
+	if config.CanonicalizeRequestBody {
+		body = canonicalJSON(body)
+	}
 	request, err := http.NewRequest(
 		strings.ToUpper(test.GetMethod()),
-		host+test.Path()+test.ToQuery(),
+		config.Host+test.Path()+requestQuery(test),
 		bytes.NewBuffer(body),
 	)
 	if err != nil {
@@ -51,14 +117,36 @@ func newRequest(host string, test models.TestInterface) (*http.Request, error) {
 	for k, v := range test.Cookies() {
 		request.AddCookie(&http.Cookie{Name: k, Value: v})
 	}
+	identifyRequest(config, test, request)
 
-	if request.Header.Get("Content-Type") == "" {
+	// only JSON body is recognized, other bodies are sent without the type,
+	// the empty body keeps the JSON type every request was sent with before the inference
+	if request.Header.Get("Content-Type") == "" && !config.DisableContentTypeInference && (len(body) == 0 || json.Valid(body)) {
 		request.Header.Set("Content-Type", "application/json")
 	}
 
 	return request, nil
 }
 
+// canonicalJSON re-encodes JSON document with sorted keys and no extra spaces,
+// the body is returned as is if it's not JSON
+func canonicalJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
 func actualRequestBody(req *http.Request) string {
 	if req.Body != nil {
 		reqBodyStream, _ := req.GetBody()
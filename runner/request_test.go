@@ -0,0 +1,198 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "keys are sorted",
+			body: `{"b": 1, "a": {"d": [3, 2], "c": "<tag>"}}`,
+			want: `{"a":{"c":"<tag>","d":[3,2]},"b":1}`,
+		},
+		{
+			name: "numbers are kept as is",
+			body: `{"id": 12345678901234567890, "amount": 1.50}`,
+			want: `{"amount":1.50,"id":12345678901234567890}`,
+		},
+		{
+			name: "not JSON",
+			body: `key=value`,
+			want: `key=value`,
+		},
+		{
+			name: "several documents",
+			body: `{"a": 1} {"b": 2}`,
+			want: `{"a": 1} {"b": 2}`,
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(canonicalJSON([]byte(tt.body))))
+		})
+	}
+}
+
+func TestRequestQueryShouldExpandArrayVariables(t *testing.T) {
+	definition := `
+query: ?sort=name
+queryParams:
+  id: "{{ $ids }}"
+  status: [new, "in progress"]
+  limit: 10
+`
+	test := &yaml_file.Test{}
+	require.NoError(t, yaml.Unmarshal([]byte(definition), &test.TestDefinition))
+
+	vars := variables.New()
+	vars.Set("ids", `[1, 2, "a&b"]`)
+
+	assert.Equal(t,
+		"?sort=name&id=1&id=2&id=a%26b&limit=10&status=new&status=in+progress",
+		requestQuery(vars.Apply(test)),
+	)
+}
+
+func TestRequestQueryShouldKeepQueryWithoutParams(t *testing.T) {
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{QueryParams: "?id=1"}}
+
+	assert.Equal(t, "?id=1", requestQuery(test))
+}
+
+func TestNewRequestShouldInferJSONContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		disable bool
+		want    string
+	}{
+		{name: "JSON body", body: `{"id": 1}`, want: "application/json"},
+		{name: "JSON array body", body: `[1, 2]`, want: "application/json"},
+		{name: "plain text body", body: `id=1`, want: ""},
+		{name: "empty body", body: ``, want: "application/json"},
+		{name: "empty body with inference disabled", body: ``, disable: true, want: ""},
+		{name: "header set in test", body: `{"id": 1}`, headers: map[string]string{"Content-Type": "text/plain"}, want: "text/plain"},
+		{name: "inference disabled", body: `{"id": 1}`, disable: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &yaml_file.Test{
+				TestDefinition: yaml_file.TestDefinition{Method: "POST", HeadersVal: tt.headers},
+				Request:        tt.body,
+			}
+
+			req, err := newRequest(&Config{Host: "http://localhost", DisableContentTypeInference: tt.disable}, test)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, req.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestNewRequestShouldIdentifyTest(t *testing.T) {
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{Name: "list books", Method: "GET", RequestURL: "/books"},
+	}
+
+	req, err := newRequest(&Config{Host: "http://localhost", RunID: "run-1"}, test)
+	require.NoError(t, err)
+	assert.Equal(t, "gonkey/"+Version, req.Header.Get("User-Agent"))
+	assert.Equal(t, "list books", req.Header.Get(TestNameHeader))
+	assert.Equal(t, "run-1", req.Header.Get(TestRunIDHeader))
+
+	test.HeadersVal = map[string]string{"User-Agent": "mobile-app/2.1", TestNameHeader: "custom"}
+	req, err = newRequest(&Config{Host: "http://localhost", UserAgent: "ci", RunID: "run-1"}, test)
+	require.NoError(t, err)
+	assert.Equal(t, "mobile-app/2.1", req.Header.Get("User-Agent"), "headers of the test must be kept")
+	assert.Equal(t, "custom", req.Header.Get(TestNameHeader))
+
+	req, err = newRequest(&Config{Host: "http://localhost", RunID: "run-1"}, &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{Name: "list books\nof the  author\r\n\x00", Method: "GET", RequestURL: "/books"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "list books of the author", req.Header.Get(TestNameHeader), "multi-line name must be joined")
+
+	req, err = newRequest(&Config{Host: "http://localhost", RunID: "run-1", DisableRequestIdentification: true}, &yaml_file.Test{})
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get(TestNameHeader))
+	assert.Empty(t, req.Header.Get(TestRunIDHeader))
+}
+
+func TestNewClientShouldUseClientOfConfig(t *testing.T) {
+	custom := &http.Client{Timeout: time.Second}
+
+	client, err := newClient(&Config{HTTPClient: custom, Transport: http.DefaultTransport})
+	require.NoError(t, err)
+	assert.Same(t, custom, client)
+}
+
+func TestNewClientShouldUseHTTP2OnlyIfEnabled(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		enable bool
+		want   string
+	}{
+		{enable: false, want: "HTTP/1.1"},
+		{enable: true, want: "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		client, err := newClient(&Config{EnableHTTP2: tt.enable})
+		require.NoError(t, err)
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, tt.want, resp.Proto)
+	}
+}
+
+func TestNewClientShouldNotFollowRedirectsByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client, err := newClient(&Config{})
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL + "/old")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+
+	client, err = newClient(&Config{HTTPClient: &http.Client{}})
+	require.NoError(t, err)
+	resp, err = client.Get(srv.URL + "/old")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,19 @@
+package runner
+
+import "github.com/lamoda/gonkey/models"
+
+// shouldRetry returns true if the failed attempt is not the last one allowed by the policy
+func shouldRetry(policy *models.RetryPolicy, attempt int, result *models.Result) bool {
+	if policy == nil || attempt >= policy.Attempts || result.Passed() {
+		return false
+	}
+	if len(policy.RetryOnStatus) == 0 {
+		return true
+	}
+	for _, status := range policy.RetryOnStatus {
+		if status == result.ResponseStatusCode {
+			return true
+		}
+	}
+	return false
+}
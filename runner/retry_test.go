@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestRetryPolicyShouldSendFailedRequestAgain(t *testing.T) {
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch {
+		case r.URL.Path == "/eventual" && calls[r.URL.Path] >= 3:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status": "ready"}`))
+		case r.URL.Path == "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "retry")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	require.Len(t, collector.results, 3)
+	assert.Equal(t, map[string]int{"/eventual": 3, "/broken": 1, "/unavailable": 2}, calls)
+
+	eventual := collector.results[0]
+	assert.Empty(t, eventual.Errors, "only the last attempt must be reported")
+	assert.Equal(t, 3, eventual.Attempts)
+	require.Len(t, eventual.AttemptResults, 3)
+	assert.Equal(t, "503 Service Unavailable", eventual.AttemptResults[0].ResponseStatus)
+	assert.NotEmpty(t, eventual.AttemptResults[0].Errors, "the errors of the failed attempt must be kept")
+	assert.Equal(t, "200 OK", eventual.AttemptResults[2].ResponseStatus)
+	assert.Empty(t, eventual.AttemptResults[2].Errors)
+
+	broken := collector.results[1]
+	assert.NotEmpty(t, broken.Errors)
+	assert.Equal(t, 1, broken.Attempts)
+	assert.Empty(t, broken.AttemptResults, "the test sent once has no attempts to report")
+
+	unavailable := collector.results[2]
+	assert.NotEmpty(t, unavailable.Errors)
+	assert.Equal(t, 2, unavailable.Attempts)
+	assert.Len(t, unavailable.AttemptResults, 2)
+}
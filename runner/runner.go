@@ -1,9 +1,12 @@
 package runner
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,10 +22,94 @@ import (
 
 type Config struct {
 	Host           string
-	FixturesLoader *fixtures.Loader
+	FixturesLoader fixtures.LoaderInterface
 	Mocks          *mocks.Mocks
 	MocksLoader    *mocks.Loader
 	Variables      *variables.Variables
+
+	// FixturesLog receives the debug output of FixturesLoader,
+	// the output of loading the fixtures of the test is reported as Result.FixturesLog
+	FixturesLog *bytes.Buffer
+
+	// VariablesSources are consulted for the variables not defined in tests,
+	// prior to the environment variables
+	VariablesSources []variables.Source
+
+	// Tracer starts the spans of every test and of its phases, no spans are started if nil
+	Tracer Tracer
+
+	// QueryCounter counts the DB queries of the service for the tests with maxDbQueries
+	QueryCounter QueryCounter
+
+	// ParityHost receives the same requests as Host, e.g. production while staging is tested,
+	// its responses must have the same status and body except ParityIgnore paths (and the ones of the test)
+	ParityHost   string
+	ParityIgnore []string
+
+	// Transport sends the requests of the tests instead of the default one,
+	// which skips TLS verification and uses HTTP_PROXY; none of that applies to the custom transport
+	Transport http.RoundTripper
+	// EnableHTTP2 makes the default transport negotiate HTTP/2 with HTTPS hosts,
+	// the requests are sent over HTTP/1.1 otherwise
+	EnableHTTP2 bool
+	// HTTPClient sends the requests of the tests as is, with its transport, timeout and redirect policy,
+	// Transport is ignored if it's set. The default client doesn't follow redirects
+	HTTPClient *http.Client
+
+	// UserAgent is sent by the tests without User-Agent header, "gonkey/<Version>" by default.
+	// The tests are also identified by X-Test-Name header with the name of the test
+	// and X-Test-Run-Id with RunID, which is generated by New if empty.
+	// The headers set by the test are kept, DisableRequestIdentification sends none of these headers
+	UserAgent                    string
+	RunID                        string
+	DisableRequestIdentification bool
+
+	// CanonicalizeRequestBody makes JSON request bodies sent with sorted keys
+	CanonicalizeRequestBody bool
+	// DisableContentTypeInference stops setting Content-Type of JSON request bodies
+	// if the test has no such header
+	DisableContentTypeInference bool
+
+	// DisallowUnusedMocks fails every test which has declared but never called mocks
+	DisallowUnusedMocks bool
+	// DisallowUnexpectedMockRequests fails every test which makes requests to the mocks
+	// not matching any endpoint declared by the test
+	DisallowUnexpectedMockRequests bool
+
+	// FailedTestsFile is where identifiers of failed tests are saved after the run
+	FailedTestsFile string
+	// RerunFailedFrom points to the file saved by a previous run,
+	// only the tests listed there are executed
+	RerunFailedFrom string
+
+	// StepFrom skips the tests preceding the one with this identifier,
+	// StepOnly runs the single test; variables of the skipped tests must be provided otherwise
+	StepFrom string
+	StepOnly string
+
+	// FailOnSkip makes the run unsuccessful if any test was skipped
+	FailOnSkip bool
+
+	// MaxFailures is the number of the failed tests, e.g. "3", or their percentage of the executed tests,
+	// e.g. "5%", which still make the run successful; the failures are reported anyway
+	MaxFailures string
+
+	// Shuffle runs the tests in random order respecting their dependencies,
+	// the order is reproduced with the same ShuffleSeed, a random seed is used if zero
+	Shuffle     bool
+	ShuffleSeed int64
+
+	// SuiteFixtures are loaded once before all the tests,
+	// their tables are truncated after the tests
+	SuiteFixtures []string
+
+	// Bootstrap loads the tests executed before all the others, e.g. to log in,
+	// the run is aborted if any of them fails
+	Bootstrap testloader.LoaderInterface
+
+	// SummaryHook adjusts the summary once per Run after all the tests and before it's returned,
+	// e.g. to tolerate known flaky failures; it is not called if the run ends with an error
+	SummaryHook func(*models.Summary)
 }
 
 type Runner struct {
@@ -31,9 +118,24 @@ type Runner struct {
 	checkers []checker.CheckerInterface
 
 	config *Config
+
+	// loadedFixtures and loadedInline are the fixtures of the tests executed since the tables were cleaned,
+	// suiteCleaned is true when the tables of the suite fixtures are cleaned as well
+	loadedFixtures []string
+	loadedInline   []string
+	suiteCleaned   bool
+
+	// scenarios count the connections of the tests by file
+	scenarios map[string]*connectionTrace
 }
 
 func New(config *Config, loader testloader.LoaderInterface) *Runner {
+	for _, s := range config.VariablesSources {
+		config.Variables.AddSource(s)
+	}
+	if config.RunID == "" {
+		config.RunID = newRunID()
+	}
 	return &Runner{
 		config: config,
 		loader: loader,
@@ -55,6 +157,7 @@ func (r *Runner) Run() (*models.Summary, error) {
 			Failed:  0,
 			Total:   0,
 		}
+		r.processSummary(s)
 		return s, nil
 	}
 
@@ -63,22 +166,142 @@ func (r *Runner) Run() (*models.Summary, error) {
 		return nil, err
 	}
 
-	client, err := newClient()
+	client, err := newClient(r.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setUpSuite(); err != nil {
+		return nil, err
+	}
+	var s *models.Summary
+	if err = r.runBootstrap(client); err == nil {
+		s, err = r.runTests(loader, client)
+	}
+	if tearDownErr := r.tearDownSuite(); tearDownErr != nil && err == nil {
+		return nil, tearDownErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.processSummary(s)
+	return s, nil
+}
+
+func (r *Runner) processSummary(s *models.Summary) {
+	if r.config.SummaryHook != nil {
+		r.config.SummaryHook(s)
+	}
+}
+
+// setUpSuite loads the fixtures shared by all the tests
+func (r *Runner) setUpSuite() error {
+	if len(r.config.SuiteFixtures) == 0 {
+		return nil
+	}
+	if r.config.FixturesLoader == nil {
+		return errors.New("suite fixtures require fixtures loader")
+	}
+	if err := r.config.FixturesLoader.Load(r.config.SuiteFixtures); err != nil {
+		return fmt.Errorf("unable to load suite fixtures: %s", err.Error())
+	}
+	return nil
+}
+
+// tearDownSuite removes the data of the suite fixtures
+func (r *Runner) tearDownSuite() error {
+	if r.config.FixturesLoader == nil || len(r.config.SuiteFixtures) == 0 {
+		return nil
+	}
+	if _, err := r.config.FixturesLoader.Clean(r.config.SuiteFixtures); err != nil {
+		return fmt.Errorf("unable to clean suite fixtures: %s", err.Error())
+	}
+	return nil
+}
+
+func (r *Runner) runTests(loader <-chan models.TestInterface, client *http.Client) (*models.Summary, error) {
+	var err error
+	var rerunTests map[string]bool
+	if r.config.RerunFailedFrom != "" {
+		rerunTests, err = loadFailedTests(r.config.RerunFailedFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	budget, err := parseMaxFailures(r.config.MaxFailures)
 	if err != nil {
 		return nil, err
 	}
 
+	if r.config.Shuffle {
+		if loader, err = r.shuffle(loader); err != nil {
+			return nil, err
+		}
+	} else if loader, err = r.orderByDependencies(loader); err != nil {
+		return nil, err
+	}
+
+	steps := newStepFilter(r.config.StepFrom, r.config.StepOnly)
+
 	totalTests := 0
 	failedTests := 0
+	var failedIDs []string
+	var skippedIDs []string
+	var skippedTests []models.TestInterface
+	xfailedTests := 0
+	var xpassedIDs []string
+	errorsByCategory := make(map[models.ErrorCategory]int)
+
+	skip := func(v models.TestInterface) bool {
+		if (rerunTests != nil && !rerunTests[testID(v)]) || steps.skip(v) {
+			skippedIDs = append(skippedIDs, testID(v))
+			skippedTests = append(skippedTests, v)
+			return true
+		}
+		return false
+	}
+
+	// the tests are selected up front only for the outputs showing the progress of the run
+	if totals := r.totalOutputs(); len(totals) > 0 {
+		var selected []models.TestInterface
+		for v := range loader {
+			if !skip(v) {
+				selected = append(selected, v)
+			}
+		}
+		for _, o := range totals {
+			o.SetTotal(len(selected))
+		}
+		ch := make(chan models.TestInterface, len(selected))
+		for _, v := range selected {
+			ch <- v
+		}
+		close(ch)
+		loader = ch
+		skip = func(models.TestInterface) bool { return false }
+	}
 
 	for v := range loader {
+		if skip(v) {
+			continue
+		}
 		testResult, err := r.executeTest(v, client)
 		if err != nil {
 			return nil, err
 		}
 		totalTests++
-		if len(testResult.Errors) > 0 {
+		switch testResult.Status() {
+		case models.StatusFailed:
 			failedTests++
+			failedIDs = append(failedIDs, testID(v))
+			for _, e := range testResult.Errors {
+				errorsByCategory[errorCategory(e)]++
+			}
+		case models.StatusXFail:
+			xfailedTests++
+		case models.StatusXPass:
+			xpassedIDs = append(xpassedIDs, testID(v))
 		}
 		for _, o := range r.output {
 			if err := o.Process(v, testResult); err != nil {
@@ -87,70 +310,226 @@ func (r *Runner) Run() (*models.Summary, error) {
 		}
 	}
 
+	if err := steps.check(); err != nil {
+		return nil, err
+	}
+
+	if r.config.FailedTestsFile != "" {
+		if err := saveFailedTests(r.config.FailedTestsFile, failedIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	s := &models.Summary{
-		Success: failedTests == 0,
+		Success: failedTests == 0 && !(r.config.FailOnSkip && len(skippedIDs) > 0),
 		Failed:  failedTests,
 		Total:   totalTests,
+		Skipped: skippedIDs,
+		XFailed: xfailedTests,
+		XPassed: xpassedIDs,
+
+		SkippedTests:     skippedTests,
+		ErrorsByCategory: errorsByCategory,
+	}
+	if budget != nil {
+		allowed := budget.allowed(totalTests)
+		s.MaxFailures = &allowed
+		s.Success = failedTests <= allowed && !(r.config.FailOnSkip && len(skippedIDs) > 0)
 	}
 
 	return s, nil
 }
 
+func (r *Runner) totalOutputs() []output.TotalInterface {
+	var totals []output.TotalInterface
+	for _, o := range r.output {
+		if o, ok := o.(output.TotalInterface); ok {
+			totals = append(totals, o)
+		}
+	}
+	return totals
+}
+
 func (r *Runner) executeTest(v models.TestInterface, client *http.Client) (*models.Result, error) {
+	ctx, span := r.startSpan(context.Background(), "test "+testID(v))
+	start := time.Now()
+	client, scenario := r.scenarioClient(v, client)
+	result, err := r.execute(ctx, v, client)
+	if result != nil {
+		result.Duration = time.Since(start)
+		if reuse := v.ConnectionReuse(); reuse != nil &&
+			(reuse.ScenarioMaxConnections != nil || reuse.ScenarioMaxTLSHandshakes != nil) {
+			result.ScenarioConnections = scenario.counts()
+			errs := checkScenarioConnectionReuse(reuse, result.ScenarioConnections)
+			result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryConnections, errs)...)
+		}
+	}
+	endTestSpan(span, v, result, err)
+	return result, err
+}
+
+func (r *Runner) execute(ctx context.Context, v models.TestInterface, client *http.Client) (*models.Result, error) {
 
 	r.config.Variables.Load(v.GetVariables())
 	v = r.config.Variables.Apply(v)
 
 	// load fixtures
-	if r.config.FixturesLoader != nil && v.Fixtures() != nil {
-		if err := r.config.FixturesLoader.Load(v.Fixtures()); err != nil {
+	if r.config.FixturesLog != nil {
+		r.config.FixturesLog.Reset()
+	}
+	_, span := r.startSpan(ctx, "fixtures")
+	cleanups, err := r.prepareFixtures(v)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	var fixturesLog string
+	if r.config.FixturesLog != nil {
+		fixturesLog = r.config.FixturesLog.String()
+	}
+
+	if err := r.loadMocks(ctx, v); err != nil {
+		return nil, err
+	}
+
+	// launch script in cmd interface
+	if v.BeforeScriptPath() != "" {
+		if err := cmd_runner.CmdRun(v.BeforeScriptPath(), v.BeforeScriptTimeout()); err != nil {
 			return nil, err
 		}
 	}
 
-	// reset mocks
+	// make pause
+	pause := v.Pause()
+	if pause > 0 {
+		time.Sleep(pause)
+		fmt.Printf("Sleep %s before requests\n", pause)
+	}
+
+	// the failed request is sent again with the same fixtures, the mocks are reloaded
+	policy := v.GetRetryPolicy()
+	var result *models.Result
+	var bodyStr string
+	var attempts []models.AttemptResult
+	for attempt := 1; ; attempt++ {
+		if result, bodyStr, err = r.sendAndCheck(ctx, v, client); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, models.AttemptResult{ResponseStatus: result.ResponseStatus, Errors: result.Errors})
+		result.Attempts = attempt
+		result.FixturesCleanup = cleanups
+		result.FixturesLog = fixturesLog
+		if r.hasMocks(v) {
+			result.Mocks = r.config.MocksLoader.Strategies()
+		}
+		if !shouldRetry(policy, attempt, result) {
+			break
+		}
+		time.Sleep(policy.Delay)
+		if err := r.loadMocks(ctx, v); err != nil {
+			return nil, err
+		}
+	}
+	if len(attempts) > 1 {
+		result.AttemptResults = attempts
+	}
+
+	if err := r.setVariablesFromResponse(v, result, bodyStr); err != nil {
+		return nil, err
+	}
+
+	errs := r.setVariablesFromSources(v, result)
+	errs = append(errs, r.captureVariables(v, bodyStr)...)
+	errs = append(errs, r.checkExported(v)...)
+	result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryCapture, errs)...)
+
+	return result, nil
+}
+
+// loadMocks resets the mocks and loads the definitions of the test over the suite ones,
+// so the overrides of the test are reverted before the next test
+func (r *Runner) loadMocks(ctx context.Context, v models.TestInterface) error {
 	if r.config.Mocks != nil {
 		// prevent deriving the definition from previous test
 		r.config.Mocks.ResetDefinitions()
 		r.config.Mocks.ResetRunningContext()
 	}
 
-	// load mocks
-	if r.config.MocksLoader != nil && v.ServiceMocks() != nil {
-		if err := r.config.MocksLoader.Load(v.ServiceMocks()); err != nil {
-			return nil, err
+	if r.hasMocks(v) {
+		_, span := r.startSpan(ctx, "mocks")
+		err := r.config.MocksLoader.LoadForRequest(v.ServiceMocks(), v.Headers())
+		endSpan(span, err)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// launch script in cmd interface
-	if v.BeforeScriptPath() != "" {
-		if err := cmd_runner.CmdRun(v.BeforeScriptPath(), v.BeforeScriptTimeout()); err != nil {
-			return nil, err
+func (r *Runner) hasMocks(v models.TestInterface) bool {
+	return r.config.MocksLoader != nil && (v.ServiceMocks() != nil || r.config.MocksLoader.HasSuiteMocks())
+}
+
+// sendAndCheck sends the request of the test and checks the response,
+// the response body is returned as received to set the variables from it
+func (r *Runner) sendAndCheck(ctx context.Context, v models.TestInterface, client *http.Client) (*models.Result, string, error) {
+	var err error
+
+	// the same idempotency key is sent with the repeated request
+	idempotency := v.Idempotency()
+	var idempotencyKey string
+	if idempotency != nil {
+		if idempotencyKey, err = newIdempotencyKey(idempotency); err != nil {
+			return nil, "", err
 		}
 	}
 
-	// make pause
-	pause := v.Pause()
-	if pause > 0 {
-		time.Sleep(time.Duration(pause) * time.Second)
-		fmt.Printf("Sleep %ds before requests\n", pause)
+	// the connections of all the requests of the test are counted after the warm-up
+	var connections *connectionTrace
+	if reuse := v.ConnectionReuse(); reuse != nil {
+		if err := warmUp(r.config, v, client, reuse.WarmUp); err != nil {
+			return nil, "", err
+		}
+		client, connections = traceConnections(client)
 	}
 
-	req, err := newRequest(r.config.Host, v)
+	req, err := newRequest(r.config, v)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if idempotency != nil {
+		req.Header.Set(idempotency.Header, idempotencyKey)
 	}
 
-	resp, err := client.Do(req)
+	requestCtx, span := r.startSpan(ctx, "request")
+	var continued *continueTrace
+	if v.ExpectContinue() {
+		req.Header.Set("Expect", "100-continue")
+		requestCtx, continued = traceContinue(requestCtx)
+	}
+	span.SetAttributes(map[string]string{
+		"http.method": req.Method,
+		"http.url":    req.URL.String(),
+	})
+	if v.MaxDbQueries() != nil && r.config.QueryCounter != nil {
+		r.config.QueryCounter.Reset()
+	}
+	start := time.Now()
+	resp, err := client.Do(req.WithContext(requestCtx))
+	if err == nil {
+		span.SetAttributes(map[string]string{"http.status_code": strconv.Itoa(resp.StatusCode)})
+	}
+	endSpan(span, err)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := readBody(resp, v.CheckContentLength())
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	_ = resp.Body.Close()
+	responseTime := time.Since(start)
 
 	bodyStr := string(body)
 
@@ -162,40 +541,177 @@ func (r *Runner) executeTest(v models.TestInterface, client *http.Client) (*mode
 		ResponseContentType: resp.Header.Get("Content-Type"),
 		ResponseStatusCode:  resp.StatusCode,
 		ResponseStatus:      resp.Status,
+		ResponseStatusText:  statusText(resp),
+		ResponseProto:       resp.Proto,
 		ResponseHeaders:     resp.Header,
+		ResponseTime:        responseTime,
 		Test:                v,
 	}
 
+	// the queries are counted before the other requests of the test
+	result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryDbQueries, r.checkDbQueries(v))...)
+
+	if v.CheckContentLength() {
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryContentLength, checkContentLength(req, resp, body))...)
+	}
+
+	if continued != nil {
+		result.Continue = continued.result()
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryContinue, checkContinue(result.Continue, resp))...)
+	}
+
+	// the items of all the pages are checked as the response body
+	if pagination := v.Pagination(); pagination != nil && resp.StatusCode == http.StatusOK {
+		combined, errs, err := fetchPages(client, req, bodyStr, pagination)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(errs) == 0 {
+			result.ResponseBody = combined
+			result.ResponseContentType = "application/json"
+		}
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryPagination, errs)...)
+	}
+
+	if idempotency != nil {
+		errs, err := r.checkIdempotency(v, client, idempotency, idempotencyKey, resp, bodyStr)
+		if err != nil {
+			return nil, "", err
+		}
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryIdempotency, errs)...)
+	}
+
+	if repeat := v.Repeat(); repeat != nil {
+		repeatResult, errs, err := r.checkRepeat(v, client, repeat, idempotencyKey, resp)
+		if err != nil {
+			return nil, "", err
+		}
+		result.Repeat = repeatResult
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryRepeat, errs)...)
+	}
+
+	if caching := v.Caching(); caching != nil {
+		errs, err := r.checkCaching(v, client, caching, resp)
+		if err != nil {
+			return nil, "", err
+		}
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryCaching, errs)...)
+	}
+
+	if r.config.ParityHost != "" {
+		errs, err := r.checkParity(v, client, resp, bodyStr)
+		if err != nil {
+			return nil, "", err
+		}
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryParity, errs)...)
+	}
+
+	if load := v.Load(); load != nil {
+		loadResult, errs, err := r.runLoad(v, client, load, resp.StatusCode)
+		if err != nil {
+			return nil, "", err
+		}
+		result.Load = loadResult
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryLoad, errs)...)
+	}
+
+	if connections != nil {
+		result.Connections = connections.counts()
+		errs := checkConnectionReuse(v.ConnectionReuse(), result.Connections)
+		result.Errors = append(result.Errors, categorizeErrors(models.ErrorCategoryConnections, errs)...)
+	}
+
 	if r.config.Mocks != nil {
 		errs := r.config.Mocks.EndRunningContext()
-		result.Errors = append(result.Errors, errs...)
+		if r.config.DisallowUnusedMocks || v.DisallowUnusedMocks() {
+			errs = append(errs, r.config.Mocks.CheckUnusedEndpoints()...)
+		}
+		if r.config.DisallowUnexpectedMockRequests || v.DisallowUnexpectedMockRequests() {
+			errs = append(errs, r.config.Mocks.CheckUnexpectedRequests()...)
+		}
+		for _, e := range errs {
+			category := models.ErrorCategoryMock
+			if mocks.IsUpstreamError(e) {
+				category = models.ErrorCategoryUpstream
+			}
+			result.Errors = append(result.Errors, models.NewCheckError(category, e))
+		}
+	}
+
+	checkers := r.checkers
+	var stopOnFailure bool
+	if order := v.ChecksOrder(); order != nil {
+		if checkers, err = orderCheckers(r.checkers, order.Order); err != nil {
+			return nil, "", err
+		}
+		stopOnFailure = order.StopOnFailure
 	}
 
-	for _, c := range r.checkers {
+	checksCtx, checksSpan := r.startSpan(ctx, "checks")
+	for _, c := range checkers {
+		if stopOnFailure && len(result.Errors) > 0 {
+			break
+		}
+		_, span := r.startSpan(checksCtx, checkerSpanName(c))
 		errs, err := c.Check(v, &result)
 		if err != nil {
-			return nil, err
+			endSpan(span, err)
+			endSpan(checksSpan, err)
+			return nil, "", err
 		}
+		if categorized, ok := c.(checker.CategorizedChecker); ok {
+			errs = categorizeErrors(categorized.Category(), errs)
+		}
+		for _, e := range errs {
+			span.RecordError(e)
+		}
+		span.End()
 		result.Errors = append(result.Errors, errs...)
 	}
+	checksSpan.End()
 
-	if err := r.setVariablesFromResponse(v, result.ResponseContentType, bodyStr, resp.StatusCode); err != nil {
-		return nil, err
+	return &result, bodyStr, nil
+}
+
+// categorizeErrors sets the category of the errors which have none
+func categorizeErrors(category models.ErrorCategory, errs []error) []error {
+	for i, e := range errs {
+		if _, ok := e.(*models.CheckError); !ok {
+			errs[i] = models.NewCheckError(category, e)
+		}
 	}
+	return errs
+}
+
+// errorCategory returns the category the error is counted by in the summary
+func errorCategory(err error) models.ErrorCategory {
+	if e, ok := err.(*models.CheckError); ok && e.GetCategory() != "" {
+		return e.GetCategory()
+	}
+	return models.ErrorCategoryOther
+}
 
-	return &result, nil
+// statusText returns the reason phrase of the response status line
+func statusText(resp *http.Response) string {
+	return strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" ")
 }
 
-func (r *Runner) setVariablesFromResponse(t models.TestInterface, contentType, body string, statusCode int) error {
+// setVariablesFromResponse sets variables_to_set of the response status from the response body
+func (r *Runner) setVariablesFromResponse(t models.TestInterface, result *models.Result, body string) error {
 
 	varTemplates := t.GetVariablesToSet()
 	if varTemplates == nil {
 		return nil
 	}
 
-	isJson := strings.Contains(contentType, "json") && body != ""
+	bodyVars, _ := variables.SplitSources(varTemplates[result.ResponseStatusCode])
+	if len(bodyVars) == 0 {
+		return nil
+	}
 
-	vars, err := variables.FromResponse(varTemplates[statusCode], body, isJson)
+	isJson := strings.Contains(result.ResponseContentType, "json") && body != ""
+
+	vars, err := variables.FromResponse(bodyVars, body, isJson)
 	if err != nil {
 		return err
 	}
@@ -208,3 +724,20 @@ func (r *Runner) setVariablesFromResponse(t models.TestInterface, contentType, b
 
 	return nil
 }
+
+// setVariablesFromSources sets variables_to_set of the response status from the response headers,
+// cookies and the db response, the absent values fail the test
+func (r *Runner) setVariablesFromSources(t models.TestInterface, result *models.Result) []error {
+	_, sourceVars := variables.SplitSources(t.GetVariablesToSet()[result.ResponseStatusCode])
+	if len(sourceVars) == 0 {
+		return nil
+	}
+
+	vars, err := variables.FromSources(sourceVars, result.ResponseHeaders, result.DbResponse)
+	if err != nil {
+		return []error{fmt.Errorf("unable to set variables: %s", err.Error())}
+	}
+
+	r.config.Variables.Merge(vars)
+	return nil
+}
@@ -3,8 +3,19 @@ package runner
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/checker/response_status"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
 )
 
 func TestDontFollowRedirects(t *testing.T) {
@@ -17,6 +28,219 @@ func TestDontFollowRedirects(t *testing.T) {
 	})
 }
 
+type recordingTransport struct {
+	requests []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req.Method+" "+req.URL.Host)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCustomTransportShouldSendRequests(t *testing.T) {
+	srv := testServerRedirect()
+	defer srv.Close()
+
+	transport := &recordingTransport{}
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:    srv,
+		TestsDir:  filepath.Join("testdata", "dont-follow-redirects"),
+		Transport: transport,
+	})
+	assert.Equal(t, []string{"GET " + srv.Listener.Addr().String()}, transport.requests, "redirects must not be followed by the custom transport")
+}
+
+func TestSuiteFixturesShouldBeLoadedOnceAndCleaned(t *testing.T) {
+	srv := testServerRedirect()
+	defer srv.Close()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^TRUNCATE TABLE "settings" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT INTO "settings"`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"name":"mode","value":"test"}`))
+	mock.ExpectExec("DO").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "settings"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`^TRUNCATE TABLE "settings" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:        srv,
+		TestsDir:      filepath.Join("testdata", "dont-follow-redirects"),
+		DB:            db,
+		FixturesDir:   filepath.Join("testdata", "suite-fixtures"),
+		SuiteFixtures: []string{"suite"},
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSkipFixturesShouldCleanTablesAndRestoreSuiteFixtures(t *testing.T) {
+	ordersRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/settings" {
+			_, _ = w.Write([]byte(`{"mode": "test"}`))
+			return
+		}
+		ordersRequests++
+		if ordersRequests == 1 {
+			_, _ = w.Write([]byte(`[{"id": 1}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectLoad := func(table, json string) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^TRUNCATE TABLE "` + table + `" CASCADE$`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`^INSERT INTO "` + table + `"`).
+			WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(json))
+		mock.ExpectExec("DO").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+	}
+	expectTruncate := func(table string) {
+		mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM "` + table + `"$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec(`^TRUNCATE TABLE "` + table + `" CASCADE$`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	expectLoad("settings", `{"name":"mode","value":"test"}`)
+	expectLoad("orders", `{"id":1,"status":"new"}`)
+	// the test skipping fixtures
+	expectTruncate("settings")
+	expectTruncate("orders")
+	// the suite fixtures are restored for the next test
+	expectLoad("settings", `{"name":"mode","value":"test"}`)
+	expectTruncate("settings")
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:        srv,
+		TestsDir:      filepath.Join("testdata", "skip-fixtures", "tests"),
+		DB:            db,
+		FixturesDir:   filepath.Join("testdata", "skip-fixtures", "fixtures"),
+		SuiteFixtures: []string{"suite"},
+
+		CheckFixturesCleanup: true,
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInlineFixturesShouldBeLoadedWithFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1}]`))
+	}))
+	defer srv.Close()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^TRUNCATE TABLE "settings" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`^TRUNCATE TABLE "orders" CASCADE$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`^INSERT INTO "settings"`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"name":"mode","value":"test"}`))
+	mock.ExpectQuery(`^INSERT INTO "orders" AS orders_table_gonkey \("id", "status"\) VALUES \(1, 'new'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"json"}).AddRow(`{"id":1,"status":"new"}`))
+	mock.ExpectExec("DO").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:      srv,
+		TestsDir:    filepath.Join("testdata", "inline-fixtures"),
+		DB:          db,
+		FixturesDir: filepath.Join("testdata", "suite-fixtures"),
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSummaryShouldCountErrorsByCategory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "error-categories")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	r.AddCheckers(response_status.NewChecker())
+
+	summary, err := r.Run()
+	require.NoError(t, err)
+	assert.Equal(t, map[models.ErrorCategory]int{
+		models.ErrorCategoryBody:   1,
+		models.ErrorCategoryStatus: 1,
+	}, summary.ErrorsByCategory)
+}
+
+func TestSummaryHookShouldAdjustSummaryOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	calls := 0
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+			SummaryHook: func(s *models.Summary) {
+				calls++
+				// tolerate a single failure
+				s.Success = s.Failed <= 1
+			},
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "error-categories")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+
+	summary, err := r.Run()
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, summary.Failed)
+	assert.True(t, summary.Success)
+}
+
+func TestEnvFileShouldNotOverrideEnvironment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/from-file/real" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	require.NoError(t, os.Setenv("GONKEY_TEST_ENV_KEPT", "real"))
+	defer os.Unsetenv("GONKEY_TEST_ENV_KEPT")
+	defer os.Unsetenv("GONKEY_TEST_ENV_PATH")
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "env-file"),
+		EnvFile:  filepath.Join("testdata", "env-file", "test.env"),
+	})
+}
+
 func testServerRedirect() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/redirect-url", http.StatusFound)
@@ -1,18 +1,44 @@
 package runner
 
 import (
+	"bytes"
 	"database/sql"
+	"io"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
+
 	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/checker/response_body_forbidden"
+	"github.com/lamoda/gonkey/checker/response_body_hash"
+	"github.com/lamoda/gonkey/checker/response_body_matches"
+	"github.com/lamoda/gonkey/checker/response_cookies"
 	"github.com/lamoda/gonkey/checker/response_db"
+	"github.com/lamoda/gonkey/checker/response_fields"
 	"github.com/lamoda/gonkey/checker/response_header"
+	"github.com/lamoda/gonkey/checker/response_json_schema"
+	"github.com/lamoda/gonkey/checker/response_keys"
+	"github.com/lamoda/gonkey/checker/response_merge_patch"
+	"github.com/lamoda/gonkey/checker/response_ndjson"
+	"github.com/lamoda/gonkey/checker/response_problem"
+	"github.com/lamoda/gonkey/checker/response_redis"
+	"github.com/lamoda/gonkey/checker/response_status"
+	"github.com/lamoda/gonkey/checker/response_structure"
+	"github.com/lamoda/gonkey/checker/response_time"
+	"github.com/lamoda/gonkey/checker/response_validation"
 	"github.com/lamoda/gonkey/fixtures"
 	"github.com/lamoda/gonkey/mocks"
 	"github.com/lamoda/gonkey/output/allure_report"
+	"github.com/lamoda/gonkey/output/failures_dir"
+	"github.com/lamoda/gonkey/output/junit_xml"
+	"github.com/lamoda/gonkey/output/tap"
 	testingOutput "github.com/lamoda/gonkey/output/testing"
+	"github.com/lamoda/gonkey/testloader"
 	"github.com/lamoda/gonkey/testloader/yaml_file"
 	"github.com/lamoda/gonkey/variables"
 )
@@ -23,6 +49,76 @@ type RunWithTestingParams struct {
 	Mocks       *mocks.Mocks
 	FixturesDir string
 	DB          *sql.DB
+	DBDriver    string // fixtures dialect (see fixtures.RegisterDialect), postgres by default
+	DBPool      DBPoolConfig
+	Redis       response_redis.Client
+
+	// MongoDB is the database the fixtures are loaded into instead of DB
+	MongoDB *mongo.Database
+
+	// MockTemplatesDir contains mock definition templates, see mocks.Loader.LoadTemplates
+	MockTemplatesDir string
+	// SuiteMocksFile contains the mocks of all the tests, the mocks of the test override them,
+	// see mocks.Loader.SetSuiteMocks
+	SuiteMocksFile string
+	// MockRetryJitter and MockRetryJitterSeed are the defaults of proxy mocks, see mocks.Loader.SetRetryJitter
+	MockRetryJitter     string
+	MockRetryJitterSeed int64
+
+	// FixturesProgress is called while the fixtures are loaded
+	FixturesProgress func(fixtures.Progress)
+	// SuiteFixtures are loaded once before the tests and cleaned after them
+	SuiteFixtures []string
+	// CheckFixturesCleanup fails the cleanup which deletes other number of rows than the fixtures have
+	CheckFixturesCleanup bool
+	// BootstrapTests is a file or a directory with the tests executed once before the others
+	BootstrapTests string
+
+	VariablesSources []variables.Source
+	// Generators are registered in addition to the built-in ones, see variables.Variables.AddGenerator
+	Generators map[string]variables.Generator
+	// EnvFile seeds the environment before the run, already set variables are kept
+	EnvFile string
+
+	DisallowUnusedMocks     bool
+	CanonicalizeRequestBody bool
+	FailOnSkip              bool
+
+	// DisallowUnexpectedMockRequests fails the tests which make requests not matching their mocks
+	DisallowUnexpectedMockRequests bool
+
+	// Shuffle runs the tests in random order, ShuffleSeed reproduces the order printed by the previous run
+	Shuffle     bool
+	ShuffleSeed int64
+
+	// UserAgent and DisableRequestIdentification, see Config
+	UserAgent                    string
+	DisableRequestIdentification bool
+
+	// QueryCounter counts the DB queries of the service for the tests with maxDbQueries
+	QueryCounter QueryCounter
+
+	// ParityHost and ParityIgnore, see Config
+	ParityHost   string
+	ParityIgnore []string
+
+	// WarnOnDuplicateNames prints the tests sharing a name instead of failing the run
+	WarnOnDuplicateNames bool
+
+	// UpdateSnapshots rewrites the structure snapshots by the actual responses instead of checking them
+	UpdateSnapshots bool
+
+	// DisableContentTypeInference sends request bodies without Content-Type unless the test sets it
+	DisableContentTypeInference bool
+
+	// Transport replaces the default transport of the tests requests, e.g. to trace or record them
+	Transport http.RoundTripper
+	// EnableHTTP2 makes the default transport negotiate HTTP/2 with HTTPS servers
+	EnableHTTP2 bool
+	// HTTPClient replaces the default client of the tests requests, e.g. to set a timeout or to follow redirects
+	HTTPClient *http.Client
+	// Tracer starts the spans of the tests and their phases
+	Tracer Tracer
 }
 
 // RunWithTesting is a helper function the wraps the common Run and provides simple way
@@ -31,29 +127,122 @@ func RunWithTesting(t *testing.T, params *RunWithTestingParams) {
 	var mocksLoader *mocks.Loader
 	if params.Mocks != nil {
 		mocksLoader = mocks.NewLoader(params.Mocks)
+		mocksLoader.SetRetryJitter(params.MockRetryJitter, params.MockRetryJitterSeed)
+		if params.MockTemplatesDir != "" {
+			if err := mocksLoader.LoadTemplates(params.MockTemplatesDir); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if params.SuiteMocksFile != "" {
+			if err := mocksLoader.LoadSuiteMocks(params.SuiteMocksFile); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if params.EnvFile != "" {
+		if err := godotenv.Load(params.EnvFile); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	debug := os.Getenv("GONKEY_DEBUG") != ""
+	// the debug output of the fixtures is printed and reported with the test
+	var fixturesLog *bytes.Buffer
+	var fixturesDebugOutput io.Writer
+	if debug {
+		fixturesLog = &bytes.Buffer{}
+		fixturesDebugOutput = io.MultiWriter(os.Stdout, fixturesLog)
+	}
+
+	params.DBPool.Apply(params.DB)
 
-	var fixturesLoader *fixtures.Loader
+	var fixturesLoader fixtures.LoaderInterface
+	if params.DB != nil && params.MongoDB != nil {
+		t.Fatal("fixtures can be loaded either into DB or into MongoDB")
+	}
+	if params.MongoDB != nil {
+		fixturesLoader = fixtures.NewMongoLoader(&fixtures.MongoConfig{
+			Location: params.FixturesDir,
+			Database: params.MongoDB,
+			Debug:    debug,
+
+			DebugOutput:  fixturesDebugOutput,
+			CheckCleanup: params.CheckFixturesCleanup,
+		})
+	}
 	if params.DB != nil {
 		fixturesLoader = fixtures.NewLoader(&fixtures.Config{
 			Location: params.FixturesDir,
 			DB:       params.DB,
 			Debug:    debug,
+			Driver:   params.DBDriver,
+
+			DebugOutput:  fixturesDebugOutput,
+			OnProgress:   params.FixturesProgress,
+			CheckCleanup: params.CheckFixturesCleanup,
 		})
 	}
 
+	// the variables of the file override the environment and are overridden by the tests
+	vars := variables.New()
+	for name, fn := range params.Generators {
+		vars.AddGenerator(name, fn)
+	}
+	if envFile := os.Getenv("GONKEY_ENV_FILE"); envFile != "" {
+		if err := vars.LoadFromFile(envFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	yamlLoader := yaml_file.NewLoader(params.TestsDir)
 	yamlLoader.SetFileFilter(os.Getenv("GONKEY_FILE_FILTER"))
+	yamlLoader.SetWarnOnDuplicateNames(params.WarnOnDuplicateNames)
+
+	var bootstrapLoader testloader.LoaderInterface
+	if params.BootstrapTests != "" {
+		bootstrapLoader = yaml_file.NewLoader(params.BootstrapTests)
+	}
 
 	r := New(
 		&Config{
 			Host:           params.Server.URL,
+			Transport:      params.Transport,
+			EnableHTTP2:    params.EnableHTTP2,
+			HTTPClient:     params.HTTPClient,
+			Tracer:         params.Tracer,
 			Mocks:          params.Mocks,
 			MocksLoader:    mocksLoader,
 			FixturesLoader: fixturesLoader,
-			Variables:      variables.New(),
+			FixturesLog:    fixturesLog,
+			Variables:      vars,
+
+			VariablesSources: params.VariablesSources,
+
+			DisallowUnusedMocks:     params.DisallowUnusedMocks,
+			CanonicalizeRequestBody: params.CanonicalizeRequestBody,
+
+			DisallowUnexpectedMockRequests: params.DisallowUnexpectedMockRequests,
+
+			DisableContentTypeInference: params.DisableContentTypeInference,
+
+			UserAgent:                    params.UserAgent,
+			DisableRequestIdentification: params.DisableRequestIdentification,
+
+			QueryCounter: params.QueryCounter,
+			ParityHost:   params.ParityHost,
+			ParityIgnore: params.ParityIgnore,
+
+			StepFrom: os.Getenv("GONKEY_STEP_FROM"),
+			StepOnly: os.Getenv("GONKEY_STEP_ONLY"),
+
+			FailOnSkip: params.FailOnSkip,
+
+			Shuffle:     params.Shuffle,
+			ShuffleSeed: params.ShuffleSeed,
+
+			SuiteFixtures: params.SuiteFixtures,
+			Bootstrap:     bootstrapLoader,
 		},
 		yamlLoader,
 	)
@@ -66,15 +255,70 @@ func RunWithTesting(t *testing.T, params *RunWithTestingParams) {
 		r.AddOutput(allureOutput)
 	}
 
+	var junitOutput *junit_xml.JUnitXMLOutput
+	if os.Getenv("GONKEY_JUNIT_REPORT") != "" {
+		junitOutput = junit_xml.NewOutput(os.Getenv("GONKEY_JUNIT_REPORT"))
+		r.AddOutput(junitOutput)
+	}
+
+	if os.Getenv("GONKEY_FAILURES_DIR") != "" {
+		failuresOutput, err := failures_dir.NewOutput(os.Getenv("GONKEY_FAILURES_DIR"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.AddOutput(failuresOutput)
+	}
+
+	var tapOutput *tap.TAPOutput
+	if os.Getenv("GONKEY_TAP_REPORT") != "" {
+		tapFile, err := os.Create(os.Getenv("GONKEY_TAP_REPORT"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tapFile.Close()
+		tapOutput = tap.NewOutput(tapFile, os.Getenv("GONKEY_TAP_VERBOSE") != "")
+		r.AddOutput(tapOutput)
+	}
+
 	r.AddCheckers(response_body.NewChecker())
+	r.AddCheckers(response_body_hash.NewChecker())
+	r.AddCheckers(response_body_matches.NewChecker())
+	r.AddCheckers(response_body_forbidden.NewChecker())
+	r.AddCheckers(response_fields.NewChecker())
+	r.AddCheckers(response_keys.NewChecker())
+	r.AddCheckers(response_ndjson.NewChecker())
+	r.AddCheckers(response_json_schema.NewChecker())
+	r.AddCheckers(response_merge_patch.NewChecker())
+	r.AddCheckers(response_validation.NewChecker())
+	r.AddCheckers(response_structure.NewChecker(params.UpdateSnapshots))
 	r.AddCheckers(response_header.NewChecker())
+	r.AddCheckers(response_cookies.NewChecker())
+	r.AddCheckers(response_status.NewChecker())
+	r.AddCheckers(response_problem.NewChecker())
+	r.AddCheckers(response_time.NewChecker())
 
 	if params.DB != nil {
-		r.AddCheckers(response_db.NewChecker(params.DB))
+		r.AddCheckers(response_db.NewCheckerForDriver(params.DB, params.DBDriver))
+	}
+
+	if params.Redis != nil {
+		r.AddCheckers(response_redis.NewChecker(params.Redis))
 	}
 
-	_, err := r.Run()
+	summary, err := r.Run()
 	if err != nil {
 		t.Fatal(err)
 	}
+	if tapOutput != nil {
+		tapOutput.ShowSummary(summary)
+	}
+	if junitOutput != nil {
+		junitOutput.ShowSummary(summary)
+		if err := junitOutput.Finalize(); err != nil {
+			t.Error(err)
+		}
+	}
+	if params.FailOnSkip && len(summary.Skipped) > 0 {
+		t.Errorf("%d test(s) skipped:\n%s", len(summary.Skipped), strings.Join(summary.Skipped, "\n"))
+	}
 }
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// shuffle returns the loaded tests in random order, the seed is printed to reproduce the order
+func (r *Runner) shuffle(loader <-chan models.TestInterface) (<-chan models.TestInterface, error) {
+	var tests []models.TestInterface
+	for v := range loader {
+		tests = append(tests, v)
+	}
+
+	seed := r.config.ShuffleSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	fmt.Printf("Tests are shuffled with seed %d\n", seed)
+
+	shuffled, err := shuffleTests(tests, seed)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan models.TestInterface, len(shuffled))
+	for _, v := range shuffled {
+		ch <- v
+	}
+	close(ch)
+	return ch, nil
+}
+
+// orderByDependencies runs the loaded tests in the declared order,
+// except the tests declared before the ones they depend on
+func (r *Runner) orderByDependencies(loader <-chan models.TestInterface) (<-chan models.TestInterface, error) {
+	var tests []models.TestInterface
+	for v := range loader {
+		tests = append(tests, v)
+	}
+
+	// the dependencies may be out of the tests selected to run, e.g. in the other files
+	for _, unknown := range unknownDependencies(tests) {
+		fmt.Printf("Warning: %s, the test is run in the declared order\n", unknown)
+	}
+	ordered, err := dependenciesFirst(tests)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan models.TestInterface, len(ordered))
+	for _, v := range ordered {
+		ch <- v
+	}
+	close(ch)
+	return ch, nil
+}
+
+// shuffleTests randomizes the order of the tests with the seed,
+// a test is still run after the tests it depends on
+func shuffleTests(tests []models.TestInterface, seed int64) ([]models.TestInterface, error) {
+	shuffled := make([]models.TestInterface, len(tests))
+	copy(shuffled, tests)
+	rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	if unknown := unknownDependencies(shuffled); len(unknown) > 0 {
+		return nil, errors.New(unknown[0])
+	}
+	return dependenciesFirst(shuffled)
+}
+
+// unknownDependencies describes the dependencies which are not among the tests
+func unknownDependencies(tests []models.TestInterface) []string {
+	known := make(map[string]bool, len(tests))
+	for _, t := range tests {
+		known[testID(t)] = true
+	}
+	var unknown []string
+	for _, t := range tests {
+		for _, dep := range t.DependsOn() {
+			if !known[dep] {
+				unknown = append(unknown, fmt.Sprintf("test %s depends on unknown test %s", testID(t), dep))
+			}
+		}
+	}
+	return unknown
+}
+
+// dependenciesFirst keeps the order of the tests, but a test is moved after the tests it depends on,
+// the unknown dependencies are ignored
+func dependenciesFirst(tests []models.TestInterface) ([]models.TestInterface, error) {
+	// tests with cases share the identifier, the dependency is on all of them
+	pending := make(map[string]int)
+	for _, t := range tests {
+		pending[testID(t)]++
+	}
+
+	// every next test is the first one in the order whose dependencies are done
+	remaining := make([]models.TestInterface, len(tests))
+	copy(remaining, tests)
+	res := make([]models.TestInterface, 0, len(tests))
+	for len(remaining) > 0 {
+		next := -1
+		for i, t := range remaining {
+			if dependenciesDone(t, pending) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			ids := make([]string, len(remaining))
+			for i, t := range remaining {
+				ids[i] = testID(t)
+			}
+			return nil, fmt.Errorf("tests depend on each other: %s", strings.Join(ids, ", "))
+		}
+		pending[testID(remaining[next])]--
+		res = append(res, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return res, nil
+}
+
+func dependenciesDone(t models.TestInterface, pending map[string]int) bool {
+	for _, dep := range t.DependsOn() {
+		if pending[dep] > 0 {
+			return false
+		}
+	}
+	return true
+}
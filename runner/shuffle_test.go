@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func newShuffleTest(name string, dependsOn ...string) models.TestInterface {
+	return &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{Name: name, DependsOnVal: dependsOn}}
+}
+
+func shuffledIDs(t *testing.T, tests []models.TestInterface, seed int64) []string {
+	shuffled, err := shuffleTests(tests, seed)
+	require.NoError(t, err)
+	ids := make([]string, len(shuffled))
+	for i, v := range shuffled {
+		ids[i] = testID(v)
+	}
+	return ids
+}
+
+func TestShuffleShouldBeReproducibleWithSeed(t *testing.T) {
+	tests := []models.TestInterface{
+		newShuffleTest("a"), newShuffleTest("b"), newShuffleTest("c"), newShuffleTest("d"), newShuffleTest("e"),
+	}
+
+	first := shuffledIDs(t, tests, 42)
+	assert.Equal(t, first, shuffledIDs(t, tests, 42))
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d", "e"}, first)
+
+	changed := false
+	for seed := int64(1); seed < 10 && !changed; seed++ {
+		changed = !assert.ObjectsAreEqual(first, shuffledIDs(t, tests, seed))
+	}
+	assert.True(t, changed, "order must depend on the seed")
+}
+
+func TestShuffleShouldRunDependenciesFirst(t *testing.T) {
+	tests := []models.TestInterface{
+		newShuffleTest("login"),
+		newShuffleTest("create", "login"),
+		newShuffleTest("get", "create", "login"),
+		newShuffleTest("health"),
+	}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		ids := shuffledIDs(t, tests, seed)
+		position := make(map[string]int)
+		for i, id := range ids {
+			position[id] = i
+		}
+		assert.Less(t, position["login"], position["create"], "seed %d: %v", seed, ids)
+		assert.Less(t, position["create"], position["get"], "seed %d: %v", seed, ids)
+	}
+}
+
+func TestShuffleShouldFailOnUnknownAndCyclicDependencies(t *testing.T) {
+	_, err := shuffleTests([]models.TestInterface{newShuffleTest("a", "b")}, 1)
+	assert.EqualError(t, err, "test a depends on unknown test b")
+
+	_, err = shuffleTests([]models.TestInterface{newShuffleTest("a", "b"), newShuffleTest("b", "a")}, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tests depend on each other")
+}
+
+func TestDependenciesFirstShouldKeepOrderOfIndependentTests(t *testing.T) {
+	ordered, err := dependenciesFirst([]models.TestInterface{
+		newShuffleTest("get", "create"),
+		newShuffleTest("health"),
+		newShuffleTest("create"),
+		newShuffleTest("list"),
+	})
+	require.NoError(t, err)
+
+	ids := make([]string, len(ordered))
+	for i, v := range ordered {
+		ids[i] = testID(v)
+	}
+	assert.Equal(t, []string{"health", "create", "get", "list"}, ids)
+}
+
+func TestDependenciesFirstShouldIgnoreUnknownDependencies(t *testing.T) {
+	ordered, err := dependenciesFirst([]models.TestInterface{
+		newShuffleTest("profile", "login"),
+		newShuffleTest("health"),
+	})
+	require.NoError(t, err)
+	require.Len(t, ordered, 2)
+	assert.Equal(t, "profile", testID(ordered[0]))
+	assert.Equal(t, []string{"test profile depends on unknown test login"}, unknownDependencies(ordered))
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// stepFilter selects the tests (steps of the scenario) to run when debugging,
+// the tests are identified the same way as in the failed tests file
+type stepFilter struct {
+	from    string
+	only    string
+	started bool
+	found   bool
+}
+
+func newStepFilter(from, only string) *stepFilter {
+	return &stepFilter{
+		from: from,
+		only: only,
+	}
+}
+
+// skip reports whether the test must not be run
+func (f *stepFilter) skip(t models.TestInterface) bool {
+	id := testID(t)
+	if f.only != "" {
+		if id != f.only {
+			return true
+		}
+		f.found = true
+		return false
+	}
+	if f.from != "" && !f.started {
+		if id != f.from {
+			return true
+		}
+		f.started = true
+		f.found = true
+	}
+	return false
+}
+
+// check returns an error if the requested step is absent
+func (f *stepFilter) check() error {
+	if f.found || (f.from == "" && f.only == "") {
+		return nil
+	}
+	step := f.only
+	if step == "" {
+		step = f.from
+	}
+	return fmt.Errorf("step %q not found", step)
+}
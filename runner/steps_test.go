@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func runSteps(t *testing.T, from, only string) ([]string, error) {
+	srv := testServer()
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+			StepFrom:  from,
+			StepOnly:  only,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "steps")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	var names []string
+	for _, result := range collector.results {
+		names = append(names, result.Test.GetName())
+	}
+	return names, err
+}
+
+func TestStepFromShouldSkipPrecedingTests(t *testing.T) {
+	names, err := runSteps(t, "second", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second", "third"}, names)
+}
+
+func TestStepOnlyShouldRunSingleTest(t *testing.T) {
+	names, err := runSteps(t, "", "second")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second"}, names)
+}
+
+type progressCollector struct {
+	resultsCollector
+	totals []int
+}
+
+func (c *progressCollector) SetTotal(total int) {
+	c.totals = append(c.totals, total)
+}
+
+func TestRunShouldSetTotalOfSelectedTests(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+			StepFrom:  "second",
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "steps")),
+	)
+	collector := &progressCollector{}
+	r.AddOutput(collector)
+
+	summary, err := r.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, collector.totals, "the total must be set once before the tests")
+	assert.Len(t, collector.results, 2)
+	assert.Len(t, summary.Skipped, 1, "the tests selected up front must be skipped the same way")
+}
+
+func TestStepShouldFailWhenNotFound(t *testing.T) {
+	_, err := runSteps(t, "fourth", "")
+	assert.EqualError(t, err, `step "fourth" not found`)
+}
+
+func TestFailOnSkipShouldMakeRunUnsuccessful(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	for _, failOnSkip := range []bool{false, true} {
+		r := New(
+			&Config{
+				Host:       srv.URL,
+				Variables:  variables.New(),
+				StepFrom:   "third",
+				FailOnSkip: failOnSkip,
+			},
+			yaml_file.NewLoader(filepath.Join("testdata", "steps")),
+		)
+
+		summary, err := r.Run()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, summary.Skipped)
+		assert.Equal(t, !failOnSkip, summary.Success)
+	}
+}
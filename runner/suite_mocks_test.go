@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/mocks"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestDisallowUnusedMocksShouldCheckOnlyTestMocks(t *testing.T) {
+	m := mocks.NewNop("books", "users")
+	require.NoError(t, m.Start())
+	defer m.Shutdown()
+
+	// the service gets the books from the books mock
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			return
+		}
+		resp, err := http.Get("http://" + m.Service("books").ServerAddr() + r.URL.Path)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer srv.Close()
+
+	loader := mocks.NewLoader(m)
+	require.NoError(t, loader.LoadSuiteMocks(filepath.Join("testdata", "suite-mocks", "suite.yaml")))
+
+	r := New(
+		&Config{
+			Host:                srv.URL,
+			Mocks:               m,
+			MocksLoader:         loader,
+			Variables:           variables.New(),
+			DisallowUnusedMocks: true,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "suite-mocks", "tests")),
+	)
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+
+	require.Len(t, collector.results, 2)
+	assert.Empty(t, collector.results[0].Errors, "the suite mocks must not be reported as unused")
+	require.Len(t, collector.results[1].Errors, 1)
+	assert.Contains(t, collector.results[1].Errors[0].Error(), "/books/4: mock was never called")
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/models"
+)
+
+// Tracer starts the spans of the tests execution, e.g. an adapter of OpenTelemetry tracer.
+// The span of the test is started with the background context, the spans of its phases
+// (fixtures, mocks, request, checks) with the context of the test span
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced part of the test execution
+type Span interface {
+	SetAttributes(attributes map[string]string)
+	RecordError(err error)
+	End()
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(map[string]string) {}
+
+func (nopSpan) RecordError(error) {}
+
+func (nopSpan) End() {}
+
+// startSpan starts the span with the tracer of the config if any
+func (r *Runner) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if r.config.Tracer == nil {
+		return ctx, nopSpan{}
+	}
+	return r.config.Tracer.Start(ctx, name)
+}
+
+// endSpan records the error if any and ends the span
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// endTestSpan sets the status of the test and records its errors
+func endTestSpan(span Span, t models.TestInterface, result *models.Result, err error) {
+	status := models.StatusFailed
+	if err == nil {
+		status = result.Status()
+	}
+	span.SetAttributes(map[string]string{
+		"gonkey.test.name":   testID(t),
+		"gonkey.test.status": status,
+	})
+	if result != nil {
+		for _, e := range result.Errors {
+			span.RecordError(e)
+		}
+	}
+	endSpan(span, err)
+}
+
+// checkerSpanName names the span of the checker by the category of its errors
+func checkerSpanName(c checker.CheckerInterface) string {
+	if categorized, ok := c.(checker.CategorizedChecker); ok {
+		return "check " + string(categorized.Category())
+	}
+	return "check"
+}
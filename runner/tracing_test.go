@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/checker/response_status"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+type spanPathKey struct{}
+
+type recordedSpan struct {
+	path       string
+	attributes map[string]string
+	errors     []string
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttributes(attributes map[string]string) {
+	for k, v := range attributes {
+		s.attributes[k] = v
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.errors = append(s.errors, err.Error())
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+// recordingTracer names the spans by the path from the root span
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (rt *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	if parent, ok := ctx.Value(spanPathKey{}).(string); ok {
+		name = parent + " > " + name
+	}
+	span := &recordedSpan{path: name, attributes: map[string]string{}}
+	rt.spans = append(rt.spans, span)
+	return context.WithValue(ctx, spanPathKey{}, name), span
+}
+
+func TestTracerShouldStartNestedSpans(t *testing.T) {
+	srv := testServerRedirect()
+	defer srv.Close()
+
+	tracer := &recordingTracer{}
+	r := New(
+		&Config{
+			Host:      srv.URL,
+			Variables: variables.New(),
+			Tracer:    tracer,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", "tracing")),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	r.AddCheckers(response_status.NewChecker())
+
+	_, err := r.Run()
+	require.NoError(t, err)
+
+	var paths []string
+	for _, span := range tracer.spans {
+		assert.True(t, span.ended, "span %s must be ended", span.path)
+		paths = append(paths, span.path)
+	}
+	assert.Equal(t, []string{
+		"test wrong body",
+		"test wrong body > fixtures",
+		"test wrong body > request",
+		"test wrong body > checks",
+		"test wrong body > checks > check body",
+		"test wrong body > checks > check status",
+	}, paths)
+
+	test := tracer.spans[0]
+	assert.Equal(t, "wrong body", test.attributes["gonkey.test.name"])
+	assert.Equal(t, "failed", test.attributes["gonkey.test.status"])
+	assert.NotEmpty(t, test.errors)
+	assert.Equal(t, "302", tracer.spans[2].attributes["http.status_code"])
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func runVariablesToSetTests(t *testing.T, host string, vars *variables.Variables, dir string) *resultsCollector {
+	r := New(
+		&Config{
+			Host:      host,
+			Variables: vars,
+		},
+		yaml_file.NewLoader(filepath.Join("testdata", dir)),
+	)
+	r.AddCheckers(response_body.NewChecker())
+	collector := &resultsCollector{}
+	r.AddOutput(collector)
+
+	_, err := r.Run()
+	require.NoError(t, err)
+	return collector
+}
+
+func testVariablesToSetServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/orders":
+			w.Header().Set("Location", "/orders/42")
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"status": "created"}`))
+		case r.URL.Path == "/orders/42":
+			if c, err := r.Cookie("session"); err != nil || c.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": 42}`))
+		case r.URL.Path == "/health":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVariablesToSetShouldTakeHeadersAndCookies(t *testing.T) {
+	srv := testVariablesToSetServer()
+	defer srv.Close()
+
+	vars := variables.New()
+	collector := runVariablesToSetTests(t, srv.URL, vars, "variables-to-set")
+
+	require.Len(t, collector.results, 2)
+	for _, result := range collector.results {
+		assert.Empty(t, result.Errors)
+	}
+	assert.Equal(t, "/orders/42", collector.results[1].Path)
+	assert.Equal(t, 3, vars.Len())
+}
+
+func TestVariablesToSetShouldFailTestOnMissingHeader(t *testing.T) {
+	srv := testVariablesToSetServer()
+	defer srv.Close()
+
+	vars := variables.New()
+	collector := runVariablesToSetTests(t, srv.URL, vars, "variables-to-set-missing")
+
+	require.Len(t, collector.results, 2, "the run must go on after the test")
+	assert.Equal(t, []error{
+		models.NewCheckError(models.ErrorCategoryCapture,
+			errors.New("unable to set variables: header 'X-Order-Location' doesn't exist in the response")),
+	}, collector.results[0].Errors)
+	assert.Empty(t, collector.results[1].Errors)
+	assert.Equal(t, 0, vars.Len())
+}
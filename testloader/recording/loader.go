@@ -0,0 +1,100 @@
+package recording
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+// Loader turns the recorded sessions into tests expecting the recorded responses
+type Loader struct {
+	testloader.LoaderInterface
+
+	location string
+}
+
+// NewLoader loads the session file or all the YAML session files of the directory
+func NewLoader(location string) *Loader {
+	return &Loader{location: location}
+}
+
+func (l *Loader) Load() (chan models.TestInterface, error) {
+	files, err := sessionFiles(l.location)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []yaml_file.Test
+	for _, file := range files {
+		session, err := ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read session %s: %s", file, err.Error())
+		}
+		for i, exchange := range session.Exchanges {
+			tests = append(tests, exchangeTest(file, i, exchange))
+		}
+	}
+
+	ch := make(chan models.TestInterface)
+	go func() {
+		for i := range tests {
+			ch <- &tests[i]
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// sessionFiles returns the file itself or the YAML files of the directory in lexical order
+func sessionFiles(location string) ([]string, error) {
+	stat, err := os.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return []string{location}, nil
+	}
+	infos, err := ioutil.ReadDir(location)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, fi := range infos {
+		if !fi.IsDir() && (strings.HasSuffix(fi.Name(), ".yaml") || strings.HasSuffix(fi.Name(), ".yml")) {
+			files = append(files, filepath.Join(location, fi.Name()))
+		}
+	}
+	return files, nil
+}
+
+// exchangeTest makes the test sending the recorded request and expecting the recorded response
+func exchangeTest(file string, i int, exchange Exchange) yaml_file.Test {
+	name := exchange.Name
+	if name == "" {
+		name = fmt.Sprintf("%s #%d %s %s", filepath.Base(file), i+1, exchange.Method, exchange.Path)
+	}
+
+	test := yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			Name:        name,
+			Method:      exchange.Method,
+			RequestURL:  exchange.Path,
+			QueryParams: exchange.Query,
+			HeadersVal:  exchange.Headers,
+			RequestTmpl: exchange.Body,
+		},
+		FileName:  file,
+		Request:   exchange.Body,
+		Responses: map[int]string{exchange.Response.StatusCode: exchange.Response.Body},
+	}
+	if len(exchange.Response.Headers) > 0 {
+		test.ResponseHeaders = map[int]map[string]string{exchange.Response.StatusCode: exchange.Response.Headers}
+	}
+	return test
+}
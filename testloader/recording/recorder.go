@@ -0,0 +1,108 @@
+package recording
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// volatileHeaders differ from one run to another or are set by the transport,
+// they are not recorded to keep the replayed expectations stable
+var volatileHeaders = map[string]bool{
+	"Accept-Encoding":   true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Date":              true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"User-Agent":        true,
+}
+
+// Recorder is a transport recording the requests sent through it and the responses to them,
+// e.g. the transport of the HTTP client used during a live session
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	session Session
+}
+
+// NewRecorder records the requests sent with the transport, http.DefaultTransport if it is nil
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	exchange := Exchange{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Headers: recordedHeaders(req.Header),
+		Body:    string(reqBody),
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    recordedHeaders(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+	if req.URL.RawQuery != "" {
+		exchange.Query = "?" + req.URL.RawQuery
+	}
+
+	r.mu.Lock()
+	r.session.Exchanges = append(r.session.Exchanges, exchange)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Session returns the exchanges recorded so far
+func (r *Recorder) Session() *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exchanges := make([]Exchange, len(r.session.Exchanges))
+	copy(exchanges, r.session.Exchanges)
+	return &Session{Exchanges: exchanges}
+}
+
+// Save writes the exchanges recorded so far to the file
+func (r *Recorder) Save(path string) error {
+	return r.Session().WriteFile(path)
+}
+
+func recordedHeaders(header http.Header) map[string]string {
+	var res map[string]string
+	for name, values := range header {
+		if volatileHeaders[http.CanonicalHeaderKey(name)] || len(values) == 0 {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string)
+		}
+		res[name] = values[0]
+	}
+	return res
+}
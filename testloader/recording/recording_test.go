@@ -0,0 +1,86 @@
+package recording
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+func TestRecorderShouldRecordRequestsAndResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"echo": ` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	recorder := NewRecorder(nil)
+	client := &http.Client{Transport: recorder}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/users?limit=1", strings.NewReader(`"gonkey"`))
+	require.NoError(t, err)
+	req.Header.Set("X-Trace", "1")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, `{"echo": "gonkey"}`, string(body), "the response must still be readable")
+
+	dir, err := ioutil.TempDir("", "gonkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "session.yaml")
+	require.NoError(t, recorder.Save(path))
+	session, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Exchange{{
+		Method:  http.MethodPost,
+		Path:    "/users",
+		Query:   "?limit=1",
+		Headers: map[string]string{"X-Trace": "1"},
+		Body:    `"gonkey"`,
+		Response: Response{
+			StatusCode: http.StatusCreated,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `{"echo": "gonkey"}`,
+		},
+	}}, session.Exchanges)
+}
+
+func TestLoaderShouldMakeTestsExpectingRecordedResponses(t *testing.T) {
+	ch, err := NewLoader("testdata").Load()
+	require.NoError(t, err)
+	var tests []models.TestInterface
+	for test := range ch {
+		tests = append(tests, test)
+	}
+	require.Len(t, tests, 2)
+
+	created := tests[0]
+	assert.Equal(t, "create user", created.GetName())
+	assert.Equal(t, http.MethodPost, created.GetMethod())
+	assert.Equal(t, "/users", created.Path())
+	assert.Equal(t, `{"name": "gonkey"}`, created.GetRequest())
+	assert.Equal(t, map[string]string{"Content-Type": "application/json"}, created.Headers())
+	response, ok := created.GetResponse(http.StatusCreated)
+	assert.True(t, ok)
+	assert.Equal(t, `{"id": "$matchRegexp(^[0-9]+$)"}`, response)
+	headers, ok := created.GetResponseHeaders(http.StatusCreated)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"Content-Type": "application/json"}, headers)
+
+	listed := tests[1]
+	assert.Equal(t, "session.yaml #2 GET /users", listed.GetName())
+	assert.Equal(t, "?limit=1", listed.ToQuery())
+	_, ok = listed.GetResponseHeaders(http.StatusOK)
+	assert.False(t, ok)
+}
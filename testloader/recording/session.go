@@ -0,0 +1,53 @@
+package recording
+
+import (
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Session is a recorded sequence of the requests and the responses to them
+type Session struct {
+	Exchanges []Exchange `json:"exchanges" yaml:"exchanges"`
+}
+
+// Exchange is a recorded request and the response to it.
+// The name is optional, the exchanges without it are named by the request
+type Exchange struct {
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Method   string            `json:"method" yaml:"method"`
+	Path     string            `json:"path" yaml:"path"`
+	Query    string            `json:"query,omitempty" yaml:"query,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body     string            `json:"body,omitempty" yaml:"body,omitempty"`
+	Response Response          `json:"response" yaml:"response"`
+}
+
+// Response is the recorded response of the exchange
+type Response struct {
+	StatusCode int               `json:"statusCode" yaml:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       string            `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ReadFile reads the session recorded to the file
+func ReadFile(path string) (*Session, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// WriteFile writes the session to the file in the format the loader reads
+func (s *Session) WriteFile(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
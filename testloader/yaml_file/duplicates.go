@@ -0,0 +1,42 @@
+package yaml_file
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// findDuplicateNames describes the names shared by several tests with the files defining them,
+// the tests without names and their cases are not checked
+func findDuplicateNames(tests []Test) []string {
+	files := make(map[string][]string)
+	for i := range tests {
+		if name := tests[i].GetName(); name != "" && !tests[i].unnamed {
+			files[name] = append(files[name], tests[i].FileName)
+		}
+	}
+
+	var duplicates []string
+	for name, defined := range files {
+		if len(defined) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%s: %s", name, strings.Join(defined, ", ")))
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
+func (l *YamlFileLoader) checkDuplicateNames(tests []Test) error {
+	duplicates := findDuplicateNames(tests)
+	if len(duplicates) == 0 {
+		return nil
+	}
+	if l.warnOnDuplicateNames {
+		for _, d := range duplicates {
+			fmt.Printf("Warning: duplicate test name %s\n", d)
+		}
+		return nil
+	}
+	return fmt.Errorf("duplicate test names, the results of such tests overwrite each other in the reports:\n%s",
+		strings.Join(duplicates, "\n"))
+}
@@ -0,0 +1,40 @@
+package yaml_file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShouldFailOnDuplicateNames(t *testing.T) {
+	_, err := NewLoader("testdata/duplicates").Load()
+
+	assert.EqualError(t, err, "duplicate test names, the results of such tests overwrite each other in the reports:\n"+
+		"list: testdata/duplicates/authors.yaml, testdata/duplicates/books.yaml")
+}
+
+func TestLoadShouldWarnOnDuplicateNames(t *testing.T) {
+	loader := NewLoader("testdata/duplicates")
+	loader.SetWarnOnDuplicateNames(true)
+
+	ch, err := loader.Load()
+	require.NoError(t, err)
+
+	var count int
+	for range ch {
+		count++
+	}
+	assert.Equal(t, 4, count)
+}
+
+func TestLoadShouldNotCheckCasesOfUnnamedTests(t *testing.T) {
+	ch, err := NewLoader("testdata/unnamed-cases").Load()
+	require.NoError(t, err)
+
+	var count int
+	for range ch {
+		count++
+	}
+	assert.Equal(t, 4, count)
+}
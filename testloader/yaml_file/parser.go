@@ -28,6 +28,9 @@ func parseTestDefinitionFile(absPath string) ([]Test, error) {
 		if testCases, err := makeTestFromDefinition(definition); err != nil {
 			return nil, err
 		} else {
+			for i := range testCases {
+				testCases[i].FileName = absPath
+			}
 			tests = append(tests, testCases...)
 		}
 	}
@@ -70,6 +73,7 @@ func makeTestFromDefinition(testDefinition TestDefinition) ([]Test, error) {
 	for caseIdx, testCase := range testDefinition.Cases {
 		test := Test{TestDefinition: testDefinition}
 		test.Name = fmt.Sprintf("%s #%d", test.Name, caseIdx)
+		test.unnamed = testDefinition.Name == ""
 
 		// compile request body
 		test.Request, err = executeTmpl(requestTmpl, testCase.RequestArgs)
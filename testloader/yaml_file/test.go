@@ -1,6 +1,9 @@
 package yaml_file
 
 import (
+	"path/filepath"
+	"time"
+
 	"github.com/lamoda/gonkey/models"
 )
 
@@ -9,6 +12,11 @@ type Test struct {
 
 	TestDefinition
 
+	// FileName is the file the test is defined in
+	FileName string
+	// unnamed is set for the cases of the test without a name, their names are just the case numbers
+	unnamed bool
+
 	Request         string
 	Responses       map[int]string
 	ResponseHeaders map[int]map[string]string
@@ -51,10 +59,149 @@ func (t *Test) GetResponseHeaders(code int) (map[string]string, bool) {
 	return val, ok
 }
 
+func (t *Test) GetResponseLinks(code int) (map[string]string, bool) {
+	val, ok := t.ResponseLinks[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseCookies(code int) (map[string]models.CookieCheck, bool) {
+	val, ok := t.ResponseCookies[code]
+	if !ok {
+		return nil, false
+	}
+	cookies := make(map[string]models.CookieCheck, len(val))
+	for name, c := range val {
+		cookies[name] = models.CookieCheck{Deleted: c.Deleted}
+	}
+	return cookies, true
+}
+
+func (t *Test) GetResponseProblem(code int) (*models.ProblemDetails, bool) {
+	val, ok := t.ResponseProblem[code]
+	if !ok {
+		return nil, false
+	}
+	return &models.ProblemDetails{
+		Type:   val.Type,
+		Title:  val.Title,
+		Status: val.Status,
+		Detail: val.Detail,
+	}, true
+}
+
+func (t *Test) GetResponseBodyHash(code int) (map[string]string, bool) {
+	val, ok := t.ResponseBodyHash[code]
+	return val, ok
+}
+
+func (t *Test) GetRequiredFields(code int) ([]string, bool) {
+	val, ok := t.RequiredFields[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseKeys(code int) (map[string][]string, bool) {
+	val, ok := t.ResponseKeys[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseValidationErrors(code int) (*models.ValidationErrorsCheck, bool) {
+	val, ok := t.ValidationErrors[code]
+	if !ok {
+		return nil, false
+	}
+	check := &models.ValidationErrorsCheck{
+		Path:   val.Path,
+		Errors: val.Errors,
+	}
+	if check.Path == "" {
+		check.Path = models.DefaultValidationErrorsPath
+	}
+	return check, true
+}
+
+func (t *Test) GetResponseNDJSON(code int) (*models.NDJSONCheck, bool) {
+	val, ok := t.ResponseNDJSON[code]
+	if !ok {
+		return nil, false
+	}
+	check := &models.NDJSONCheck{
+		Lines:     val.Lines,
+		Unordered: val.Unordered,
+		Count:     len(val.Lines),
+	}
+	if val.Count != nil {
+		check.Count = *val.Count
+	}
+	return check, true
+}
+
+func (t *Test) GetResponseFiles(code int) ([]string, bool) {
+	val, ok := t.ResponseFiles[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseSchema(code int) (string, bool) {
+	val, ok := t.ResponseSchemas[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseMergeBase(code int) (string, bool) {
+	val, ok := t.ResponseMergeBase[code]
+	return val, ok
+}
+
+func (t *Test) GetResponseMergeBases() map[int]string {
+	return t.ResponseMergeBase
+}
+
+func (t *Test) GetResponseNDJSONLines() map[int][]string {
+	if t.ResponseNDJSON == nil {
+		return nil
+	}
+	res := make(map[int][]string, len(t.ResponseNDJSON))
+	for code, lines := range t.ResponseNDJSON {
+		res[code] = lines.Lines
+	}
+	return res
+}
+
+func (t *Test) GetResponseBodyPatterns() map[int]string {
+	return t.ResponseBodyMatches
+}
+
+func (t *Test) BodyComparator() string {
+	return t.BodyComparatorVal
+}
+
+func (t *Test) GetResponseBodyForbidden() []string {
+	return t.ResponseBodyForbidden
+}
+
+func (t *Test) GetResponseBodyMatches(code int) (string, bool) {
+	val, ok := t.ResponseBodyMatches[code]
+	return val, ok
+}
+
+func (t *Test) GetStatusText() string {
+	return t.StatusText
+}
+
+func (t *Test) GetProtocol() string {
+	return t.Protocol
+}
+
+func (t *Test) GetStatusLine() string {
+	return t.StatusLine
+}
+
 func (t *Test) NeedsCheckingValues() bool {
 	return !t.ComparisonParams.IgnoreValues
 }
 
+func (t *Test) GetFileName() string {
+	return t.FileName
+}
+
 func (t *Test) GetName() string {
 	return t.Name
 }
@@ -67,24 +214,84 @@ func (t *Test) DisallowExtraFields() bool {
 	return t.ComparisonParams.DisallowExtraFields
 }
 
+func (t *Test) Wildcard() string {
+	return t.ComparisonParams.Wildcard
+}
+
 func (t *Test) Fixtures() []string {
 	return t.FixtureFiles
 }
 
+func (t *Test) InlineFixtures() []string {
+	return t.InlineFixturesVal
+}
+
 func (t *Test) ServiceMocks() map[string]interface{} {
 	return t.MocksDefinition
 }
 
-func (t *Test) Pause() int {
-	return t.PauseValue
+func (t *Test) SkipFixtures() bool {
+	return t.SkipFixturesVal
+}
+
+func (t *Test) ExpectedToFail() bool {
+	return t.ExpectedToFailVal
+}
+
+// GetStructureSnapshot resolves the path of the snapshot of the status relative to the test file
+func (t *Test) GetStructureSnapshot(code int) (string, bool) {
+	val, ok := t.StructureSnapshots[code]
+	if !ok || val == "" {
+		return "", false
+	}
+	if filepath.IsAbs(val) {
+		return val, true
+	}
+	return filepath.Join(filepath.Dir(t.FileName), val), true
+}
+
+func (t *Test) DisallowUnusedMocks() bool {
+	return t.DisallowUnusedMocksVal
+}
+
+func (t *Test) DisallowUnexpectedMockRequests() bool {
+	return t.DisallowUnexpectedMockRequestsVal
+}
+
+func (t *Test) Meta() map[string]string {
+	return t.MetaVal
+}
+
+func (t *Test) ParityIgnore() []string {
+	return t.ParityIgnoreVal
+}
+
+func (t *Test) MaxDbQueries() *int {
+	return t.MaxDbQueriesVal
+}
+
+func (t *Test) MaxResponseTime() time.Duration {
+	return time.Duration(t.ResponseTimeVal.Max)
+}
+
+func (t *Test) CheckContentLength() bool {
+	return t.CheckContentLengthVal
+}
+
+func (t *Test) ExpectContinue() bool {
+	return t.ExpectContinueVal
+}
+
+func (t *Test) Pause() time.Duration {
+	return time.Duration(t.PauseValue)
 }
 
 func (t *Test) BeforeScriptPath() string {
 	return t.BeforeScript
 }
 
-func (t *Test) BeforeScriptTimeout() int {
-	return t.BeforeScriptParams.Timeout
+func (t *Test) BeforeScriptTimeout() time.Duration {
+	return time.Duration(t.BeforeScriptParams.Timeout)
 }
 
 func (t *Test) Cookies() map[string]string {
@@ -103,6 +310,161 @@ func (t *Test) DbResponseJson() []string {
 	return t.DbResponse
 }
 
+func (t *Test) RedisChecks() []models.RedisCheck {
+	checks := make([]models.RedisCheck, 0, len(t.RedisChecksVal))
+	for _, c := range t.RedisChecksVal {
+		// the key is expected to exist by default
+		exists := c.Exists == nil || *c.Exists
+		checks = append(checks, models.RedisCheck{
+			Key:    c.Key,
+			Exists: exists,
+			Value:  c.Value,
+			TTLMin: c.TTL.Min,
+			TTLMax: c.TTL.Max,
+		})
+	}
+	return checks
+}
+
+func (t *Test) Idempotency() *models.IdempotencyCheck {
+	if t.IdempotencyVal == nil {
+		return nil
+	}
+	header := t.IdempotencyVal.Header
+	if header == "" {
+		header = "Idempotency-Key"
+	}
+	return &models.IdempotencyCheck{
+		Header:         header,
+		Key:            t.IdempotencyVal.Key,
+		CompareHeaders: t.IdempotencyVal.CompareHeaders,
+	}
+}
+
+func (t *Test) Repeat() *models.RepeatCheck {
+	if t.RepeatVal == nil {
+		return nil
+	}
+	return &models.RepeatCheck{Status: t.RepeatVal.Status}
+}
+
+func (t *Test) Load() *models.LoadCheck {
+	if t.LoadVal == nil {
+		return nil
+	}
+	check := &models.LoadCheck{
+		Requests:    t.LoadVal.Requests,
+		Concurrency: t.LoadVal.Concurrency,
+		P95Under:    time.Duration(t.LoadVal.P95Under),
+	}
+	if check.Requests <= 0 {
+		check.Requests = 100
+	}
+	if check.Concurrency <= 0 {
+		check.Concurrency = 1
+	}
+	return check
+}
+
+// ConnectionReuse allows a single new connection for all the requests of the test if no limits are set
+func (t *Test) ConnectionReuse() *models.ConnectionReuseCheck {
+	if t.ConnectionReuseVal == nil {
+		return nil
+	}
+	check := &models.ConnectionReuseCheck{
+		WarmUp:           t.ConnectionReuseVal.WarmUp,
+		MaxConnections:   t.ConnectionReuseVal.MaxConnections,
+		MaxTLSHandshakes: t.ConnectionReuseVal.MaxTLSHandshakes,
+
+		ScenarioMaxConnections:   t.ConnectionReuseVal.ScenarioMaxConnections,
+		ScenarioMaxTLSHandshakes: t.ConnectionReuseVal.ScenarioMaxTLSHandshakes,
+	}
+	if check.MaxConnections == nil && check.MaxTLSHandshakes == nil &&
+		check.ScenarioMaxConnections == nil && check.ScenarioMaxTLSHandshakes == nil {
+		one := 1
+		check.MaxConnections = &one
+	}
+	return check
+}
+
+func (t *Test) GetRetryPolicy() *models.RetryPolicy {
+	if t.RetryPolicyVal == nil {
+		return nil
+	}
+	policy := &models.RetryPolicy{
+		Attempts:      t.RetryPolicyVal.Attempts,
+		Delay:         time.Duration(t.RetryPolicyVal.Delay),
+		RetryOnStatus: t.RetryPolicyVal.RetryOnStatus,
+	}
+	if policy.Attempts <= 0 {
+		policy.Attempts = 1
+	}
+	return policy
+}
+
+func (t *Test) Caching() *models.CachingCheck {
+	if t.CachingVal == nil {
+		return nil
+	}
+	check := &models.CachingCheck{
+		Headers:     t.CachingVal.Headers,
+		NotModified: t.CachingVal.NotModified,
+	}
+	if hit := t.CachingVal.Hit; hit.enabled {
+		check.Hit = &models.CacheHitCheck{
+			Header: hit.Header,
+			Value:  hit.Value,
+		}
+		if check.Hit.Header == "" {
+			check.Hit.Header = models.DefaultCacheHitHeader
+		}
+		if check.Hit.Value == "" {
+			check.Hit.Value = models.DefaultCacheHitValue
+		}
+	}
+	return check
+}
+
+func (t *Test) Pagination() *models.Pagination {
+	if t.PaginateVal == nil {
+		return nil
+	}
+	pagination := &models.Pagination{
+		Items:    t.PaginateVal.Items,
+		Next:     t.PaginateVal.Next,
+		MaxPages: t.PaginateVal.MaxPages,
+	}
+	if pagination.Items == "" {
+		pagination.Items = "$"
+	}
+	if pagination.MaxPages == 0 {
+		pagination.MaxPages = models.DefaultMaxPages
+	}
+	return pagination
+}
+
+func (t *Test) ChecksOrder() *models.ChecksOrder {
+	if t.ChecksVal == nil {
+		return nil
+	}
+	order := make([]models.ErrorCategory, len(t.ChecksVal.Order))
+	for i, category := range t.ChecksVal.Order {
+		order[i] = models.ErrorCategory(category)
+	}
+	return &models.ChecksOrder{
+		Order:         order,
+		StopOnFailure: t.ChecksVal.StopOnFailure,
+	}
+}
+
+func (t *Test) Export() []string {
+	return t.ExportVal
+}
+
+func (t *Test) DependsOn() []string {
+	return t.DependsOnVal
+}
+
 func (t *Test) GetVariables() map[string]string {
 	return t.Variables
 }
@@ -111,6 +473,10 @@ func (t *Test) GetVariablesToSet() map[int]map[string]string {
 	return t.VariablesToSet
 }
 
+func (t *Test) Capture() map[string]string {
+	return t.CaptureVal
+}
+
 func (t *Test) Clone() models.TestInterface {
 	res := *t
 
@@ -120,6 +486,28 @@ func (t *Test) Clone() models.TestInterface {
 func (t *Test) SetQuery(val string) {
 	t.QueryParams = val
 }
+
+func (t *Test) GetQueryParams() map[string][]string {
+	if t.QueryParamsMap == nil {
+		return nil
+	}
+	params := make(map[string][]string, len(t.QueryParamsMap))
+	for name, values := range t.QueryParamsMap {
+		params[name] = values
+	}
+	return params
+}
+
+func (t *Test) SetQueryParams(params map[string][]string) {
+	if params == nil {
+		t.QueryParamsMap = nil
+		return
+	}
+	t.QueryParamsMap = make(map[string]QueryValues, len(params))
+	for name, values := range params {
+		t.QueryParamsMap[name] = values
+	}
+}
 func (t *Test) SetMethod(val string) {
 	t.Method = val
 }
@@ -132,6 +520,30 @@ func (t *Test) SetRequest(val string) {
 func (t *Test) SetResponses(val map[int]string) {
 	t.Responses = val
 }
+func (t *Test) SetResponseMergeBases(val map[int]string) {
+	t.ResponseMergeBase = val
+}
+func (t *Test) SetResponseNDJSONLines(val map[int][]string) {
+	// the options of the lines are shared with the original test
+	res := make(NDJSONLines, len(t.ResponseNDJSON))
+	for code, lines := range t.ResponseNDJSON {
+		lines.Lines = val[code]
+		res[code] = lines
+	}
+	t.ResponseNDJSON = res
+}
+func (t *Test) SetResponseBodyPatterns(val map[int]string) {
+	t.ResponseBodyMatches = val
+}
 func (t *Test) SetHeaders(val map[string]string) {
 	t.HeadersVal = val
 }
+func (t *Test) SetServiceMocks(val map[string]interface{}) {
+	t.MocksDefinition = val
+}
+func (t *Test) SetDbQueryString(val string) {
+	t.DbQuery = val
+}
+func (t *Test) SetDbResponseJson(val []string) {
+	t.DbResponse = val
+}
@@ -1,25 +1,76 @@
 package yaml_file
 
+import (
+	"errors"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/variables"
+)
+
 type TestDefinition struct {
-	Name               string                    `json:"name" yaml:"name"`
-	Variables          map[string]string         `json:"variables" yaml:"variables"`
-	VariablesToSet     VariablesToSet            `json:"variables_to_set" yaml:"variables_to_set"`
-	Method             string                    `json:"method" yaml:"method"`
-	RequestURL         string                    `json:"path" yaml:"path"`
-	QueryParams        string                    `json:"query" yaml:"query"`
-	RequestTmpl        string                    `json:"request" yaml:"request"`
-	ResponseTmpls      map[int]string            `json:"response" yaml:"response"`
-	ResponseHeaders    map[int]map[string]string `json:"responseHeaders" yaml:"responseHeaders"`
-	BeforeScriptParams beforeScriptParams        `json:"beforeScript" yaml:"beforeScript"`
-	HeadersVal         map[string]string         `json:"headers" yaml:"headers"`
-	CookiesVal         map[string]string         `json:"cookies" yaml:"cookies"`
-	Cases              []CaseData                `json:"cases" yaml:"cases"`
-	ComparisonParams   comparisonParams          `json:"comparisonParams" yaml:"comparisonParams"`
-	FixtureFiles       []string                  `json:"fixtures" yaml:"fixtures"`
-	MocksDefinition    map[string]interface{}    `json:"mocks" yaml:"mocks"`
-	PauseValue         int                       `json:"pause" yaml:"pause"`
-	DbQueryTmpl        string                    `json:"dbQuery" yaml:"dbQuery"`
-	DbResponseTmpl     []string                  `json:"dbResponse" yaml:"dbResponse"`
+	Name                              string                    `json:"name" yaml:"name"`
+	DependsOnVal                      []string                  `json:"dependsOn" yaml:"dependsOn"`
+	ExportVal                         []string                  `json:"export" yaml:"export"`
+	Variables                         map[string]string         `json:"variables" yaml:"variables"`
+	VariablesToSet                    VariablesToSet            `json:"variables_to_set" yaml:"variables_to_set"`
+	CaptureVal                        map[string]string         `json:"capture" yaml:"capture"`
+	Method                            string                    `json:"method" yaml:"method"`
+	RequestURL                        string                    `json:"path" yaml:"path"`
+	QueryParams                       string                    `json:"query" yaml:"query"`
+	QueryParamsMap                    map[string]QueryValues    `json:"queryParams" yaml:"queryParams"`
+	RequestTmpl                       string                    `json:"request" yaml:"request"`
+	ResponseTmpls                     map[int]string            `json:"response" yaml:"response"`
+	ResponseHeaders                   map[int]map[string]string `json:"responseHeaders" yaml:"responseHeaders"`
+	ResponseLinks                     map[int]map[string]string `json:"responseLinks" yaml:"responseLinks"`
+	ResponseCookies                   map[int]map[string]cookie `json:"responseCookies" yaml:"responseCookies"`
+	StatusText                        string                    `json:"statusText" yaml:"statusText"`
+	Protocol                          string                    `json:"protocol" yaml:"protocol"`
+	StatusLine                        string                    `json:"statusLine" yaml:"statusLine"`
+	MetaVal                           map[string]string         `json:"meta" yaml:"meta"`
+	ParityIgnoreVal                   []string                  `json:"parityIgnore" yaml:"parityIgnore"`
+	ExpectContinueVal                 bool                      `json:"expectContinue" yaml:"expectContinue"`
+	MaxDbQueriesVal                   *int                      `json:"maxDbQueries" yaml:"maxDbQueries"`
+	CheckContentLengthVal             bool                      `json:"checkContentLength" yaml:"checkContentLength"`
+	StructureSnapshots                map[int]string            `json:"structureSnapshot" yaml:"structureSnapshot"`
+	BeforeScriptParams                beforeScriptParams        `json:"beforeScript" yaml:"beforeScript"`
+	HeadersVal                        map[string]string         `json:"headers" yaml:"headers"`
+	CookiesVal                        map[string]string         `json:"cookies" yaml:"cookies"`
+	Cases                             []CaseData                `json:"cases" yaml:"cases"`
+	ComparisonParams                  comparisonParams          `json:"comparisonParams" yaml:"comparisonParams"`
+	FixtureFiles                      []string                  `json:"fixtures" yaml:"-"`
+	InlineFixturesVal                 []string                  `json:"-" yaml:"-"`
+	SkipFixturesVal                   bool                      `json:"skipFixtures" yaml:"skipFixtures"`
+	ExpectedToFailVal                 bool                      `json:"expectedToFail" yaml:"expectedToFail"`
+	MocksDefinition                   map[string]interface{}    `json:"mocks" yaml:"mocks"`
+	DisallowUnusedMocksVal            bool                      `json:"disallowUnusedMocks" yaml:"disallowUnusedMocks"`
+	DisallowUnexpectedMockRequestsVal bool                      `json:"disallowUnexpectedMockRequests" yaml:"disallowUnexpectedMockRequests"`
+	PauseValue                        models.Duration           `json:"pause" yaml:"pause"`
+	DbQueryTmpl                       string                    `json:"dbQuery" yaml:"dbQuery"`
+	DbResponseTmpl                    []string                  `json:"dbResponse" yaml:"dbResponse"`
+	RedisChecksVal                    []redisCheck              `json:"responseRedis" yaml:"responseRedis"`
+	ResponseProblem                   map[int]problemDetails    `json:"responseProblem" yaml:"responseProblem"`
+	ResponseBodyHash                  map[int]map[string]string `json:"responseBodyHash" yaml:"responseBodyHash"`
+	ResponseBodyMatches               map[int]string            `json:"responseBodyMatches" yaml:"responseBodyMatches"`
+	ResponseBodyForbidden             []string                  `json:"responseBodyForbidden" yaml:"responseBodyForbidden"`
+	BodyComparatorVal                 string                    `json:"bodyComparator" yaml:"bodyComparator"`
+	RequiredFields                    map[int][]string          `json:"requiredFields" yaml:"requiredFields"`
+	ResponseFiles                     map[int][]string          `json:"responseFiles" yaml:"responseFiles"`
+	ResponseSchemas                   map[int]string            `json:"responseSchemas" yaml:"responseSchemas"`
+	ResponseMergeBase                 map[int]string            `json:"responseMergeBase" yaml:"responseMergeBase"`
+	ResponseKeys                      ResponseKeys              `json:"responseKeys" yaml:"responseKeys"`
+	ValidationErrors                  ValidationErrors          `json:"responseValidationErrors" yaml:"responseValidationErrors"`
+	ResponseNDJSON                    NDJSONLines               `json:"responseNDJSON" yaml:"responseNDJSON"`
+	ResponseTimeVal                   responseTime              `json:"responseTime" yaml:"responseTime"`
+	LoadVal                           *load                     `json:"load" yaml:"load"`
+	ConnectionReuseVal                *connectionReuse          `json:"connectionReuse" yaml:"connectionReuse"`
+	RetryPolicyVal                    *retryPolicy              `json:"retryPolicy" yaml:"retryPolicy"`
+	IdempotencyVal                    *idempotency              `json:"idempotency" yaml:"idempotency"`
+	RepeatVal                         *repeat                   `json:"repeat" yaml:"repeat"`
+	CachingVal                        *caching                  `json:"caching" yaml:"caching"`
+	PaginateVal                       *paginate                 `json:"paginate" yaml:"paginate"`
+	ChecksVal                         *checks                   `json:"checks" yaml:"checks"`
 }
 
 type CaseData struct {
@@ -32,14 +83,299 @@ type CaseData struct {
 }
 
 type comparisonParams struct {
-	IgnoreValues         bool `json:"ignoreValues" yaml:"ignoreValues"`
-	IgnoreArraysOrdering bool `json:"ignoreArraysOrdering" yaml:"ignoreArraysOrdering"`
-	DisallowExtraFields  bool `json:"disallowExtraFields" yaml:"disallowExtraFields"`
+	IgnoreValues         bool   `json:"ignoreValues" yaml:"ignoreValues"`
+	IgnoreArraysOrdering bool   `json:"ignoreArraysOrdering" yaml:"ignoreArraysOrdering"`
+	DisallowExtraFields  bool   `json:"disallowExtraFields" yaml:"disallowExtraFields"`
+	Wildcard             string `json:"wildcard" yaml:"wildcard"`
+}
+
+type redisCheck struct {
+	Key    string   `json:"key" yaml:"key"`
+	Exists *bool    `json:"exists" yaml:"exists"`
+	Value  *string  `json:"value" yaml:"value"`
+	TTL    ttlRange `json:"ttl" yaml:"ttl"`
+}
+
+type ttlRange struct {
+	Min int `json:"min" yaml:"min"`
+	Max int `json:"max" yaml:"max"`
+}
+
+type problemDetails struct {
+	Type   string `json:"type" yaml:"type"`
+	Title  string `json:"title" yaml:"title"`
+	Status int    `json:"status" yaml:"status"`
+	Detail string `json:"detail" yaml:"detail"`
+}
+
+type cookie struct {
+	Deleted bool `json:"deleted" yaml:"deleted"`
+}
+
+type idempotency struct {
+	Header         string   `json:"header" yaml:"header"`
+	Key            string   `json:"key" yaml:"key"`
+	CompareHeaders []string `json:"compareHeaders" yaml:"compareHeaders"`
+}
+
+type repeat struct {
+	Status int `json:"status" yaml:"status"`
+}
+
+// UnmarshalYAML requires the status of the repeated response, there is no sensible default
+func (r *repeat) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain repeat
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if r.Status == 0 {
+		return errors.New("`repeat` requires `status` of the repeated response")
+	}
+	return nil
+}
+
+type load struct {
+	Requests    int             `json:"requests" yaml:"requests"`
+	Concurrency int             `json:"concurrency" yaml:"concurrency"`
+	P95Under    models.Duration `json:"p95Under" yaml:"p95Under"`
+}
+
+type connectionReuse struct {
+	WarmUp           int  `json:"warmUp" yaml:"warmUp"`
+	MaxConnections   *int `json:"maxConnections" yaml:"maxConnections"`
+	MaxTLSHandshakes *int `json:"maxTLSHandshakes" yaml:"maxTLSHandshakes"`
+
+	ScenarioMaxConnections   *int `json:"scenarioMaxConnections" yaml:"scenarioMaxConnections"`
+	ScenarioMaxTLSHandshakes *int `json:"scenarioMaxTLSHandshakes" yaml:"scenarioMaxTLSHandshakes"`
+}
+
+type responseTime struct {
+	Max models.Duration `json:"max" yaml:"max"`
+}
+
+type retryPolicy struct {
+	Attempts      int             `json:"attempts" yaml:"attempts"`
+	Delay         models.Duration `json:"delay" yaml:"delay"`
+	RetryOnStatus []int           `json:"retryOnStatus" yaml:"retryOnStatus"`
+}
+
+type caching struct {
+	Headers     []string `json:"headers" yaml:"headers"`
+	NotModified bool     `json:"notModified" yaml:"notModified"`
+	Hit         cacheHit `json:"hit" yaml:"hit"`
+}
+
+type cacheHit struct {
+	Header  string `json:"header" yaml:"header"`
+	Value   string `json:"value" yaml:"value"`
+	enabled bool
+}
+
+// UnmarshalYAML accepts either true for the default header and value
+// or the header with its expected value
+func (h *cacheHit) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		*h = cacheHit{enabled: enabled}
+		return nil
+	}
+
+	var res struct {
+		Header string `yaml:"header"`
+		Value  string `yaml:"value"`
+	}
+	if err := unmarshal(&res); err != nil {
+		return err
+	}
+	*h = cacheHit{Header: res.Header, Value: res.Value, enabled: true}
+	return nil
+}
+
+type paginate struct {
+	Items    string `json:"items" yaml:"items"`
+	Next     string `json:"next" yaml:"next"`
+	MaxPages int    `json:"maxPages" yaml:"maxPages"`
+}
+
+type checks struct {
+	Order         []string `json:"order" yaml:"order"`
+	StopOnFailure bool     `json:"stopOnFailure" yaml:"stopOnFailure"`
 }
 
 type beforeScriptParams struct {
-	PathTmpl string `json:"path" yaml:"path"`
-	Timeout  int    `json:"timeout" yaml:"timeout"`
+	PathTmpl string          `json:"path" yaml:"path"`
+	Timeout  models.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// QueryValues are values of the repeated query parameter, a single value is also accepted
+type QueryValues []string
+
+func (q *QueryValues) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*q = QueryValues{single}
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*q = list
+	return nil
+}
+
+// ResponseKeys contains the exact sets of the object keys by response code and JSON-path
+type ResponseKeys map[int]map[string][]string
+
+// UnmarshalYAML accepts either the keys by JSON-path or the plain list
+// of the top-level keys, which is stored with "$" path
+func (k *ResponseKeys) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var plain map[int][]string
+	if err := unmarshal(&plain); err == nil {
+		res := make(map[int]map[string][]string)
+		for code, keys := range plain {
+			res[code] = map[string][]string{"$": keys}
+		}
+		*k = res
+		return nil
+	}
+
+	var res map[int]map[string][]string
+	if err := unmarshal(&res); err != nil {
+		return err
+	}
+	*k = res
+	return nil
+}
+
+// ValidationErrors contains the expected validation errors by response code
+type ValidationErrors map[int]validationErrors
+
+type validationErrors struct {
+	Path   string              `json:"path" yaml:"path"`
+	Errors []map[string]string `json:"errors" yaml:"errors"`
+}
+
+// UnmarshalYAML accepts either the errors with their path
+// or the plain list of the errors found by the default path, the forms may differ by response code
+func (v *validationErrors) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var plain []map[string]string
+	if err := unmarshal(&plain); err == nil {
+		*v = validationErrors{Errors: plain}
+		return nil
+	}
+
+	// the alias has no UnmarshalYAML method
+	type withPath validationErrors
+	var res withPath
+	if err := unmarshal(&res); err != nil {
+		return err
+	}
+	*v = validationErrors(res)
+	return nil
+}
+
+// NDJSONLines contains the expected lines of newline-delimited JSON responses by response code
+type NDJSONLines map[int]ndjsonLines
+
+type ndjsonLines struct {
+	Lines     []string `json:"lines" yaml:"lines"`
+	Unordered bool     `json:"unordered" yaml:"unordered"`
+	Count     *int     `json:"count" yaml:"count"`
+}
+
+// UnmarshalYAML accepts either the lines with the options or the plain list of the lines,
+// the forms may differ by response code
+func (n *ndjsonLines) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var plain []string
+	if err := unmarshal(&plain); err == nil {
+		*n = ndjsonLines{Lines: plain}
+		return nil
+	}
+
+	// the alias has no UnmarshalYAML method
+	type withOptions ndjsonLines
+	var res withOptions
+	if err := unmarshal(&res); err != nil {
+		return err
+	}
+	*n = ndjsonLines(res)
+	return nil
+}
+
+// UnmarshalYAML reads fixtures listing both the names of the files and the tables defined inline,
+// the latter are kept as YAML documents of the fixtures in InlineFixturesVal
+func (d *TestDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain TestDefinition
+	if err := unmarshal((*plain)(d)); err != nil {
+		return err
+	}
+	var definition struct {
+		Fixtures fixtures `yaml:"fixtures"`
+	}
+	if err := unmarshal(&definition); err != nil {
+		return err
+	}
+	d.FixtureFiles = definition.Fixtures.files
+	d.InlineFixturesVal = definition.Fixtures.inline
+	return nil
+}
+
+// fixtures are the names of the fixtures files and the fixtures defined inline
+type fixtures struct {
+	files  []string
+	inline []string
+}
+
+type fixtureItem struct {
+	name   string
+	inline string
+}
+
+// UnmarshalYAML accepts either the name of the fixture file or the tables defined inline
+func (i *fixtureItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&i.name); err == nil {
+		return nil
+	}
+	var definition struct {
+		Inline yaml.MapSlice `yaml:"inline"`
+	}
+	if err := unmarshal(&definition); err != nil {
+		return err
+	}
+	if len(definition.Inline) == 0 {
+		return errors.New("fixture must be either the name of the file or the inline tables")
+	}
+	data, err := yaml.Marshal(yaml.MapSlice{{Key: "tables", Value: definition.Inline}})
+	if err != nil {
+		return err
+	}
+	i.inline = string(data)
+	return nil
+}
+
+// UnmarshalYAML accepts the list of the fixtures or the single inline fixture
+func (f *fixtures) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items []fixtureItem
+	if err := unmarshal(&items); err != nil {
+		var item fixtureItem
+		if err := unmarshal(&item); err != nil {
+			return err
+		}
+		items = []fixtureItem{item}
+	}
+
+	res := fixtures{}
+	for _, item := range items {
+		if item.inline != "" {
+			res.inline = append(res.inline, item.inline)
+			continue
+		}
+		res.files = append(res.files, item.name)
+	}
+	*f = res
+	return nil
 }
 
 type VariablesToSet map[int]map[string]string
@@ -62,6 +398,12 @@ There can be two types of data in yaml-file:
 			<varName1>: ""
 		<code2>:
 			<varName2>: ""
+The paths may take the values from other sources than the response body:
+	 VariablesToSet:
+		<code1>:
+			<varName1>: header:<Header-Name>
+			<varName2>: cookie:<cookie_name>
+			<varName3>: dbResponse:<row>.<JSON_Path>
 */
 func (v *VariablesToSet) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
@@ -85,6 +427,13 @@ func (v *VariablesToSet) UnmarshalYAML(unmarshal func(interface{}) error) error
 	if err := unmarshal(&res); err != nil {
 		return err
 	}
+	for _, paths := range res {
+		for _, path := range paths {
+			if _, _, err := variables.ParseSource(path); err != nil {
+				return err
+			}
+		}
+	}
 
 	*v = res
 	return nil
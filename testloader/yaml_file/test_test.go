@@ -3,6 +3,10 @@ package yaml_file
 import (
 	"reflect"
 	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/models"
 )
 
 func TestNewTestWithCases(t *testing.T) {
@@ -71,3 +75,82 @@ func TestNewTestWithCases(t *testing.T) {
 		t.Errorf("wait request %s, got %s", `{"foo": "bar", "hello": "world2" }`, reqData)
 	}
 }
+
+func TestCachingHitShouldHaveDefaults(t *testing.T) {
+	tests := []struct {
+		definition string
+		expected   *models.CacheHitCheck
+	}{
+		{"caching:\n  hit: true\n", &models.CacheHitCheck{Header: "X-Cache", Value: "HIT"}},
+		{"caching:\n  hit:\n    header: CF-Cache-Status\n", &models.CacheHitCheck{Header: "CF-Cache-Status", Value: "HIT"}},
+		{"caching:\n  hit: false\n", nil},
+		{"caching:\n  notModified: true\n", nil},
+	}
+	for _, tc := range tests {
+		test := &Test{}
+		if err := yaml.Unmarshal([]byte(tc.definition), &test.TestDefinition); err != nil {
+			t.Fatal(err)
+		}
+		if hit := test.Caching().Hit; !reflect.DeepEqual(hit, tc.expected) {
+			t.Errorf("unexpected cache hit check of %q: %+v", tc.definition, hit)
+		}
+	}
+}
+
+func TestFixturesShouldAcceptInlineTables(t *testing.T) {
+	tests := []struct {
+		definition string
+		files      []string
+		inline     []string
+	}{
+		{"fixtures: [orders, users]\n", []string{"orders", "users"}, nil},
+		{
+			"fixtures:\n  - orders\n  - inline:\n      users:\n        - name: bob\n      roles:\n        - name: admin\n",
+			[]string{"orders"},
+			[]string{"tables:\n  users:\n  - name: bob\n  roles:\n  - name: admin\n"},
+		},
+		{"fixtures:\n  inline:\n    users:\n      - name: bob\n", nil, []string{"tables:\n  users:\n  - name: bob\n"}},
+	}
+	for _, tc := range tests {
+		test := &Test{}
+		if err := yaml.Unmarshal([]byte(tc.definition), &test.TestDefinition); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(test.Fixtures(), tc.files) {
+			t.Errorf("unexpected fixtures files of %q: %v", tc.definition, test.Fixtures())
+		}
+		if !reflect.DeepEqual(test.InlineFixtures(), tc.inline) {
+			t.Errorf("unexpected inline fixtures of %q: %q", tc.definition, test.InlineFixtures())
+		}
+	}
+
+	test := &Test{}
+	if err := yaml.Unmarshal([]byte("fixtures:\n  - inline: {}\n"), &test.TestDefinition); err == nil {
+		t.Error("empty inline fixture must not be accepted")
+	}
+}
+
+func TestFixturesShouldBeSetInDefinition(t *testing.T) {
+	test := &Test{TestDefinition: TestDefinition{FixtureFiles: []string{"orders"}}}
+
+	if !reflect.DeepEqual(test.Fixtures(), []string{"orders"}) {
+		t.Errorf("unexpected fixtures files: %v", test.Fixtures())
+	}
+}
+
+func TestRepeatShouldRequireStatus(t *testing.T) {
+	test := &Test{}
+	if err := yaml.Unmarshal([]byte("repeat:\n  status: 404\n"), &test.TestDefinition); err != nil {
+		t.Fatal(err)
+	}
+	if repeat := test.Repeat(); repeat == nil || repeat.Status != 404 {
+		t.Errorf("unexpected repeat check: %+v", repeat)
+	}
+
+	for _, definition := range []string{"repeat: {}\n", "repeat:\n  status: 0\n"} {
+		err := yaml.Unmarshal([]byte(definition), &(&Test{}).TestDefinition)
+		if err == nil || err.Error() != "`repeat` requires `status` of the repeated response" {
+			t.Errorf("unexpected error of %q: %v", definition, err)
+		}
+	}
+}
@@ -102,3 +102,52 @@ func checkApplied(t *testing.T, test models.TestInterface) {
 	assert.True(t, ok)
 	assert.Equal(t, "existingVar_Value - {{ $notExistingVar }}", resp)
 }
+
+func TestApplyShouldPerformMocks(t *testing.T) {
+	tests, err := parseTestDefinitionFile("testdata/variables-mocks.yaml")
+	require.NoError(t, err)
+
+	testOriginal := &tests[0]
+
+	vars := variables.New()
+	vars.Load(testOriginal.GetVariables())
+	testApplied := vars.Apply(testOriginal)
+
+	uris := testApplied.ServiceMocks()["books"].(map[interface{}]interface{})["uris"].(map[interface{}]interface{})
+	mock := uris["/books/42"].(map[interface{}]interface{})
+	assert.Equal(t, `{"id": 42}`, mock["body"])
+	assert.Equal(t, []interface{}{"X-Book: 42"}, mock["headers"])
+
+	// check that original test is not changed
+	uris = testOriginal.ServiceMocks()["books"].(map[interface{}]interface{})["uris"].(map[interface{}]interface{})
+	mock = uris["/books/{{ $bookId }}"].(map[interface{}]interface{})
+	assert.Equal(t, `{"id": {{ $bookId }}}`, mock["body"])
+}
+
+func TestApplyShouldPerformPerStatusFields(t *testing.T) {
+	tests, err := parseTestDefinitionFile("testdata/variables-per-status.yaml")
+	require.NoError(t, err)
+
+	testOriginal := &tests[0]
+
+	vars := variables.New()
+	vars.Load(testOriginal.GetVariables())
+	testApplied := vars.Apply(testOriginal)
+
+	base, ok := testApplied.GetResponseMergeBase(201)
+	assert.True(t, ok)
+	assert.Equal(t, `{"id": 42}`, base)
+
+	pattern, ok := testApplied.GetResponseBodyMatches(201)
+	assert.True(t, ok)
+	assert.Equal(t, `"id":\s*42`, pattern)
+
+	ndjson, ok := testApplied.GetResponseNDJSON(206)
+	require.True(t, ok)
+	assert.Equal(t, &models.NDJSONCheck{Lines: []string{`{"id": 42}`}, Unordered: true, Count: 1}, ndjson)
+
+	// check that original test is not changed
+	ndjson, ok = testOriginal.GetResponseNDJSON(206)
+	require.True(t, ok)
+	assert.Equal(t, []string{`{"id": {{ $bookId }}}`}, ndjson.Lines)
+}
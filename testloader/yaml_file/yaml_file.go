@@ -12,8 +12,9 @@ import (
 type YamlFileLoader struct {
 	testloader.LoaderInterface
 
-	testsLocation string
-	fileFilter    string
+	testsLocation        string
+	fileFilter           string
+	warnOnDuplicateNames bool
 }
 
 func NewLoader(testsLocation string) *YamlFileLoader {
@@ -27,6 +28,9 @@ func (l *YamlFileLoader) Load() (chan models.TestInterface, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := l.checkDuplicateNames(fileTests); err != nil {
+		return nil, err
+	}
 	ch := make(chan models.TestInterface)
 	go func() {
 		for i := range fileTests {
@@ -41,6 +45,11 @@ func (l *YamlFileLoader) SetFileFilter(f string) {
 	l.fileFilter = f
 }
 
+// SetWarnOnDuplicateNames makes the loader print the tests sharing a name instead of failing
+func (l *YamlFileLoader) SetWarnOnDuplicateNames(warn bool) {
+	l.warnOnDuplicateNames = warn
+}
+
 func (l *YamlFileLoader) parseTestsWithCases(path string) ([]Test, error) {
 	stat, err := os.Stat(path)
 	if err != nil {
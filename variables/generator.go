@@ -0,0 +1,139 @@
+package variables
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generator returns a new value of the variable for every test, e.g. {{ $randomInt(1, 100) }}
+// calls the generator randomInt with the arguments "1" and "100"
+type Generator func(args ...string) string
+
+var generatorCallRx = regexp.MustCompile(`{{\s*\$(\w+)\(([^)]*)\)\s*}}`)
+
+const alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func defaultGenerators() map[string]Generator {
+	return map[string]Generator{
+		"uuid":         generateUUID,
+		"randomInt":    generateRandomInt,
+		"randomString": generateRandomString,
+		"randomEmail":  generateRandomEmail,
+		"now":          generateNow,
+	}
+}
+
+// AddGenerator registers the generator of the variable which is not set explicitly nor found in the sources,
+// the built-in generators are uuid, randomInt(min, max), randomString(length), randomEmail and now(layout)
+func (vs *Variables) AddGenerator(name string, fn Generator) {
+	vs.generators[name] = fn
+}
+
+// generate memoizes the values of the test, so the same call gives the same value
+// both in the request and in the checks
+func (vs *Variables) generate(name string, args []string) (string, bool) {
+	fn, ok := vs.generators[name]
+	if !ok {
+		return "", false
+	}
+	key := name + "(" + strings.Join(args, ",") + ")"
+	if val, ok := vs.generated[key]; ok {
+		return val, true
+	}
+	val := fn(args...)
+	vs.generated[key] = val
+	return val, true
+}
+
+// performGeneratorCalls replaces the calls of the generators with arguments,
+// the calls of unknown generators are left as is
+func (vs *Variables) performGeneratorCalls(str string) string {
+	return generatorCallRx.ReplaceAllStringFunc(str, func(call string) string {
+		match := generatorCallRx.FindStringSubmatch(call)
+		if val, ok := vs.generate(match[1], parseGeneratorArgs(match[2])); ok {
+			return val
+		}
+		return call
+	})
+}
+
+// parseGeneratorArgs splits the arguments by commas and unquotes them, e.g. '2006-01-02'
+func parseGeneratorArgs(str string) []string {
+	if strings.TrimSpace(str) == "" {
+		return nil
+	}
+	args := strings.Split(str, ",")
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if len(arg) >= 2 && (arg[0] == '\'' || arg[0] == '"') && arg[len(arg)-1] == arg[0] {
+			arg = arg[1 : len(arg)-1]
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+func generateUUID(args ...string) string {
+	return uuid.New().String()
+}
+
+// generateRandomInt returns the number from min to max inclusive, 0 to 1000000 by default,
+// empty string if the arguments are invalid
+func generateRandomInt(args ...string) string {
+	min, max := int64(0), int64(1000000)
+	if len(args) == 2 {
+		var err1, err2 error
+		min, err1 = strconv.ParseInt(args[0], 10, 64)
+		max, err2 = strconv.ParseInt(args[1], 10, 64)
+		if err1 != nil || err2 != nil || min > max {
+			return ""
+		}
+	} else if len(args) != 0 {
+		return ""
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(max-min+1))
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatInt(min+n.Int64(), 10)
+}
+
+// generateRandomString returns the alphanumeric string of the given length, 10 by default
+func generateRandomString(args ...string) string {
+	length := 10
+	if len(args) == 1 {
+		var err error
+		if length, err = strconv.Atoi(args[0]); err != nil || length < 0 {
+			return ""
+		}
+	}
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphanumeric))))
+		if err != nil {
+			return ""
+		}
+		b[i] = alphanumeric[n.Int64()]
+	}
+	return string(b)
+}
+
+func generateRandomEmail(args ...string) string {
+	return fmt.Sprintf("%s@example.com", strings.ToLower(generateRandomString("16")))
+}
+
+// generateNow formats the current time with the layout of the time package, RFC 3339 by default
+func generateNow(args ...string) string {
+	layout := time.RFC3339
+	if len(args) > 0 {
+		layout = args[0]
+	}
+	return time.Now().Format(layout)
+}
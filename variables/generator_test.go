@@ -0,0 +1,60 @@
+package variables
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorsShouldBeMemoizedPerTest(t *testing.T) {
+	vars := New()
+	vars.generated = make(map[string]string)
+
+	first := vars.perform("{{ $uuid }}")
+	assert.Regexp(t, `^[0-9a-f-]{36}$`, first)
+	assert.Equal(t, first, vars.perform("{{ $uuid }}"))
+	assert.Equal(t, first, vars.perform("{{ $uuid() }}"))
+
+	number := vars.perform("{{ $randomInt(1, 100) }}")
+	assert.Equal(t, number, vars.perform("{{ $randomInt(1,100) }}"))
+	n, err := strconv.Atoi(number)
+	require.NoError(t, err)
+	assert.True(t, n >= 1 && n <= 100)
+
+	// the next test gets new values
+	vars.generated = make(map[string]string)
+	assert.NotEqual(t, first, vars.perform("{{ $uuid }}"))
+}
+
+func TestGeneratorsArguments(t *testing.T) {
+	vars := New()
+
+	assert.Equal(t, time.Now().Format("2006-01-02"), vars.perform("{{ $now('2006-01-02') }}"))
+	assert.Regexp(t, `^[a-zA-Z0-9]{5}$`, vars.perform(`{{ $randomString("5") }}`))
+	assert.Regexp(t, `^[a-z0-9]{16}@example.com$`, vars.perform("{{ $randomEmail }}"))
+	assert.Equal(t, "7", vars.perform("{{ $randomInt(7, 7) }}"))
+	assert.Equal(t, "", vars.perform("{{ $randomInt(a, b) }}"))
+	assert.Equal(t, "{{ $unknown(1) }}", vars.perform("{{ $unknown(1) }}"))
+}
+
+func TestVariablesShouldOverrideGenerators(t *testing.T) {
+	vars := New()
+	vars.Set("uuid", "explicit")
+
+	assert.Equal(t, "explicit", vars.perform("{{ $uuid }}"))
+}
+
+func TestAddGenerator(t *testing.T) {
+	vars := New()
+	calls := 0
+	vars.AddGenerator("counter", func(args ...string) string {
+		calls++
+		return args[0] + strconv.Itoa(calls)
+	})
+
+	assert.Equal(t, "id-1 id-1", vars.perform("{{ $counter(id-) }} {{ $counter(id-) }}"))
+	assert.Equal(t, 1, calls)
+}
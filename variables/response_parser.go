@@ -2,10 +2,21 @@ package variables
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/tidwall/gjson"
 )
 
+// The sources of variables_to_set other than the response body, e.g. header:Location,
+// cookie:session or dbResponse:0.id for the field id of the first row of the db response
+const (
+	SourceHeader     = "header"
+	SourceCookie     = "cookie"
+	SourceDbResponse = "dbResponse"
+)
+
 func FromResponse(varsToSet map[string]string, body string, isJson bool) (vars *Variables, err error) {
 
 	names, paths := split(varsToSet)
@@ -58,6 +69,113 @@ func fromPlainText(names []string, body string) (*Variables, error) {
 	return New().Add(NewVariable(names[0], body)), nil
 }
 
+// ParseSource splits the path of variables_to_set into the source and the name of the header (cookie)
+// or the row path of the db response, the source of the paths of the response body is empty
+func ParseSource(path string) (source, name string, err error) {
+	i := strings.Index(path, ":")
+	if i < 0 {
+		return "", path, nil
+	}
+	source, name = path[:i], path[i+1:]
+	switch source {
+	case SourceHeader, SourceCookie:
+	case SourceDbResponse:
+		row := strings.SplitN(name, ".", 2)[0]
+		if _, err := strconv.Atoi(row); err != nil {
+			return "", "", fmt.Errorf("path '%s' must start with the index of the db response row", path)
+		}
+	default:
+		// the colon is a part of the path of the response body
+		return "", path, nil
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("path '%s' must have the name of the %s", path, source)
+	}
+	return source, name, nil
+}
+
+// SplitSources returns the variables of the response body and the ones of the other sources
+func SplitSources(varsToSet map[string]string) (body, sources map[string]string) {
+	body = make(map[string]string, len(varsToSet))
+	sources = make(map[string]string, len(varsToSet))
+	for name, path := range varsToSet {
+		if source, _, _ := ParseSource(path); source != "" {
+			sources[name] = path
+		} else {
+			body[name] = path
+		}
+	}
+	return body, sources
+}
+
+// FromSources returns the variables of the response headers, cookies and db response rows,
+// the error names the first value which is absent
+func FromSources(varsToSet map[string]string, headers http.Header, dbResponse []string) (*Variables, error) {
+	names, paths := split(varsToSet)
+	vars := New()
+	for n, path := range paths {
+		source, name, err := ParseSource(path)
+		if err != nil {
+			return nil, err
+		}
+		var value string
+		switch source {
+		case SourceHeader:
+			if value, err = fromHeader(name, headers); err != nil {
+				return nil, err
+			}
+		case SourceCookie:
+			if value, err = fromCookie(name, headers); err != nil {
+				return nil, err
+			}
+		case SourceDbResponse:
+			if value, err = fromDbResponse(name, dbResponse); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("path '%s' is not a path of a header, cookie or db response", path)
+		}
+		vars.Add(NewVariable(names[n], value))
+	}
+	return vars, nil
+}
+
+func fromHeader(name string, headers http.Header) (string, error) {
+	values := headers.Values(name)
+	if len(values) == 0 {
+		return "", fmt.Errorf("header '%s' doesn't exist in the response", name)
+	}
+	return values[0], nil
+}
+
+func fromCookie(name string, headers http.Header) (string, error) {
+	resp := http.Response{Header: headers}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == name {
+			return cookie.Value, nil
+		}
+	}
+	return "", fmt.Errorf("cookie '%s' isn't set by the response", name)
+}
+
+// fromDbResponse returns the field of the row by the path after the index of the row,
+// the whole row without the path
+func fromDbResponse(path string, dbResponse []string) (string, error) {
+	parts := strings.SplitN(path, ".", 2)
+	row, _ := strconv.Atoi(parts[0])
+	if row < 0 || row >= len(dbResponse) {
+		return "", fmt.Errorf("row %d doesn't exist in the db response of %d row(s)", row, len(dbResponse))
+	}
+	if len(parts) == 1 {
+		return dbResponse[row], nil
+	}
+	res := gjson.Get(dbResponse[row], parts[1])
+	if !res.Exists() {
+		return "", fmt.Errorf("path '%s' doesn't exist in row %d of the db response", parts[1], row)
+	}
+	return res.String(), nil
+}
+
 // split returns keys and values of given map as separate slices
 func split(m map[string]string) ([]string, []string) {
 
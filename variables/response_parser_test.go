@@ -0,0 +1,78 @@
+package variables
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		path   string
+		source string
+		name   string
+	}{
+		{path: "data.id", name: "data.id"},
+		{path: "header:Location", source: SourceHeader, name: "Location"},
+		{path: "cookie:session", source: SourceCookie, name: "session"},
+		{path: "dbResponse:0.id", source: SourceDbResponse, name: "0.id"},
+		{path: "time:12", name: "time:12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			source, name, err := ParseSource(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.source, source)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+
+	_, _, err := ParseSource("header:")
+	assert.EqualError(t, err, "path 'header:' must have the name of the header")
+	_, _, err = ParseSource("dbResponse:id")
+	assert.EqualError(t, err, "path 'dbResponse:id' must start with the index of the db response row")
+}
+
+func TestFromSources(t *testing.T) {
+	headers := http.Header{
+		"Location":   {"/orders/42"},
+		"Set-Cookie": {"lang=en; Path=/", "session=abc; HttpOnly"},
+	}
+	dbResponse := []string{`{"id": 1, "name": "first"}`, `{"id": 2, "name": "second"}`}
+
+	vars, err := FromSources(map[string]string{
+		"location": "header:location",
+		"session":  "cookie:session",
+		"name":     "dbResponse:1.name",
+		"row":      "dbResponse:0",
+	}, headers, dbResponse)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/orders/42", vars.get("location").value)
+	assert.Equal(t, "abc", vars.get("session").value)
+	assert.Equal(t, "second", vars.get("name").value)
+	assert.Equal(t, `{"id": 1, "name": "first"}`, vars.get("row").value)
+}
+
+func TestFromSourcesShouldFailOnAbsentValues(t *testing.T) {
+	headers := http.Header{"Set-Cookie": {"lang=en"}}
+	dbResponse := []string{`{"id": 1}`}
+
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{path: "header:Location", err: "header 'Location' doesn't exist in the response"},
+		{path: "cookie:session", err: "cookie 'session' isn't set by the response"},
+		{path: "dbResponse:1.id", err: "row 1 doesn't exist in the db response of 1 row(s)"},
+		{path: "dbResponse:0.name", err: "path 'name' doesn't exist in row 0 of the db response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := FromSources(map[string]string{"value": tt.path}, headers, dbResponse)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package variables
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Source provides values of the variables which are not defined in the tests
+// nor set from the responses, e.g. environment variables or secrets storage.
+type Source interface {
+	// Get returns value of the variable and true if the variable is known to the source
+	Get(name string) (string, bool)
+}
+
+// EnvironmentSource reads variables from the environment, empty values are considered as missing
+type EnvironmentSource struct{}
+
+func NewEnvironmentSource() *EnvironmentSource {
+	return &EnvironmentSource{}
+}
+
+func (s *EnvironmentSource) Get(name string) (string, bool) {
+	val := os.Getenv(name)
+	if val == "" {
+		return "", false
+	}
+	return val, true
+}
+
+// FileSource reads variables from the dotenv-style file, see Variables.LoadFromFile
+type FileSource struct {
+	values map[string]string
+}
+
+func NewFileSource(path string) (*FileSource, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read variables from %s: %v", path, err)
+	}
+	return &FileSource{values: values}, nil
+}
+
+func (s *FileSource) Get(name string) (string, bool) {
+	val, ok := s.values[name]
+	return val, ok
+}
+
+// environmentSnapshot has the values of the environment at the moment of its creation,
+// see Variables.LoadEnvironment
+type environmentSnapshot map[string]string
+
+func newEnvironmentSnapshot() environmentSnapshot {
+	s := make(environmentSnapshot)
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 && parts[1] != "" {
+			s[parts[0]] = parts[1]
+		}
+	}
+	return s
+}
+
+func (s environmentSnapshot) Get(name string) (string, bool) {
+	val, ok := s[name]
+	return val, ok
+}
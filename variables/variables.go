@@ -6,8 +6,17 @@ import (
 	"github.com/lamoda/gonkey/models"
 )
 
+// Variables resolve the names in the order of precedence: the variables set explicitly
+// (by the tests and from the responses), the registered sources, the files loaded
+// with LoadFromFile, the environment and the generators
 type Variables struct {
-	variables variables
+	variables   variables
+	sources     []Source
+	files       []Source
+	environment Source
+	generators  map[string]Generator
+	// generated are the values of the generators for the applied test
+	generated map[string]string
 }
 
 type variables map[string]*Variable
@@ -16,10 +25,36 @@ var variableRx = regexp.MustCompile(`{{\s*\$(\w+)\s*}}`)
 
 func New() *Variables {
 	return &Variables{
-		variables: make(variables),
+		variables:   make(variables),
+		environment: NewEnvironmentSource(),
+		generators:  defaultGenerators(),
+		generated:   make(map[string]string),
 	}
 }
 
+// AddSource registers the source of variables which are not defined explicitly.
+// Sources are consulted in reverse order of registration, prior to the files and the environment.
+func (vs *Variables) AddSource(s Source) {
+	vs.sources = append([]Source{s}, vs.sources...)
+}
+
+// LoadFromFile reads the variables of the dotenv-style file, they override the environment
+// and are overridden by the variables of the tests, the file loaded later takes precedence
+func (vs *Variables) LoadFromFile(path string) error {
+	s, err := NewFileSource(path)
+	if err != nil {
+		return err
+	}
+	vs.files = append([]Source{s}, vs.files...)
+	return nil
+}
+
+// LoadEnvironment imports the current environment, so the later changes of the environment
+// are not seen by the tests. Until it is called the environment is read at the moment of use
+func (vs *Variables) LoadEnvironment() {
+	vs.environment = newEnvironmentSnapshot()
+}
+
 // Load adds new variables and replaces values of existing
 func (vs *Variables) Load(variables map[string]string) {
 	for n, v := range variables {
@@ -36,6 +71,13 @@ func (vs *Variables) Set(name, value string) {
 	vs.variables[name] = v
 }
 
+// IsSet returns true if the variable is set explicitly rather than found in the sources
+func (vs *Variables) IsSet(name string) bool {
+	_, ok := vs.variables[name]
+	return ok
+}
+
+// Apply replaces the variables of the test, the generators give new values for every applied test
 func (vs *Variables) Apply(t models.TestInterface) models.TestInterface {
 
 	newTest := t.Clone()
@@ -44,13 +86,30 @@ func (vs *Variables) Apply(t models.TestInterface) models.TestInterface {
 		return newTest
 	}
 
+	vs.generated = make(map[string]string)
+
 	newTest.SetQuery(vs.perform(newTest.ToQuery()))
+	newTest.SetQueryParams(vs.performQueryParams(newTest.GetQueryParams()))
 	newTest.SetMethod(vs.perform(newTest.GetMethod()))
 	newTest.SetPath(vs.perform(newTest.Path()))
 	newTest.SetRequest(vs.perform(newTest.GetRequest()))
 
 	newTest.SetResponses(vs.performResponses(newTest.GetResponses()))
+	if newTest.GetResponseMergeBases() != nil {
+		newTest.SetResponseMergeBases(vs.performResponses(newTest.GetResponseMergeBases()))
+	}
+	if newTest.GetResponseNDJSONLines() != nil {
+		newTest.SetResponseNDJSONLines(vs.performResponseLines(newTest.GetResponseNDJSONLines()))
+	}
+	if newTest.GetResponseBodyPatterns() != nil {
+		newTest.SetResponseBodyPatterns(vs.performResponses(newTest.GetResponseBodyPatterns()))
+	}
 	newTest.SetHeaders(vs.performHeaders(newTest.Headers()))
+	if newTest.ServiceMocks() != nil {
+		newTest.SetServiceMocks(vs.performMocks(newTest.ServiceMocks()))
+	}
+	newTest.SetDbQueryString(vs.perform(newTest.DbQueryString()))
+	newTest.SetDbResponseJson(vs.performList(newTest.DbResponseJson()))
 
 	return newTest
 }
@@ -87,17 +146,33 @@ func (vs *Variables) perform(str string) string {
 		}
 	}
 
-	return str
+	return vs.performGeneratorCalls(str)
 }
 
 func (vs *Variables) get(name string) *Variable {
 
-	v := vs.variables[name]
-	if v == nil {
-		v = NewFromEnvironment(name)
+	if v := vs.variables[name]; v != nil {
+		return v
+	}
+
+	for _, s := range vs.sources {
+		if val, ok := s.Get(name); ok {
+			return NewVariable(name, val)
+		}
+	}
+	for _, s := range vs.files {
+		if val, ok := s.Get(name); ok {
+			return NewVariable(name, val)
+		}
+	}
+	if val, ok := vs.environment.Get(name); ok {
+		return NewVariable(name, val)
+	}
+	if val, ok := vs.generate(name, nil); ok {
+		return NewVariable(name, val)
 	}
 
-	return v
+	return nil
 }
 
 func (vs *Variables) performHeaders(headers map[string]string) map[string]string {
@@ -110,6 +185,23 @@ func (vs *Variables) performHeaders(headers map[string]string) map[string]string
 	return res
 }
 
+func (vs *Variables) performQueryParams(params map[string][]string) map[string][]string {
+	if params == nil {
+		return nil
+	}
+
+	res := make(map[string][]string)
+
+	for k, values := range params {
+		performed := make([]string, len(values))
+		for i, v := range values {
+			performed[i] = vs.perform(v)
+		}
+		res[k] = performed
+	}
+	return res
+}
+
 func (vs *Variables) performResponses(responses map[int]string) map[int]string {
 
 	res := make(map[int]string)
@@ -120,6 +212,55 @@ func (vs *Variables) performResponses(responses map[int]string) map[int]string {
 	return res
 }
 
+func (vs *Variables) performResponseLines(responses map[int][]string) map[int][]string {
+	res := make(map[int][]string, len(responses))
+	for k, v := range responses {
+		res[k] = vs.performList(v)
+	}
+	return res
+}
+
+func (vs *Variables) performList(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	res := make([]string, len(list))
+	for i, v := range list {
+		res[i] = vs.perform(v)
+	}
+	return res
+}
+
+func (vs *Variables) performMocks(mocks map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(mocks))
+	for k, v := range mocks {
+		res[k] = vs.performValue(v)
+	}
+	return res
+}
+
+// performValue replaces the variables in the strings and the keys of the YAML value,
+// the value itself is kept unchanged
+func (vs *Variables) performValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return vs.perform(value)
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(value))
+		for k, v := range value {
+			res[vs.performValue(k)] = vs.performValue(v)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, v := range value {
+			res[i] = vs.performValue(v)
+		}
+		return res
+	}
+	return value
+}
+
 func (vs *Variables) Add(v *Variable) *Variables {
 	vs.variables[v.name] = v
 
@@ -0,0 +1,70 @@
+package variables
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapSource map[string]string
+
+func (s mapSource) Get(name string) (string, bool) {
+	val, ok := s[name]
+	return val, ok
+}
+
+func TestSourcesPrecedence(t *testing.T) {
+	os.Setenv("GONKEY_TEST_ENV_VAR", "from_env")
+	os.Setenv("GONKEY_TEST_SOURCE_VAR", "from_env")
+	defer os.Unsetenv("GONKEY_TEST_ENV_VAR")
+	defer os.Unsetenv("GONKEY_TEST_SOURCE_VAR")
+
+	vars := New()
+	vars.AddSource(mapSource{
+		"GONKEY_TEST_SOURCE_VAR": "from_first_source",
+		"explicitVar":            "from_first_source",
+	})
+	vars.AddSource(mapSource{
+		"GONKEY_TEST_SOURCE_VAR": "from_second_source",
+	})
+	vars.Set("explicitVar", "explicit")
+
+	assert.Equal(t, "from_env", vars.perform("{{ $GONKEY_TEST_ENV_VAR }}"))
+	assert.Equal(t, "from_second_source", vars.perform("{{ $GONKEY_TEST_SOURCE_VAR }}"))
+	assert.Equal(t, "explicit", vars.perform("{{ $explicitVar }}"))
+	assert.Equal(t, "{{ $unknownVar }}", vars.perform("{{ $unknownVar }}"))
+}
+
+func TestFilePrecedence(t *testing.T) {
+	os.Setenv("GONKEY_TEST_ENV_VAR", "from_env")
+	defer os.Unsetenv("GONKEY_TEST_ENV_VAR")
+
+	vars := New()
+	require.NoError(t, vars.LoadFromFile("testdata/vars.env"))
+	vars.Set("explicitVar", "explicit")
+
+	assert.Equal(t, "from_file", vars.perform("{{ $GONKEY_TEST_FILE_VAR }}"))
+	assert.Equal(t, "from_file", vars.perform("{{ $GONKEY_TEST_ENV_VAR }}"))
+	assert.Equal(t, "explicit", vars.perform("{{ $explicitVar }}"))
+
+	vars.AddSource(mapSource{"GONKEY_TEST_FILE_VAR": "from_source"})
+	assert.Equal(t, "from_source", vars.perform("{{ $GONKEY_TEST_FILE_VAR }}"))
+}
+
+func TestLoadFromFileShouldFailOnMissingFile(t *testing.T) {
+	err := New().LoadFromFile("testdata/missing.env")
+	assert.Error(t, err)
+}
+
+func TestLoadEnvironmentShouldImportEnvironment(t *testing.T) {
+	os.Setenv("GONKEY_TEST_ENV_VAR", "from_env")
+	defer os.Unsetenv("GONKEY_TEST_ENV_VAR")
+
+	vars := New()
+	vars.LoadEnvironment()
+	os.Setenv("GONKEY_TEST_ENV_VAR", "changed")
+
+	assert.Equal(t, "from_env", vars.perform("{{ $GONKEY_TEST_ENV_VAR }}"))
+}